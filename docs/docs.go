@@ -15,42 +15,21 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/ping": {
-            "get": {
-                "description": "Vérifie si Firebase et Redis sont accessibles et retourne leur état",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "HealthCheck"
-                ],
-                "summary": "Vérifier la disponibilité des services",
-                "responses": {
-                    "200": {
-                        "description": "Statut des services",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                }
-            }
-        },
-        "/quiz": {
+        "/admin/auth/cache": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retourne la liste des quiz créés par l'utilisateur authentifié",
+                "description": "Retourne le nombre de vérifications de tokens servies par le cache (hits) ou par le fournisseur d'identité (misses) depuis le démarrage. Réservé aux administrateurs.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Quizzes"
+                    "Admin"
                 ],
-                "summary": "Récupérer tous mes quiz",
+                "summary": "Statistiques du cache d'authentification",
                 "parameters": [
                     {
                         "type": "string",
@@ -63,9 +42,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Liste des quiz de l'utilisateur",
+                        "description": "Compteurs du cache",
                         "schema": {
-                            "$ref": "#/definitions/quizzes.UserQuizzesResponse"
+                            "$ref": "#/definitions/auth.CacheStats"
                         }
                     },
                     "401": {
@@ -74,31 +53,30 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Erreur interne du serveur",
+                    "403": {
+                        "description": "Utilisateur non administrateur",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/keyspace": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Permet à l'utilisateur authentifié de créer un quiz",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retourne les codes d'exécution et salles présents dans Redis (SCAN). Réservé aux administrateurs.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Quizzes"
+                    "Admin"
                 ],
-                "summary": "Créer un quiz",
+                "summary": "Lister les codes et salles actifs",
                 "parameters": [
                     {
                         "type": "string",
@@ -107,32 +85,23 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "description": "Informations du quiz à créer",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/quizzes.CreateQuizRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Quiz créé avec succès",
+                    "200": {
+                        "description": "Codes et salles actifs",
                         "schema": {
-                            "$ref": "#/definitions/quizzes.Quiz"
+                            "$ref": "#/definitions/quizzes.Keyspace"
                         }
                     },
-                    "400": {
-                        "description": "Requête invalide",
+                    "401": {
+                        "description": "Utilisateur non authentifié",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "401": {
-                        "description": "Utilisateur non authentifié",
+                    "403": {
+                        "description": "Utilisateur non administrateur",
                         "schema": {
                             "type": "string"
                         }
@@ -142,25 +111,37 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "501": {
+                        "description": "Non supporté par le résolveur configuré",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/quiz/{quiz-id}": {
+        "/admin/users": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retourne les informations d'un quiz appartenant à l'utilisateur authentifié",
+                "description": "Retourne tous les utilisateurs enregistrés. Réservé aux administrateurs.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Quizzes"
+                    "Admin"
                 ],
-                "summary": "Récupérer un quiz",
+                "summary": "Lister les utilisateurs",
                 "parameters": [
                     {
                         "type": "string",
@@ -169,20 +150,16 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "ID du quiz",
-                        "name": "quiz-id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Détails du quiz",
+                        "description": "Liste des utilisateurs",
                         "schema": {
-                            "$ref": "#/definitions/quizzes.Quiz"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/users.User"
+                            }
                         }
                     },
                     "401": {
@@ -191,8 +168,8 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "Quiz non trouvé",
+                    "403": {
+                        "description": "Utilisateur non administrateur",
                         "schema": {
                             "type": "string"
                         }
@@ -202,26 +179,31 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/admin/users/{user-id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Met à jour un quiz existant en fonction des champs envoyés",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retourne les informations de n'importe quel utilisateur. Réservé aux administrateurs.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Quizzes"
+                    "Admin"
                 ],
-                "summary": "Modifier un quiz",
+                "summary": "Récupérer un utilisateur",
                 "parameters": [
                     {
                         "type": "string",
@@ -233,39 +215,33 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "ID du quiz",
-                        "name": "quiz-id",
+                        "description": "ID de l'utilisateur",
+                        "name": "user-id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Champs à modifier",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/quizzes.FieldPatchOp"
-                            }
-                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Quiz mis à jour avec succès",
+                    "200": {
+                        "description": "Informations de l'utilisateur",
+                        "schema": {
+                            "$ref": "#/definitions/users.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Requête invalide",
+                    "403": {
+                        "description": "Utilisateur non administrateur",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "401": {
-                        "description": "Utilisateur non authentifié",
+                    "404": {
+                        "description": "Utilisateur non trouvé",
                         "schema": {
                             "type": "string"
                         }
@@ -275,25 +251,31 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/quiz/{quiz-id}/questions": {
+        "/admin/users/{user-id}/quizzes": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retourne toutes les questions du quiz spécifié par son ID",
+                "description": "Retourne tous les quiz de n'importe quel utilisateur. Réservé aux administrateurs.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Quizzes"
+                    "Admin"
                 ],
-                "summary": "Récupérer les questions d'un quiz",
+                "summary": "Récupérer les quiz d'un utilisateur",
                 "parameters": [
                     {
                         "type": "string",
@@ -305,19 +287,19 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "ID du quiz",
-                        "name": "quiz-id",
+                        "description": "ID de l'utilisateur",
+                        "name": "user-id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Liste des questions du quiz",
+                        "description": "Liste des quiz de l'utilisateur",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/quizzes.Question"
+                                "$ref": "#/definitions/quizzes.Quiz"
                             }
                         }
                     },
@@ -327,8 +309,8 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "Quiz non trouvé",
+                    "403": {
+                        "description": "Utilisateur non administrateur",
                         "schema": {
                             "type": "string"
                         }
@@ -338,26 +320,31 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/users/{user-id}/quizzes/{quiz-id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Permet d'ajouter une question à un quiz existant",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retourne un quiz (questions et réponses comprises) de n'importe quel utilisateur. Réservé aux administrateurs.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Quizzes"
+                    "Admin"
                 ],
-                "summary": "Ajouter une question",
+                "summary": "Inspecter un quiz",
                 "parameters": [
                     {
                         "type": "string",
@@ -369,36 +356,40 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "ID du quiz",
-                        "name": "quiz-id",
+                        "description": "ID du propriétaire",
+                        "name": "user-id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Détails de la question",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/quizzes.CreateQuestionRequest"
-                        }
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Question ajoutée avec succès",
+                    "200": {
+                        "description": "Détails du quiz",
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.Quiz"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Requête invalide",
+                    "403": {
+                        "description": "Utilisateur non administrateur",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "401": {
-                        "description": "Utilisateur non authentifié",
+                    "404": {
+                        "description": "Quiz non trouvé",
                         "schema": {
                             "type": "string"
                         }
@@ -408,18 +399,24 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/quiz/{quiz-id}/questions/{question-id}": {
+        "/admin/users/{user-id}/roles": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Met à jour une question spécifique d'un quiz",
+                "description": "Remplace les rôles (admin, teacher, player) accordés à un utilisateur. Réservé aux administrateurs.",
                 "consumes": [
                     "application/json"
                 ],
@@ -427,9 +424,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Quizzes"
+                    "Admin"
                 ],
-                "summary": "Modifier une question",
+                "summary": "Modifier les rôles d'un utilisateur",
                 "parameters": [
                     {
                         "type": "string",
@@ -441,33 +438,26 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "ID du quiz",
-                        "name": "quiz-id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "ID de la question",
-                        "name": "question-id",
+                        "description": "ID de l'utilisateur",
+                        "name": "user-id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Mise à jour de la question",
+                        "description": "Rôles de l'utilisateur",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/quizzes.UpdateQuestionRequest"
+                            "$ref": "#/definitions/users.updateRolesRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Question mise à jour avec succès",
+                    "200": {
+                        "description": "Utilisateur mis à jour",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/users.User"
                         }
                     },
                     "400": {
@@ -482,8 +472,14 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Utilisateur non administrateur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "404": {
-                        "description": "Quiz ou question non trouvée",
+                        "description": "Utilisateur non trouvé",
                         "schema": {
                             "type": "string"
                         }
@@ -493,25 +489,31 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/quiz/{quiz-id}/start": {
-            "post": {
+        "/collections": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Démarre un quiz et retourne son code d'exécution",
+                "description": "Retourne toutes les collections de l'utilisateur authentifié, avec les identifiants de leurs quiz",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Quizzes"
+                    "Collections"
                 ],
-                "summary": "Démarrer un quiz",
+                "summary": "Récupérer mes collections",
                 "parameters": [
                     {
                         "type": "string",
@@ -520,26 +522,16 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "ID du quiz",
-                        "name": "quiz-id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Quiz démarré avec succès",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Quiz non prêt à être démarré",
+                    "200": {
+                        "description": "Liste des collections",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/quizzes.Collection"
+                            }
                         }
                     },
                     "401": {
@@ -548,29 +540,27 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "Quiz non trouvé",
+                    "500": {
+                        "description": "Erreur interne du serveur",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Erreur interne du serveur",
+                    "504": {
+                        "description": "Délai d'attente dépassé",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/users": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Cette route permet de créer un nouvel utilisateur à partir d'un username et de l'email récupéré via l'authentification",
+                "description": "Crée une collection vide. Contrairement aux dossiers, un même quiz peut faire partie de plusieurs collections.",
                 "consumes": [
                     "application/json"
                 ],
@@ -578,9 +568,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Collections"
                 ],
-                "summary": "Créer un utilisateur",
+                "summary": "Créer une collection",
                 "parameters": [
                     {
                         "type": "string",
@@ -591,24 +581,30 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Informations de l'utilisateur à créer",
+                        "description": "Nom et description",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/users.createUserRequest"
+                            "$ref": "#/definitions/quizzes.CreateCollectionRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Utilisateur créé avec succès",
+                        "description": "Collection créée",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/quizzes.Collection"
                         }
                     },
                     "400": {
-                        "description": "Requête invalide",
+                        "description": "Nom invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
                         "schema": {
                             "type": "string"
                         }
@@ -618,25 +614,31 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/users/me": {
+        "/collections/{collection-id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Cette route permet d'obtenir les informations du compte actuellement authentifié",
+                "description": "Retourne une collection avec ses quiz, dans l'ordre où ils ont été ajoutés",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Collections"
                 ],
-                "summary": "Récupérer les informations de l'utilisateur connecté",
+                "summary": "Récupérer une collection",
                 "parameters": [
                     {
                         "type": "string",
@@ -645,13 +647,236 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la collection",
+                        "name": "collection-id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Informations de l'utilisateur",
+                        "description": "Collection et ses quiz",
                         "schema": {
-                            "$ref": "#/definitions/users.User"
+                            "$ref": "#/definitions/quizzes.CollectionWithQuizzes"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Collection non trouvée",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprime une collection, ses quiz sont conservés",
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Supprimer une collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la collection",
+                        "name": "collection-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Collection supprimée",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Collection non trouvée",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renomme une collection ou modifie sa description",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Modifier une collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la collection",
+                        "name": "collection-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Champs à modifier",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.PatchCollectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Collection modifiée",
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Nom invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Collection non trouvée",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{collection-id}/quizzes/{quiz-id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ajoute un quiz de l'utilisateur authentifié à la fin d'une collection. Sans effet si le quiz en fait déjà partie.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Ajouter un quiz à une collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la collection",
+                        "name": "collection-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Collection modifiée",
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.Collection"
                         }
                     },
                     "401": {
@@ -660,49 +885,3886 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Collection ou quiz non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Erreur interne du serveur",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retire un quiz d'une collection, le quiz est conservé",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Retirer un quiz d'une collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la collection",
+                        "name": "collection-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Collection modifiée",
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.Collection"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Collection non trouvée",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/execution/{code}/guest": {
+            "post": {
+                "description": "Délivre un jeton invité de courte durée, valable uniquement pour rejoindre l'exécution correspondant au code. Ce jeton n'est jamais accepté sur les routes de gestion des quiz.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Executions"
+                ],
+                "summary": "Obtenir une identité invitée",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Code de l'exécution",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Identité invitée",
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.CreateGuestResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Exécution non trouvée",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/folders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne tous les dossiers de l'utilisateur authentifié, sous forme de liste à plat. L'arborescence se reconstruit à partir de parentId (vide pour les dossiers de premier niveau).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Folders"
+                ],
+                "summary": "Récupérer mes dossiers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Liste des dossiers",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/quizzes.Folder"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crée un dossier, éventuellement dans un dossier parent. Les dossiers peuvent être imbriqués sur 8 niveaux au plus.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Folders"
+                ],
+                "summary": "Créer un dossier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Nom et dossier parent",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.CreateFolderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Dossier créé",
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.Folder"
+                        }
+                    },
+                    "400": {
+                        "description": "Nom invalide, dossier parent inconnu ou imbrication trop profonde",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/folders/{folder-id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprime un dossier, qui doit au préalable être vidé de ses quiz et sous-dossiers.",
+                "tags": [
+                    "Folders"
+                ],
+                "summary": "Supprimer un dossier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du dossier",
+                        "name": "folder-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Dossier supprimé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Dossier non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Dossier non vide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renomme un dossier, ou le déplace avec tout son contenu dans un autre dossier (parentId vide pour le premier niveau). Un dossier ne peut pas être déplacé dans l'un de ses sous-dossiers.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Folders"
+                ],
+                "summary": "Modifier un dossier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du dossier",
+                        "name": "folder-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Champs à modifier",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.PatchFolderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dossier modifié",
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.Folder"
+                        }
+                    },
+                    "400": {
+                        "description": "Nom invalide ou déplacement impossible",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Dossier non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/library": {
+            "get": {
+                "description": "Recherche plein texte (titre, description, tags et questions) parmi les quiz publics, avec filtres par tags, catégorie et langue. Les facettes comptent tous les résultats, pas seulement la page retournée.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Library"
+                ],
+                "summary": "Rechercher dans la bibliothèque",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Texte recherché",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tags requis",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Catégorie",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Langue (ex. fr, en-US)",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "relevance",
+                            "popular",
+                            "rating"
+                        ],
+                        "type": "string",
+                        "default": "relevance",
+                        "description": "Tri : relevance (pertinence), popular (nombre d'étoiles) ou rating (note moyenne)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Numéro de page, à partir de 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Taille de page, 100 au maximum",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page de résultats",
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.SearchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/library/{quiz-id}/fork": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Copie un quiz de la bibliothèque d'un autre utilisateur dans les quiz de l'utilisateur authentifié. La copie est privée, ses questions et réponses reçoivent de nouveaux identifiants et forkedFrom indique le quiz d'origine, dont le compteur forks est incrémenté.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Library"
+                ],
+                "summary": "Copier un quiz de la bibliothèque",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Copie créée",
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.Quiz"
+                        }
+                    },
+                    "400": {
+                        "description": "Le quiz appartient déjà à l'utilisateur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Réservé aux enseignants",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiz absent de la bibliothèque",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/library/{quiz-id}/rating": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Attribue une note de 1 à 5 à un quiz de la bibliothèque, en remplaçant la note précédente de l'utilisateur authentifié. Un propriétaire ne peut pas noter ses propres quiz.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Library"
+                ],
+                "summary": "Noter un quiz",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note attribuée",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.RateQuizRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Note enregistrée",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Note invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiz non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retire la note attribuée à un quiz par l'utilisateur authentifié. Sans effet s'il ne l'avait pas noté.",
+                "tags": [
+                    "Library"
+                ],
+                "summary": "Retirer sa note",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Note retirée",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiz absent de la bibliothèque",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/library/{quiz-id}/star": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ajoute un quiz de la bibliothèque aux favoris de l'utilisateur authentifié. Sans effet si le quiz est déjà en favori.",
+                "tags": [
+                    "Library"
+                ],
+                "summary": "Ajouter un quiz aux favoris",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Quiz ajouté aux favoris",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiz non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retire un quiz des favoris de l'utilisateur authentifié. Sans effet si le quiz n'était pas en favori.",
+                "tags": [
+                    "Library"
+                ],
+                "summary": "Retirer un quiz des favoris",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Quiz retiré des favoris",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiz absent de la bibliothèque",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/livez": {
+            "get": {
+                "description": "Répond tant que le serveur traite des requêtes, sans contacter ses dépendances.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "HealthCheck"
+                ],
+                "summary": "Sonde de vivacité",
+                "responses": {
+                    "200": {
+                        "description": "Serveur en vie",
+                        "schema": {
+                            "$ref": "#/definitions/ping.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ping": {
+            "get": {
+                "description": "Contacte Firestore (lecture d'un document) et Redis (commande PING), et retourne l'état et la latence (en millisecondes) de chacun. Le statut global vaut OK, KO ou Partial.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "HealthCheck"
+                ],
+                "summary": "Vérifier la disponibilité des services",
+                "responses": {
+                    "200": {
+                        "description": "Statut des services",
+                        "schema": {
+                            "$ref": "#/definitions/ping.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne la liste des quiz créés par l'utilisateur authentifié, éventuellement limitée à un dossier",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Quizzes"
+                ],
+                "summary": "Récupérer tous mes quiz",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du dossier, ou root pour les quiz hors de tout dossier",
+                        "name": "folder",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Liste des quiz de l'utilisateur",
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.UserQuizzesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Dossier inconnu",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Permet à l'utilisateur authentifié de créer un quiz",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Quizzes"
+                ],
+                "summary": "Créer un quiz",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Informations du quiz à créer",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.CreateQuizRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Quiz créé avec succès",
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.Quiz"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/shared": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les quiz d'autres utilisateurs sur lesquels l'utilisateur authentifié est collaborateur, avec le rôle accordé (editor ou viewer)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Quizzes"
+                ],
+                "summary": "Récupérer les quiz partagés avec moi",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Liste des quiz partagés",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/quizzes.QuizAccess"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/ws": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Établit une connexion WebSocket pour interagir avec le quiz en temps réel",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "WebSocket"
+                ],
+                "summary": "Connexion WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Connexion WebSocket établie",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Mauvaise requête",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/{quiz-id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les informations d'un quiz appartenant à l'utilisateur authentifié, ou partagé avec lui",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Quizzes"
+                ],
+                "summary": "Récupérer un quiz",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Détails du quiz",
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.Quiz"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiz non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Met à jour un quiz existant en fonction des champs envoyés (opération replace sur /title, /description, /visibility : private, unlisted, public, /tags, /category ou /language)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Quizzes"
+                ],
+                "summary": "Modifier un quiz",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Champs à modifier",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/quizzes.FieldPatchOp"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Quiz mis à jour avec succès",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/{quiz-id}/collaborators": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les utilisateurs ayant accès au quiz, avec leur rôle",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Quizzes"
+                ],
+                "summary": "Récupérer les collaborateurs d'un quiz",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Liste des collaborateurs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/quizzes.Collaborator"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiz non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Accorde un rôle (editor ou viewer) sur le quiz à un utilisateur, désigné par son email ou son nom d'utilisateur. Réservé au propriétaire du quiz. Seuls les enseignants (et administrateurs) peuvent être invités. Un collaborateur déjà invité voit son rôle remplacé.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Quizzes"
+                ],
+                "summary": "Inviter un collaborateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Utilisateur invité et rôle accordé",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.InviteCollaboratorRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Collaborateur ajouté",
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.Collaborator"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide, ou utilisateur invité qui n'est pas enseignant",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Réservé au propriétaire du quiz",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiz ou utilisateur non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/{quiz-id}/collaborators/{user-id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Révoque l'accès d'un collaborateur au quiz. Réservé au propriétaire, un collaborateur peut toutefois se retirer lui-même.",
+                "tags": [
+                    "Quizzes"
+                ],
+                "summary": "Retirer un collaborateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du collaborateur",
+                        "name": "user-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Collaborateur retiré",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Réservé au propriétaire du quiz",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiz ou collaborateur non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/{quiz-id}/executions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne toutes les exécutions (en cours ou terminées) du quiz spécifié, des plus récentes aux plus anciennes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Quizzes"
+                ],
+                "summary": "Récupérer les exécutions d'un quiz",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Liste des exécutions du quiz",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/quizzes.Execution"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiz non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/{quiz-id}/executions/{execution-id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne l'état (code, statut, question courante) d'une exécution du quiz",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Quizzes"
+                ],
+                "summary": "Récupérer une exécution",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de l'exécution",
+                        "name": "execution-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Détails de l'exécution",
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.Execution"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiz ou exécution non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/{quiz-id}/executions/{execution-id}/analytics": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Calcule, pour chaque question du quiz, le pourcentage de bonnes réponses, la répartition des réponses, le temps de réponse médian (en millisecondes) et le nombre de participants ayant passé la question, à partir des réponses enregistrées de l'exécution.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Quizzes"
+                ],
+                "summary": "Statistiques d'une exécution",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de l'exécution",
+                        "name": "execution-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Statistiques par question",
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.ExecutionAnalytics"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiz ou exécution non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/{quiz-id}/executions/{execution-id}/results": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Exporte une ligne par participant : pseudo, score (nombre de bonnes réponses), rang, puis pour chaque question 1 si la réponse est correcte, 0 si elle est fausse, vide si la question a été passée. Le fichier est généré au fil de l'eau.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Quizzes"
+                ],
+                "summary": "Exporter les résultats d'une exécution",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de l'exécution",
+                        "name": "execution-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format du fichier : csv (par défaut) ou xlsx",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Résultats de l'exécution",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Format inconnu",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiz ou exécution non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/{quiz-id}/folder": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Déplace un quiz dans un dossier (folderId vide pour le sortir de tout dossier). Réservé au propriétaire du quiz.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Folders"
+                ],
+                "summary": "Déplacer un quiz",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dossier de destination",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.MoveQuizRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Quiz déplacé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Dossier inconnu",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Réservé au propriétaire du quiz",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiz non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/{quiz-id}/questions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne toutes les questions du quiz spécifié par son ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Quizzes"
+                ],
+                "summary": "Récupérer les questions d'un quiz",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Liste des questions du quiz",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/quizzes.Question"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiz non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Permet d'ajouter une question à un quiz existant",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Quizzes"
+                ],
+                "summary": "Ajouter une question",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Détails de la question",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.CreateQuestionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Question ajoutée avec succès",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/{quiz-id}/questions/{question-id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Met à jour une question spécifique d'un quiz",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Quizzes"
+                ],
+                "summary": "Modifier une question",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la question",
+                        "name": "question-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Mise à jour de la question",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.UpdateQuestionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Question mise à jour avec succès",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiz ou question non trouvée",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/{quiz-id}/start": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crée une nouvelle exécution du quiz, avec son propre code, et la retourne. Un même quiz peut avoir plusieurs exécutions en parallèle.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Quizzes"
+                ],
+                "summary": "Démarrer un quiz",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Exécution créée avec succès",
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.Execution"
+                        }
+                    },
+                    "400": {
+                        "description": "Quiz non prêt à être démarré",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiz non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Aucun code d'exécution disponible",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/quiz/{quiz-id}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Agrège toutes les exécutions terminées du quiz : nombre de sessions et de joueurs, score moyen et répartition des scores (en pourcentage de bonnes réponses, par tranches de 10 %), questions les plus difficiles et les plus faciles, durée moyenne d'une exécution (en millisecondes). Les statistiques sont mises à jour à la fin de chaque exécution.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Quizzes"
+                ],
+                "summary": "Statistiques d'un quiz",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Statistiques du quiz",
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.QuizStatsReport"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiz non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Contacte chaque dépendance, et répond 503 si l'une des dépendances requises est indisponible.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "HealthCheck"
+                ],
+                "summary": "Sonde de disponibilité",
+                "responses": {
+                    "200": {
+                        "description": "Dépendances requises disponibles",
+                        "schema": {
+                            "$ref": "#/definitions/ping.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Dépendance requise indisponible",
+                        "schema": {
+                            "$ref": "#/definitions/ping.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cette route permet de créer un nouvel utilisateur à partir d'un username et de l'email récupéré via l'authentification",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Créer un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Informations de l'utilisateur à créer",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/users.createUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Utilisateur créé avec succès",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Nom d'utilisateur déjà utilisé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cette route permet d'obtenir les informations du compte actuellement authentifié",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Récupérer les informations de l'utilisateur connecté",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Informations de l'utilisateur",
+                        "schema": {
+                            "$ref": "#/definitions/users.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur non enregistré",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprime définitivement le compte connecté et toutes ses données (quiz, questions, réponses, exécutions, jetons d'accès), puis révoque et supprime l'utilisateur Firebase.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Supprimer son compte",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Compte supprimé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Impossible avec un jeton d'accès personnel",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Modifie les champs fournis du profil (username, displayName, avatarUrl, locale), les autres sont conservés. Le nom d'utilisateur est unique, sans tenir compte de la casse.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Modifier le profil de l'utilisateur connecté",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Champs du profil à modifier",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/users.PatchUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Profil modifié",
+                        "schema": {
+                            "$ref": "#/definitions/users.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur non enregistré",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Nom d'utilisateur déjà utilisé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne une archive zip contenant le profil (profile.json), les jetons d'accès (tokens.json), les favoris et notes (reactions.json), l'historique des parties (history.json), et pour chaque quiz ses questions, réponses (quizzes/{quiz-id}/quiz.json) et exécutions (quizzes/{quiz-id}/executions.json). L'archive est transmise au fur et à mesure de sa lecture.",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Exporter ses données personnelles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Archive des données personnelles",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur non enregistré",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/favorites": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les quiz mis en favori par l'utilisateur authentifié, des plus récents aux plus anciens. Les quiz supprimés ou redevenus privés sont ignorés.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Récupérer mes favoris",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Liste des quiz favoris",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/quizzes.LibraryEntry"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les exécutions terminées auxquelles l'utilisateur authentifié a participé avec son compte, des plus récentes aux plus anciennes, avec le titre du quiz, la date, le score et le rang obtenus.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Récupérer mon historique",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Numéro de page, à partir de 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre d'entrées par page (20 par défaut, 100 au plus)",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page de l'historique",
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.HistoryPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Pagination invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retourne les jetons de l'utilisateur connecté, sans leur secret",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Lister les jetons d'accès personnels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Liste des jetons",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/users.AccessToken"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Impossible avec un jeton d'accès personnel",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crée un jeton d'accès révocable, limité aux scopes demandés (quizzes:read, quizzes:write, users:read, users:write). Le secret n'est retourné qu'une seule fois. Sans date d'expiration, le jeton expire après 90 jours (366 jours maximum).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Créer un jeton d'accès personnel",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Nom, scopes et expiration du jeton",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/users.CreateTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Jeton créé",
+                        "schema": {
+                            "$ref": "#/definitions/users.CreateTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Requête invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Impossible avec un jeton d'accès personnel",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/tokens/{token-id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Supprime le jeton, il ne pourra plus être utilisé",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Révoquer un jeton d'accès personnel",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cvotre_token\u003e",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du jeton",
+                        "name": "token-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Jeton révoqué",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Utilisateur non authentifié",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Impossible avec un jeton d'accès personnel",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Jeton non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}": {
+            "get": {
+                "description": "Retourne le profil public d'un utilisateur (sans email ni rôles). L'authentification est facultative.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Récupérer un profil public",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nom d'utilisateur (insensible à la casse)",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Profil public",
+                        "schema": {
+                            "$ref": "#/definitions/users.PublicUser"
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/quizzes": {
+            "get": {
+                "description": "Retourne les quiz publics d'un utilisateur. Les bonnes réponses (isCorrect) ne sont incluses que si l'appelant est le propriétaire. L'authentification est facultative.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Lister les quiz publics d'un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nom d'utilisateur (insensible à la casse)",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Quiz publics",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/quizzes.PublicQuiz"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Token d'authentification invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/quizzes/{quiz-id}": {
+            "get": {
+                "description": "Retourne un quiz public ou non répertorié (unlisted). Les bonnes réponses (isCorrect) ne sont incluses que si l'appelant est le propriétaire. L'authentification est facultative.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Récupérer un quiz partagé",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token d'authentification Bearer",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nom d'utilisateur (insensible à la casse)",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du quiz",
+                        "name": "quiz-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Quiz partagé",
+                        "schema": {
+                            "$ref": "#/definitions/quizzes.PublicQuiz"
+                        }
+                    },
+                    "401": {
+                        "description": "Token d'authentification invalide",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Utilisateur ou quiz non trouvé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur interne du serveur",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "504": {
+                        "description": "Délai d'attente dépassé",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "auth.CacheStats": {
+            "type": "object",
+            "properties": {
+                "hits": {
+                    "type": "integer"
+                },
+                "misses": {
+                    "type": "integer"
+                }
+            }
+        },
+        "auth.Role": {
+            "type": "string",
+            "enum": [
+                "admin",
+                "teacher",
+                "player",
+                "teacher"
+            ],
+            "x-enum-varnames": [
+                "RoleAdmin",
+                "RoleTeacher",
+                "RolePlayer",
+                "DefaultRole"
+            ]
+        },
+        "auth.Scope": {
+            "type": "string",
+            "enum": [
+                "quizzes:read",
+                "quizzes:write",
+                "users:read",
+                "users:write"
+            ],
+            "x-enum-varnames": [
+                "ScopeQuizzesRead",
+                "ScopeQuizzesWrite",
+                "ScopeUsersRead",
+                "ScopeUsersWrite"
+            ]
+        },
+        "ping.CheckResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "latency": {
+                    "description": "Latency of the probe, in milliseconds.",
+                    "type": "number"
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "ping.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "details": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/ping.CheckResult"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.ActiveCode": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "ref": {
+                    "$ref": "#/definitions/quizzes.ExecutionRef"
+                },
+                "ttl": {
+                    "type": "integer"
+                }
+            }
+        },
+        "quizzes.ActiveRoom": {
+            "type": "object",
+            "properties": {
+                "executionId": {
+                    "type": "string"
+                },
+                "participants": {
+                    "type": "integer"
+                }
+            }
+        },
+        "quizzes.Answer": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "isCorrect": {
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.AnswerStats": {
+            "type": "object",
+            "properties": {
+                "answerId": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "isCorrect": {
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.Collaborator": {
+            "type": "object",
+            "properties": {
+                "addedAt": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/quizzes.QuizRole"
+                },
+                "uid": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.Collection": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "quizIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "quizzes.CollectionWithQuizzes": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "quizIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "quizzes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/quizzes.Quiz"
+                    }
+                }
+            }
+        },
+        "quizzes.CreateCollectionRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.CreateFolderRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "parentId": {
+                    "description": "ParentId is empty for top-level folders.",
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.CreateGuestResponse": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "uid": {
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.CreateQuestionRequest": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/quizzes.Answer"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.CreateQuizRequest": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "visibility": {
+                    "description": "Visibility defaults to private.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/quizzes.Visibility"
+                        }
+                    ]
+                }
+            }
+        },
+        "quizzes.Execution": {
+            "type": "object",
+            "properties": {
+                "askedAt": {
+                    "description": "AskedAt is when each question was asked, by question id.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "code": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "cursor": {
+                    "type": "integer"
+                },
+                "endedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "quizId": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/quizzes.ExecutionStatus"
+                }
+            }
+        },
+        "quizzes.ExecutionAnalytics": {
+            "type": "object",
+            "properties": {
+                "executionId": {
+                    "type": "string"
+                },
+                "participants": {
+                    "type": "integer"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/quizzes.QuestionAnalytics"
+                    }
+                }
+            }
+        },
+        "quizzes.ExecutionRef": {
+            "type": "object",
+            "properties": {
+                "executionId": {
+                    "type": "string"
+                },
+                "ownerId": {
+                    "type": "string"
+                },
+                "quizId": {
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.ExecutionStatus": {
+            "type": "string",
+            "enum": [
+                "waiting",
+                "started",
+                "finished"
+            ],
+            "x-enum-varnames": [
+                "ExecutionWaiting",
+                "ExecutionStarted",
+                "ExecutionFinished"
+            ]
+        },
+        "quizzes.Facet": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.FieldPatchOp": {
+            "type": "object",
+            "properties": {
+                "op": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "value": {}
+            }
+        },
+        "quizzes.Folder": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parentId": {
+                    "description": "ParentId is RootFolder for top-level folders.",
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.ForkSource": {
+            "type": "object",
+            "properties": {
+                "forkedAt": {
+                    "type": "string"
+                },
+                "ownerId": {
+                    "type": "string"
+                },
+                "quizId": {
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.HistoryEntry": {
+            "type": "object",
+            "properties": {
+                "executionId": {
+                    "type": "string"
+                },
+                "maxScore": {
+                    "description": "MaxScore is the number of questions of the quiz when it was played.",
+                    "type": "integer"
+                },
+                "ownerId": {
+                    "type": "string"
+                },
+                "participants": {
+                    "type": "integer"
+                },
+                "playedAt": {
+                    "description": "PlayedAt is when the execution was started.",
+                    "type": "string"
+                },
+                "quizId": {
+                    "type": "string"
+                },
+                "quizTitle": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "quizzes.HistoryPage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/quizzes.HistoryEntry"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "quizzes.InviteCollaboratorRequest": {
+            "type": "object",
+            "required": [
+                "role",
+                "user"
+            ],
+            "properties": {
+                "role": {
+                    "$ref": "#/definitions/quizzes.QuizRole"
+                },
+                "user": {
+                    "description": "User is the email or username of the invited user.",
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.Keyspace": {
+            "type": "object",
+            "properties": {
+                "codes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/quizzes.ActiveCode"
+                    }
+                },
+                "rooms": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/quizzes.ActiveRoom"
+                    }
+                }
+            }
+        },
+        "quizzes.LibraryEntry": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "forks": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "ownerId": {
+                    "type": "string"
+                },
+                "questionCount": {
+                    "type": "integer"
+                },
+                "ratingAverage": {
+                    "type": "number"
+                },
+                "ratingCount": {
+                    "type": "integer"
+                },
+                "stars": {
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.Links": {
+            "type": "object",
+            "properties": {
+                "create": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.MoveQuizRequest": {
+            "type": "object",
+            "properties": {
+                "folderId": {
+                    "description": "FolderId is empty to move the quiz out of any folder.",
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.PatchCollectionRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.PatchFolderRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "parentId": {
+                    "description": "ParentId moves the folder, RootFolder (\"\") moves it to the top level.",
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.PublicAnswer": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "isCorrect": {
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.PublicQuestion": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/quizzes.PublicAnswer"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "quizzes.PublicQuiz": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "forkedFrom": {
+                    "description": "ForkedFrom is omitted unless the quiz was copied from another one.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/quizzes.ForkSource"
+                        }
+                    ]
+                },
+                "forks": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/quizzes.PublicQuestion"
+                    }
+                },
+                "ratingAverage": {
+                    "description": "RatingAverage is 0 if the quiz was never rated.",
+                    "type": "number"
+                },
+                "ratingCount": {
+                    "type": "integer"
+                },
+                "stars": {
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "visibility": {
+                    "$ref": "#/definitions/quizzes.Visibility"
                 }
             }
-        }
-    },
-    "definitions": {
-        "quizzes.Answer": {
+        },
+        "quizzes.Question": {
             "type": "object",
             "properties": {
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/quizzes.Answer"
+                    }
+                },
                 "id": {
                     "type": "string"
                 },
-                "isCorrect": {
-                    "type": "boolean"
-                },
                 "title": {
                     "type": "string"
                 }
             }
         },
-        "quizzes.CreateQuestionRequest": {
+        "quizzes.QuestionAnalytics": {
             "type": "object",
             "properties": {
+                "answered": {
+                    "type": "integer"
+                },
                 "answers": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/quizzes.Answer"
+                        "$ref": "#/definitions/quizzes.AnswerStats"
                     }
                 },
+                "asked": {
+                    "description": "Asked is false for questions the execution didn't reach, their counts are all 0.",
+                    "type": "boolean"
+                },
+                "correct": {
+                    "type": "integer"
+                },
+                "correctRate": {
+                    "description": "CorrectRate is the percentage of participants who answered correctly, skips included.",
+                    "type": "number"
+                },
+                "medianResponseTime": {
+                    "description": "MedianResponseTime of participants who answered, in milliseconds.",
+                    "type": "integer"
+                },
+                "participants": {
+                    "description": "Participants counts players who were there when the question was asked, or answered it.",
+                    "type": "integer"
+                },
+                "questionId": {
+                    "type": "string"
+                },
+                "skipped": {
+                    "description": "Skipped counts participants who skipped the question or didn't answer it.",
+                    "type": "integer"
+                },
                 "title": {
                     "type": "string"
                 }
             }
         },
-        "quizzes.CreateQuizRequest": {
+        "quizzes.QuestionRate": {
             "type": "object",
             "properties": {
-                "description": {
+                "correctRate": {
+                    "description": "CorrectRate is the percentage of participants who answered correctly, over every execution.",
+                    "type": "number"
+                },
+                "participants": {
+                    "type": "integer"
+                },
+                "questionId": {
                     "type": "string"
                 },
                 "title": {
@@ -710,92 +4772,274 @@ const docTemplate = `{
                 }
             }
         },
-        "quizzes.FieldPatchOp": {
+        "quizzes.Quiz": {
             "type": "object",
             "properties": {
-                "op": {
+                "category": {
+                    "description": "Category is a single lower-cased subject (e.g. \"history\").",
                     "type": "string"
                 },
-                "path": {
+                "description": {
                     "type": "string"
                 },
-                "value": {}
+                "folderId": {
+                    "description": "FolderId is the folder holding the quiz, RootFolder if it wasn't moved to any.",
+                    "type": "string"
+                },
+                "forkedFrom": {
+                    "description": "ForkedFrom is nil unless the quiz was copied from another one.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/quizzes.ForkSource"
+                        }
+                    ]
+                },
+                "forks": {
+                    "description": "Forks counts copies of the quiz made from the library, only updated through Store.InsertFork.",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "language": {
+                    "description": "Language of the quiz content, as a language tag (e.g. \"fr\", \"en-US\").",
+                    "type": "string"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/quizzes.Question"
+                    }
+                },
+                "ratingCount": {
+                    "type": "integer"
+                },
+                "ratingSum": {
+                    "type": "integer"
+                },
+                "stars": {
+                    "description": "Aggregates of reactions, only updated through Store.UpdateReaction.",
+                    "type": "integer"
+                },
+                "tags": {
+                    "description": "Tags are lower-cased keywords used to search the library.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "visibility": {
+                    "$ref": "#/definitions/quizzes.Visibility"
+                }
             }
         },
-        "quizzes.Links": {
+        "quizzes.QuizAccess": {
             "type": "object",
             "properties": {
-                "create": {
+                "ownerId": {
                     "type": "string"
                 },
-                "start": {
-                    "type": "string"
+                "quiz": {
+                    "$ref": "#/definitions/quizzes.Quiz"
+                },
+                "role": {
+                    "$ref": "#/definitions/quizzes.QuizRole"
                 }
             }
         },
-        "quizzes.Question": {
+        "quizzes.QuizRole": {
+            "type": "string",
+            "enum": [
+                "owner",
+                "editor",
+                "viewer"
+            ],
+            "x-enum-varnames": [
+                "QuizRoleOwner",
+                "QuizRoleEditor",
+                "QuizRoleViewer"
+            ]
+        },
+        "quizzes.QuizStatsReport": {
             "type": "object",
             "properties": {
-                "answers": {
+                "averageDuration": {
+                    "description": "AverageDuration of executions, from their first question to their end, in milliseconds.",
+                    "type": "integer"
+                },
+                "averageScore": {
+                    "description": "AverageScore is the average number of correct answers per player.",
+                    "type": "number"
+                },
+                "averageScoreRate": {
+                    "description": "AverageScoreRate is the average percentage of correct answers per player.",
+                    "type": "number"
+                },
+                "easiestQuestions": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/quizzes.Answer"
+                        "$ref": "#/definitions/quizzes.QuestionRate"
                     }
                 },
-                "id": {
-                    "type": "string"
+                "hardestQuestions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/quizzes.QuestionRate"
+                    }
                 },
-                "title": {
-                    "type": "string"
+                "players": {
+                    "type": "integer"
+                },
+                "scoreDistribution": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/quizzes.ScoreRange"
+                    }
+                },
+                "sessions": {
+                    "type": "integer"
                 }
             }
         },
-        "quizzes.Quiz": {
+        "quizzes.QuizWithLinks": {
             "type": "object",
             "properties": {
-                "code": {
+                "_links": {
+                    "$ref": "#/definitions/quizzes.Links"
+                },
+                "category": {
+                    "description": "Category is a single lower-cased subject (e.g. \"history\").",
                     "type": "string"
                 },
                 "description": {
                     "type": "string"
                 },
+                "folderId": {
+                    "description": "FolderId is the folder holding the quiz, RootFolder if it wasn't moved to any.",
+                    "type": "string"
+                },
+                "forkedFrom": {
+                    "description": "ForkedFrom is nil unless the quiz was copied from another one.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/quizzes.ForkSource"
+                        }
+                    ]
+                },
+                "forks": {
+                    "description": "Forks counts copies of the quiz made from the library, only updated through Store.InsertFork.",
+                    "type": "integer"
+                },
                 "id": {
                     "type": "string"
                 },
+                "language": {
+                    "description": "Language of the quiz content, as a language tag (e.g. \"fr\", \"en-US\").",
+                    "type": "string"
+                },
                 "questions": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/quizzes.Question"
                     }
                 },
+                "ratingCount": {
+                    "type": "integer"
+                },
+                "ratingSum": {
+                    "type": "integer"
+                },
+                "stars": {
+                    "description": "Aggregates of reactions, only updated through Store.UpdateReaction.",
+                    "type": "integer"
+                },
+                "tags": {
+                    "description": "Tags are lower-cased keywords used to search the library.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "title": {
                     "type": "string"
+                },
+                "visibility": {
+                    "$ref": "#/definitions/quizzes.Visibility"
                 }
             }
         },
-        "quizzes.QuizWithLinks": {
+        "quizzes.RateQuizRequest": {
             "type": "object",
+            "required": [
+                "rating"
+            ],
             "properties": {
-                "_links": {
-                    "$ref": "#/definitions/quizzes.Links"
+                "rating": {
+                    "description": "Rating from 1 to 5.",
+                    "type": "integer"
+                }
+            }
+        },
+        "quizzes.ScoreRange": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
                 },
-                "code": {
-                    "type": "string"
+                "from": {
+                    "type": "integer"
                 },
-                "description": {
-                    "type": "string"
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "quizzes.SearchFacets": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/quizzes.Facet"
+                    }
                 },
-                "id": {
-                    "type": "string"
+                "languages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/quizzes.Facet"
+                    }
                 },
-                "questions": {
+                "tags": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/quizzes.Question"
+                        "$ref": "#/definitions/quizzes.Facet"
+                    }
+                }
+            }
+        },
+        "quizzes.SearchResult": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/quizzes.LibraryEntry"
                     }
                 },
-                "title": {
-                    "type": "string"
+                "facets": {
+                    "$ref": "#/definitions/quizzes.SearchFacets"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
@@ -838,15 +5082,161 @@ const docTemplate = `{
                 }
             }
         },
+        "quizzes.Visibility": {
+            "type": "string",
+            "enum": [
+                "private",
+                "unlisted",
+                "public"
+            ],
+            "x-enum-varnames": [
+                "VisibilityPrivate",
+                "VisibilityUnlisted",
+                "VisibilityPublic"
+            ]
+        },
+        "users.AccessToken": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auth.Scope"
+                    }
+                }
+            }
+        },
+        "users.CreateTokenRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expiresAt": {
+                    "description": "ExpiresAt defaults to DefaultTokenLifetime from now.",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auth.Scope"
+                    }
+                }
+            }
+        },
+        "users.CreateTokenResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auth.Scope"
+                    }
+                },
+                "token": {
+                    "description": "Token is the secret, it is only returned once.",
+                    "type": "string"
+                }
+            }
+        },
+        "users.PatchUserRequest": {
+            "type": "object",
+            "properties": {
+                "avatarUrl": {
+                    "type": "string"
+                },
+                "displayName": {
+                    "type": "string"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "users.PublicUser": {
+            "type": "object",
+            "properties": {
+                "avatarUrl": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "displayName": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
         "users.User": {
             "type": "object",
             "properties": {
+                "avatarUrl": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "displayName": {
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
+                "locale": {
+                    "type": "string"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auth.Role"
+                    }
+                },
                 "uid": {
                     "type": "string"
                 },
+                "updatedAt": {
+                    "type": "string"
+                },
                 "username": {
                     "type": "string"
                 }
@@ -859,6 +5249,17 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "users.updateRolesRequest": {
+            "type": "object",
+            "properties": {
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auth.Role"
+                    }
+                }
+            }
         }
     }
 }`
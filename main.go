@@ -1,7 +1,18 @@
 package main
 
-import "quizzy.app/backend/quizzy"
+import (
+	"log"
+	"os"
+	"quizzy.app/backend/quizzy"
+)
 
 func main() {
+	if len(os.Args) > 1 {
+		if err := quizzy.RunCommand(os.Args[1:]); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+
 	quizzy.Run()
 }
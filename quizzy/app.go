@@ -8,9 +8,13 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 	_ "quizzy.app/backend/docs" // Import Swagger Docs
 
-	"log"
+	"fmt"
+	"log/slog"
+	"os"
 	"quizzy.app/backend/quizzy/auth"
 	"quizzy.app/backend/quizzy/cfg"
+	"quizzy.app/backend/quizzy/logging"
+	"quizzy.app/backend/quizzy/metrics"
 	"quizzy.app/backend/quizzy/ping"
 	"quizzy.app/backend/quizzy/quizzes"
 	"quizzy.app/backend/quizzy/services"
@@ -30,18 +34,26 @@ import (
 func Run() {
 	config := cfg.LoadCfgFromEnv()
 
+	// Every log (including the ones of the standard logger) is written by the configured logger.
+	logger, logErr := logging.NewLogger(os.Stderr, config.Log)
+	if logErr != nil {
+		fatal("failed to initialize logger", logErr)
+	}
+	slog.SetDefault(logger)
+
 	// Configure GIN execution mode (dev, test, production).
 	setGinMode(config.Env.AsString())
 
-	log.Printf("application running in %s mode.\n", config.Env)
+	slog.Info("application running", "env", config.Env)
 
-	// Initializing GIN engine.
-	engine := gin.Default()
+	// Initializing GIN engine, requests are logged by AccessLog.
+	engine := gin.New()
+	engine.Use(gin.Recovery(), logging.RequestId, logging.AccessLog, metrics.Middleware)
 	engine.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:4200"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept"},
-		ExposeHeaders:    []string{"Content-Length", "Location"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept", logging.HeaderRequestId},
+		ExposeHeaders:    []string{"Content-Length", "Location", logging.HeaderRequestId},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -51,22 +63,28 @@ func Run() {
 
 	fbs, fbsErr := services.ConfigureFirebase(config)
 	if fbsErr != nil {
-		log.Fatalf("failed to initialize firebase services: %s", fbsErr)
+		fatal("failed to initialize firebase services", fbsErr)
 	}
 
 	rc, rcErr := services.ConfigureRedis(config)
 	if rcErr != nil {
-		log.Fatalf("failed to initialize redis service: %s", rcErr)
+		fatal("failed to initialize redis service", rcErr)
 	}
 
 	setupModule(router, &fbs, rc, config)
 
 	// Running server...
 	if err := engine.Run(config.Addr); err != nil {
-		log.Fatalf("Failed to start server on %s: %s\n", config.Addr, err)
+		fatal("failed to start server on "+config.Addr, err)
 	}
 }
 
+// fatal logs the given error, and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
 func setGinMode(env string) {
 	switch env {
 	case cfg.EnvDevelopment:
@@ -82,10 +100,79 @@ func setGinMode(env string) {
 }
 
 func setupModule(rt *gin.RouterGroup, fbs *services.FirebaseServices, rc *redis.Client, conf cfg.AppConfig) {
-	ping.Configure(fbs, rc).ConfigureRouting(rt)
+	health := ping.Configure(fbs, rc, conf.Timeouts.Health)
+	health.ConfigureRouting(rt)
+	metrics.ConfigureRouting(rt, conf.MetricsToken)
+
+	authenticator, authErr := configureAuthenticator(fbs, conf)
+	if authErr != nil {
+		fatal("failed to initialize "+conf.AuthProvider+" authenticator", authErr)
+	}
+
+	// Only some identity providers (Firebase) let us remove identities of deleted accounts.
+	identities, _ := authenticator.(users.IdentityProvider)
+
+	// Verified tokens are cached, avoiding a round trip to the identity provider on every request.
+	var cached *auth.CachedAuthenticator
+	if cache, err := configureTokenCache(rc, conf); err != nil {
+		fatal("failed to initialize token cache", err)
+	} else if cache != nil {
+		cached = &auth.CachedAuthenticator{Next: authenticator, Cache: cache, RevocationInterval: conf.AuthCache.RevocationInterval}
+		authenticator = cached
+	}
 
-	secured := rt.Group("", auth.ProvideAuthenticator(&auth.FirebaseAuthenticator{Fbs: fbs}))
+	quizController := quizzes.Configure(fbs, rc, conf, auth.NewGuestIssuer(conf.GuestSecret, conf.GuestTokenTTL))
+	// The library is empty until its index is built.
+	health.Checks = append(health.Checks, ping.Check{Name: "library", Required: true, Probe: quizController.Indexer.Ready})
+	userController := users.Configure(fbs, conf, quizController.Service, identities)
+	if cached != nil {
+		userController.Sessions = cached
+	}
+	// Collaborators are invited by email or username, resolved by the users module.
+	quizController.Users = userController.Service
+
+	// Personal access tokens are checked before reaching the identity provider.
+	authenticator = userController.Authenticator(authenticator)
+	// Guests are accepted by the authenticator, but refused by RequireAuthenticated.
+	authenticator = &auth.GuestAuthenticator{Issuer: quizController.Guests, Next: authenticator}
+	// Roles are completed from user documents, remembered along with provisioned users.
+	authenticator = &auth.RoleAuthenticator{Next: authenticator, Source: userController.Provisioner}
+
+	// Users are registered on their first authenticated request.
+	secured := rt.Group("", auth.ProvideAuthenticator(authenticator), auth.ProvideProvisioner(userController.Provisioner))
+	userController.ConfigureRouting(secured)
+	quizController.ConfigureRouting(secured)
+
+	admin := secured.Group("/admin", auth.RequireAuthenticated, auth.RejectAccessTokens, auth.RequireRole(auth.RoleAdmin))
+	userController.ConfigureAdminRouting(admin)
+	quizController.ConfigureAdminRouting(admin)
+	if cached != nil {
+		cached.ConfigureAdminRouting(admin)
+	}
+}
 
-	users.Configure(fbs, conf).ConfigureRouting(secured)
-	quizzes.Configure(fbs, rc, conf).ConfigureRouting(secured)
+// configureTokenCache returns the configured verified tokens cache, nil if disabled.
+func configureTokenCache(rc *redis.Client, conf cfg.AppConfig) (auth.TokenCache, error) {
+	switch conf.AuthCache.Backend {
+	case cfg.AuthCacheNone:
+		return nil, nil
+	case cfg.AuthCacheMemory:
+		return auth.NewMemoryTokenCache(), nil
+	case cfg.AuthCacheRedis:
+		return auth.NewRedisTokenCache(rc, conf.RedisKeyPrefix, conf.Timeouts.Redis), nil
+	default:
+		return nil, fmt.Errorf("unknown auth cache backend %q", conf.AuthCache.Backend)
+	}
+}
+
+// configureAuthenticator returns the authenticator matching the configured identity provider.
+func configureAuthenticator(fbs *services.FirebaseServices, conf cfg.AppConfig) (auth.Authenticator, error) {
+	switch conf.AuthProvider {
+	case cfg.AuthProviderJwt:
+		return auth.NewJwtAuthenticator(conf.Jwt)
+	case cfg.AuthProviderFirebase:
+		return &auth.FirebaseAuthenticator{Fbs: fbs, Timeout: conf.Timeouts.Auth}, nil
+	default:
+		return nil, fmt.Errorf("unknown auth provider %q", conf.AuthProvider)
+	}
 }
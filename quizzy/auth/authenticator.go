@@ -1,11 +1,29 @@
 package auth
 
+import (
+	"context"
+	"time"
+)
+
 type Identity struct {
 	Token string `json:"-"`
 	Uid   string `json:"-"`
 	Email string `json:"-"`
+	// Guest is set for anonymous players, which are only allowed to play the execution
+	// matching ExecutionCode.
+	Guest         bool   `json:"-"`
+	ExecutionCode string `json:"-"`
+	// Roles granted to this identity, from the identity provider claims or our own user data.
+	Roles []Role `json:"-"`
+	// AccessTokenId is set when authenticated with a personal access token, which only
+	// grants the given Scopes.
+	AccessTokenId string  `json:"-"`
+	Scopes        []Scope `json:"-"`
+	// ExpiresAt is the expiry of the token this identity was authenticated with,
+	// zero when unknown.
+	ExpiresAt time.Time `json:"-"`
 }
 
 type Authenticator interface {
-	Authorize(token string) (Identity, error)
+	Authorize(ctx context.Context, token string) (Identity, error)
 }
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// TokenCache stores verified identities, keyed by the hash of their token.
+type TokenCache interface {
+	// Get returns the identity stored under the given key, if any.
+	Get(ctx context.Context, key string) (Identity, bool, error)
+
+	// Set stores the given identity under the given key, for the given duration.
+	Set(ctx context.Context, key string, id Identity, ttl time.Duration) error
+
+	// Invalidate drops every identity of the given user, e.g. once deleted.
+	Invalidate(ctx context.Context, uid string) error
+}
+
+// CacheStats counts lookups made against a token cache.
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// CachedAuthenticator remembers identities verified by the Next authenticator, avoiding a
+// network round trip on every request. An entry never outlives its token, nor the
+// RevocationInterval: a revoked token may still be accepted for that long.
+type CachedAuthenticator struct {
+	Next               Authenticator
+	Cache              TokenCache
+	RevocationInterval time.Duration
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// TokenKey returns the cache key of the given token, tokens themselves are never stored.
+func TokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (auth *CachedAuthenticator) Authorize(ctx context.Context, token string) (Identity, error) {
+	key := TokenKey(token)
+
+	// The cache is only an optimization, failing to use it must not fail authentication.
+	if id, ok, err := auth.Cache.Get(ctx, key); err != nil {
+		slog.WarnContext(ctx, "token cache lookup failed", "error", err)
+	} else if ok && (id.ExpiresAt.IsZero() || time.Now().Before(id.ExpiresAt)) {
+		auth.hits.Add(1)
+		id.Token = token
+		return id, nil
+	}
+
+	auth.misses.Add(1)
+	id, err := auth.Next.Authorize(ctx, token)
+	if err != nil {
+		return id, err
+	}
+
+	ttl := auth.RevocationInterval
+	if !id.ExpiresAt.IsZero() {
+		ttl = min(ttl, time.Until(id.ExpiresAt))
+	}
+
+	if ttl > 0 {
+		if err2 := auth.Cache.Set(ctx, key, id, ttl); err2 != nil {
+			slog.WarnContext(ctx, "token cache store failed", "error", err2)
+		}
+	}
+
+	return id, nil
+}
+
+// Invalidate forgets every verified token of the given user, they are verified again on their next use.
+func (auth *CachedAuthenticator) Invalidate(ctx context.Context, uid string) error {
+	return auth.Cache.Invalidate(ctx, uid)
+}
+
+// Stats returns cache hits and misses counted since startup.
+func (auth *CachedAuthenticator) Stats() CacheStats {
+	return CacheStats{Hits: auth.hits.Load(), Misses: auth.misses.Load()}
+}
+
+// ConfigureAdminRouting registers routes exposing cache counters, the given group must be
+// restricted to administrators.
+func (auth *CachedAuthenticator) ConfigureAdminRouting(admin *gin.RouterGroup) {
+	admin.GET("/auth/cache", auth.handleGetStats)
+}
+
+// handleGetStats retourne les compteurs du cache de vérification des tokens
+// @Summary Statistiques du cache d'authentification
+// @Description Retourne le nombre de vérifications de tokens servies par le cache (hits) ou par le fournisseur d'identité (misses) depuis le démarrage. Réservé aux administrateurs.
+// @Tags Admin
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Success 200 {object} CacheStats "Compteurs du cache"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 403 {string} string "Utilisateur non administrateur"
+// @Router /admin/auth/cache [get]
+// @Security BearerAuth
+func (auth *CachedAuthenticator) handleGetStats(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, auth.Stats())
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// countingAuthenticator counts verifications, and accepts any token except "invalid".
+type countingAuthenticator struct {
+	calls     int
+	expiresAt time.Time
+}
+
+func (c *countingAuthenticator) Authorize(ctx context.Context, token string) (Identity, error) {
+	c.calls++
+	if token == "invalid" {
+		return Identity{}, errors.New("invalid token")
+	}
+
+	return Identity{Token: token, Uid: "uid-" + token, Email: "me@mail.net", Roles: []Role{RoleTeacher}, ExpiresAt: c.expiresAt}, nil
+}
+
+func _testCachedAuthenticator(t *testing.T, cache TokenCache) {
+	next := &countingAuthenticator{expiresAt: time.Now().Add(time.Hour)}
+	ca := &CachedAuthenticator{Next: next, Cache: cache, RevocationInterval: time.Minute}
+
+	first, err := ca.Authorize(context.Background(), "a")
+	assert.Nil(t, err)
+	second, err2 := ca.Authorize(context.Background(), "a")
+	assert.Nil(t, err2)
+
+	assert.Equal(t, 1, next.calls)
+	assert.Equal(t, "a", second.Token)
+	assert.Equal(t, first.Uid, second.Uid)
+	assert.Equal(t, first.Roles, second.Roles)
+	assert.WithinDuration(t, first.ExpiresAt, second.ExpiresAt, time.Second)
+
+	_, err3 := ca.Authorize(context.Background(), "invalid")
+	assert.NotNil(t, err3)
+	_, _ = ca.Authorize(context.Background(), "invalid")
+	assert.Equal(t, 3, next.calls, "failed verifications must not be cached")
+
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 3}, ca.Stats())
+
+	// Tokens of an invalidated user are verified again, others are kept.
+	_, _ = ca.Authorize(context.Background(), "b")
+	assert.Nil(t, ca.Invalidate(context.Background(), first.Uid))
+	_, _ = ca.Authorize(context.Background(), "a")
+	_, _ = ca.Authorize(context.Background(), "b")
+	assert.Equal(t, 5, next.calls)
+}
+
+func TestCachedAuthenticatorMemory(t *testing.T) {
+	_testCachedAuthenticator(t, NewMemoryTokenCache())
+}
+
+func TestCachedAuthenticatorRedis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rc := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	_testCachedAuthenticator(t, NewRedisTokenCache(rc, "test", time.Second))
+
+	entry := "test:auth:" + TokenKey("a")
+	assert.True(t, mr.Exists(entry))
+	assert.LessOrEqual(t, mr.TTL(entry), time.Minute)
+	// Keys of a user's entries are indexed as long as they live.
+	members, err := mr.Members("test:auth:uid:uid-a")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{TokenKey("a")}, members)
+	assert.Equal(t, mr.TTL(entry), mr.TTL("test:auth:uid:uid-a"))
+}
+
+func TestCachedAuthenticatorBoundedByExpiry(t *testing.T) {
+	next := &countingAuthenticator{expiresAt: time.Now().Add(-time.Second)}
+	ca := &CachedAuthenticator{Next: next, Cache: NewMemoryTokenCache(), RevocationInterval: time.Minute}
+
+	_, _ = ca.Authorize(context.Background(), "a")
+	_, _ = ca.Authorize(context.Background(), "a")
+	assert.Equal(t, 2, next.calls, "expired tokens must not be cached")
+}
@@ -1,9 +1,11 @@
 package auth
 
+import "context"
+
 type DummyAuthenticator struct {
 	PlaceHolder Identity
 }
 
-func (d *DummyAuthenticator) Authorize(token string) (Identity, error) {
+func (d *DummyAuthenticator) Authorize(ctx context.Context, token string) (Identity, error) {
 	return d.PlaceHolder, nil
 }
@@ -2,21 +2,47 @@ package auth
 
 import (
 	"context"
+	fireauth "firebase.google.com/go/auth"
 	"quizzy.app/backend/quizzy/services"
+	"time"
 )
 
 type FirebaseAuthenticator struct {
-	Fbs *services.FirebaseServices
+	Fbs     *services.FirebaseServices
+	Timeout time.Duration
 }
 
-func (auth *FirebaseAuthenticator) Authorize(token string) (Identity, error) {
-	if tk, err := auth.Fbs.Auth.VerifyIDTokenAndCheckRevoked(context.Background(), token); err != nil {
+func (auth *FirebaseAuthenticator) Authorize(ctx context.Context, token string) (Identity, error) {
+	ctx, cancel := services.WithTimeout(ctx, auth.Timeout)
+	defer cancel()
+
+	if tk, err := auth.Fbs.Auth.VerifyIDTokenAndCheckRevoked(ctx, token); err != nil {
 		return Identity{}, err
 	} else {
 		return Identity{
 			Token: token,
 			Uid:   tk.UID,
 			Email: tk.Claims["email"].(string),
+			// Firebase custom claims are merged into token claims.
+			Roles:     rolesFromClaims(tk.Claims),
+			ExpiresAt: time.Unix(tk.Expires, 0),
 		}, err
 	}
 }
+
+// DeleteIdentity revokes every session of the given user, then removes it from Firebase.
+// An already removed user isn't considered as an error.
+func (auth *FirebaseAuthenticator) DeleteIdentity(ctx context.Context, uid string) error {
+	ctx, cancel := services.WithTimeout(ctx, auth.Timeout)
+	defer cancel()
+
+	if err := auth.Fbs.Auth.RevokeRefreshTokens(ctx, uid); err != nil && !fireauth.IsUserNotFound(err) {
+		return err
+	}
+
+	if err := auth.Fbs.Auth.DeleteUser(ctx, uid); err != nil && !fireauth.IsUserNotFound(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const (
+	// GuestTokenPrefix distinguishes guest tokens from identity provider ones.
+	GuestTokenPrefix = "qg_"
+	guestIssuer      = "quizzy:guest"
+	guestUidPrefix   = "guest:"
+)
+
+var ErrInvalidGuestToken = errors.New("invalid guest token")
+
+type guestClaims struct {
+	Code string `json:"code"`
+	jwt.RegisteredClaims
+}
+
+// GuestIssuer issues short-lived signed identities to anonymous players,
+// each one bound to a single execution code.
+type GuestIssuer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewGuestIssuer creates a GuestIssuer signing tokens with the given secret. If no secret
+// is given, a random one is generated: guest tokens won't survive a restart, and won't be
+// shared across instances.
+func NewGuestIssuer(secret string, ttl time.Duration) *GuestIssuer {
+	key := []byte(secret)
+	if len(key) == 0 {
+		slog.Warn("no guest secret configured, generating an ephemeral one")
+		key = make([]byte, 32)
+		_, _ = rand.Read(key)
+	}
+
+	return &GuestIssuer{secret: key, ttl: ttl}
+}
+
+// Issue returns a new guest token for the given execution code, and its matching Identity.
+func (g *GuestIssuer) Issue(code string) (string, Identity, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(g.ttl)
+	uid := guestUidPrefix + uuid.New().String()
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, guestClaims{
+		Code: code,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    guestIssuer,
+			Subject:   uid,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}).SignedString(g.secret)
+	if err != nil {
+		return "", Identity{}, expiresAt, err
+	}
+
+	token := GuestTokenPrefix + signed
+	return token, Identity{Token: token, Uid: uid, Guest: true, ExecutionCode: code, ExpiresAt: expiresAt}, expiresAt, nil
+}
+
+// Verify checks the given guest token, and returns its Identity.
+func (g *GuestIssuer) Verify(token string) (Identity, error) {
+	var claims guestClaims
+	_, err := jwt.ParseWithClaims(strings.TrimPrefix(token, GuestTokenPrefix), &claims, func(t *jwt.Token) (any, error) {
+		return g.secret, nil
+	}, jwt.WithValidMethods([]string{"HS256"}), jwt.WithIssuer(guestIssuer), jwt.WithExpirationRequired())
+
+	if err != nil {
+		return Identity{}, err
+	}
+
+	if !strings.HasPrefix(claims.Subject, guestUidPrefix) || len(claims.Code) == 0 {
+		return Identity{}, ErrInvalidGuestToken
+	}
+
+	return Identity{Token: token, Uid: claims.Subject, Guest: true, ExecutionCode: claims.Code, ExpiresAt: claims.ExpiresAt.Time}, nil
+}
+
+// GuestAuthenticator accepts guest tokens, any other token is handed to the Next authenticator.
+type GuestAuthenticator struct {
+	Issuer *GuestIssuer
+	Next   Authenticator
+}
+
+func (auth *GuestAuthenticator) Authorize(ctx context.Context, token string) (Identity, error) {
+	if strings.HasPrefix(token, GuestTokenPrefix) {
+		return auth.Issuer.Verify(token)
+	}
+
+	return auth.Next.Authorize(ctx, token)
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGuestIssuerRoundTrip(t *testing.T) {
+	issuer := NewGuestIssuer("secret", time.Hour)
+
+	token, issued, _, err := issuer.Issue("ABC234")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(token, GuestTokenPrefix))
+
+	id, err2 := issuer.Verify(token)
+	assert.Nil(t, err2)
+	assert.True(t, id.Guest)
+	assert.Equal(t, "ABC234", id.ExecutionCode)
+	assert.Equal(t, issued.Uid, id.Uid)
+}
+
+func TestGuestIssuerRejectsForeignTokens(t *testing.T) {
+	token, _, _, _ := NewGuestIssuer("other", time.Hour).Issue("ABC234")
+	_, err := NewGuestIssuer("secret", time.Hour).Verify(token)
+	assert.NotNil(t, err)
+
+	expired, _, _, _ := NewGuestIssuer("secret", -time.Minute).Issue("ABC234")
+	_, err2 := NewGuestIssuer("secret", time.Hour).Verify(expired)
+	assert.NotNil(t, err2)
+}
+
+func TestGuestAuthenticatorDelegates(t *testing.T) {
+	issuer := NewGuestIssuer("secret", time.Hour)
+	user := Identity{Uid: "user-1"}
+	authenticator := &GuestAuthenticator{Issuer: issuer, Next: &DummyAuthenticator{PlaceHolder: user}}
+
+	id, err := authenticator.Authorize(context.Background(), "provider-token")
+	assert.Nil(t, err)
+	assert.Equal(t, user, id)
+
+	token, _, _, _ := issuer.Issue("ABC234")
+	guest, err2 := authenticator.Authorize(context.Background(), token)
+	assert.Nil(t, err2)
+	assert.True(t, guest.Guest)
+}
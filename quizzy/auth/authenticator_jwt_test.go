@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"os"
+	"path/filepath"
+	"quizzy.app/backend/quizzy/cfg"
+	"testing"
+	"time"
+)
+
+func _claims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   "https://idp.test",
+		"aud":   "quizzy",
+		"sub":   "user-1",
+		"email": "user@mail.net",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func _b64(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+func _writeJwks(t *testing.T, keys ...jwk) string {
+	raw, _ := json.Marshal(jwks{Keys: keys})
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, raw, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestJwtAuthenticatorHS256(t *testing.T) {
+	auth, err := NewJwtAuthenticator(cfg.JwtConfig{Issuer: "https://idp.test", Audience: "quizzy", HmacSecret: "secret"})
+	assert.Nil(t, err)
+
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, _claims()).SignedString([]byte("secret"))
+	id, err2 := auth.Authorize(context.Background(), token)
+
+	assert.Nil(t, err2)
+	assert.Equal(t, "user-1", id.Uid)
+	assert.Equal(t, "user@mail.net", id.Email)
+	assert.Equal(t, token, id.Token)
+}
+
+func TestJwtAuthenticatorRejectsInvalidClaims(t *testing.T) {
+	auth, _ := NewJwtAuthenticator(cfg.JwtConfig{Issuer: "https://idp.test", Audience: "quizzy", HmacSecret: "secret"})
+
+	cases := map[string]func(c jwt.MapClaims){
+		"issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.test" },
+		"audience": func(c jwt.MapClaims) { c["aud"] = "other" },
+		"expired":  func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() },
+		"no exp":   func(c jwt.MapClaims) { delete(c, "exp") },
+		"no sub":   func(c jwt.MapClaims) { delete(c, "sub") },
+	}
+
+	for name, mutate := range cases {
+		claims := _claims()
+		mutate(claims)
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+
+		_, err := auth.Authorize(context.Background(), token)
+		assert.NotNil(t, err, name)
+	}
+
+	forged, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, _claims()).SignedString([]byte("not-the-secret"))
+	_, err := auth.Authorize(context.Background(), forged)
+	assert.NotNil(t, err)
+}
+
+func TestJwtAuthenticatorRS256(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	path := _writeJwks(t, jwk{Kty: "RSA", Kid: "rsa-1", N: _b64(key.N), E: _b64(big.NewInt(int64(key.E)))})
+
+	auth, err := NewJwtAuthenticator(cfg.JwtConfig{JwksFile: path})
+	assert.Nil(t, err)
+
+	tk := jwt.NewWithClaims(jwt.SigningMethodRS256, _claims())
+	tk.Header["kid"] = "rsa-1"
+	token, _ := tk.SignedString(key)
+
+	id, err2 := auth.Authorize(context.Background(), token)
+	assert.Nil(t, err2)
+	assert.Equal(t, "user-1", id.Uid)
+
+	// HS256 must not be accepted when no shared secret is configured (algorithm confusion).
+	hs, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, _claims()).SignedString([]byte(""))
+	_, err3 := auth.Authorize(context.Background(), hs)
+	assert.NotNil(t, err3)
+}
+
+func TestJwtAuthenticatorES256(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	path := _writeJwks(t, jwk{Kty: "EC", Kid: "ec-1", Crv: "P-256", X: _b64(key.X), Y: _b64(key.Y)})
+
+	auth, err := NewJwtAuthenticator(cfg.JwtConfig{JwksFile: path})
+	assert.Nil(t, err)
+
+	tk := jwt.NewWithClaims(jwt.SigningMethodES256, _claims())
+	tk.Header["kid"] = "ec-1"
+	token, _ := tk.SignedString(key)
+
+	id, err2 := auth.Authorize(context.Background(), token)
+	assert.Nil(t, err2)
+	assert.Equal(t, "user-1", id.Uid)
+
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tk2 := jwt.NewWithClaims(jwt.SigningMethodES256, _claims())
+	tk2.Header["kid"] = "ec-1"
+	forged, _ := tk2.SignedString(other)
+	_, err3 := auth.Authorize(context.Background(), forged)
+	assert.NotNil(t, err3)
+}
+
+func TestJwtAuthenticatorWithoutKeys(t *testing.T) {
+	_, err := NewJwtAuthenticator(cfg.JwtConfig{})
+	assert.ErrorIs(t, err, ErrNoVerificationKey)
+}
@@ -1,43 +1,145 @@
 package auth
 
 import (
+	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
-	"log"
+	"log/slog"
 	"net/http"
+	"quizzy.app/backend/quizzy/logging"
+	"quizzy.app/backend/quizzy/services"
 	"strings"
 )
 
+var (
+	// ErrIdentityDeleted is returned by provisioners refusing identities of deleted users.
+	ErrIdentityDeleted = errors.New("identity of a deleted user")
+)
+
 const (
 	KeyAuthenticator = "authenticator"
+	KeyProvisioner   = "provisioner"
 	KeyIdentity      = "identity"
 )
 
+// Provisioner makes sure data of registered identities (e.g. their user document) exists
+// before reaching handlers. Identities of deleted users are refused with ErrIdentityDeleted.
+type Provisioner interface {
+	Provision(ctx context.Context, id Identity) error
+}
+
 // RequireAuthenticated middleware perform authorization against for the current request.
 // If the given authorization isn't valid, this middleware will stop propagation, and immediately
 // abort request processing.
-// If authorization succeed, a new Identity will be injected in the current middleware chain.
+// If authorization succeed, a new Identity will be injected in the current middleware chain,
+// once provisioned by the Provisioner exposed with ProvideProvisioner (if any).
+// Guest identities are refused, they are only allowed on player routes (see RequirePlayer).
 func RequireAuthenticated(ctx *gin.Context) {
+	authenticate(ctx, false)
+}
+
+// RequirePlayer middleware behaves like RequireAuthenticated, but also accepts guest identities.
+// Handlers must check that a guest identity is bound to the execution it tries to play.
+func RequirePlayer(ctx *gin.Context) {
+	authenticate(ctx, true)
+}
+
+// OptionalAuthenticated middleware authenticates requests holding an authorization token
+// (like RequirePlayer), and lets anonymous requests through. Handlers must rely on TryUseIdentity.
+func OptionalAuthenticated(ctx *gin.Context) {
+	if len(ctx.GetHeader("Authorization")) == 0 {
+		ctx.Next()
+		return
+	}
+
+	authenticate(ctx, true)
+}
+
+func authenticate(ctx *gin.Context, allowGuests bool) {
 	token := strings.TrimSpace(strings.TrimLeft(ctx.GetHeader("Authorization"), "Bearer"))
 
 	if len(token) == 0 {
-		log.Println("missing authorization token")
+		slog.DebugContext(ctx.Request.Context(), "missing authorization token")
 		ctx.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
 
 	authenticator := UseAuthenticator(ctx)
-	if id, err := authenticator.Authorize(token); err != nil {
+	id, err := authenticator.Authorize(ctx.Request.Context(), token)
+	if services.IsTimeout(err) {
+		ctx.AbortWithStatus(http.StatusGatewayTimeout)
+		return
+	} else if err != nil {
 		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	// Everything logged from now on, up to the access log, is bound to the identity.
+	ctx.Request = ctx.Request.WithContext(logging.With(ctx.Request.Context(), slog.String(logging.AttrUid, id.Uid)))
+
+	if id.Guest && !allowGuests {
+		ctx.AbortWithStatus(http.StatusForbidden)
+	} else if err2 := provision(ctx, id); errors.Is(err2, ErrIdentityDeleted) {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+	} else if err2 != nil {
+		slog.ErrorContext(ctx.Request.Context(), "failed to provision user", "error", err2)
+		ctx.AbortWithStatus(services.HttpStatusOf(err2))
 	} else {
 		ctx.Set(KeyIdentity, id)
 		ctx.Next()
 	}
 }
 
+// provision runs the provisioner of the current middleware chain for registered identities.
+func provision(ctx *gin.Context, id Identity) error {
+	if p := TryUseProvisioner(ctx); p != nil && !id.Guest {
+		return p.Provision(ctx.Request.Context(), id)
+	}
+
+	return nil
+}
+
+// Identify authorizes the given token outside of the middleware chain (e.g. on WebSocket events),
+// registered identities are provisioned by the given provisioner, if any. Like RequirePlayer, guest
+// identities are accepted: callers must check what they are allowed to do.
+func Identify(ctx context.Context, authenticator Authenticator, provisioner Provisioner, token string) (Identity, error) {
+	id, err := authenticator.Authorize(ctx, token)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	if provisioner != nil && !id.Guest {
+		if err2 := provisioner.Provision(ctx, id); err2 != nil {
+			return Identity{}, err2
+		}
+	}
+
+	return id, nil
+}
+
+// RequireRole middleware only let identities granted any of the given roles through, others
+// are refused with 403. It must be placed after RequireAuthenticated or RequirePlayer.
+func RequireRole(roles ...Role) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !UseIdentity(ctx).HasRole(roles...) {
+			ctx.AbortWithStatus(http.StatusForbidden)
+		}
+	}
+}
+
 func UseIdentity(ctx *gin.Context) Identity {
 	return ctx.MustGet(KeyIdentity).(Identity)
 }
 
+// TryUseIdentity returns the identity of the current request, if it was authenticated.
+func TryUseIdentity(ctx *gin.Context) (Identity, bool) {
+	if id, ok := ctx.Get(KeyIdentity); ok {
+		return id.(Identity), true
+	}
+
+	return Identity{}, false
+}
+
 // ProvideAuthenticator expose the given authenticator to the current middleware chain.
 func ProvideAuthenticator(authenticator Authenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -45,6 +147,22 @@ func ProvideAuthenticator(authenticator Authenticator) gin.HandlerFunc {
 	}
 }
 
+// ProvideProvisioner expose the given provisioner to the current middleware chain.
+func ProvideProvisioner(provisioner Provisioner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(KeyProvisioner, provisioner)
+	}
+}
+
+// TryUseProvisioner returns the provisioner of the current middleware chain, nil if none was provided.
+func TryUseProvisioner(ctx *gin.Context) Provisioner {
+	if p, ok := ctx.Get(KeyProvisioner); ok {
+		return p.(Provisioner)
+	}
+
+	return nil
+}
+
 func UseAuthenticator(ctx *gin.Context) Authenticator {
 	return ctx.MustGet(KeyAuthenticator).(Authenticator)
 }
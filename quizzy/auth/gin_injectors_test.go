@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type funcProvisioner func(ctx context.Context, id Identity) error
+
+func (f funcProvisioner) Provision(ctx context.Context, id Identity) error {
+	return f(ctx, id)
+}
+
+func _configureProvisionedHandler(id Identity, p Provisioner) http.Handler {
+	eng := gin.New()
+	rt := eng.Group("", ProvideAuthenticator(&DummyAuthenticator{PlaceHolder: id}), ProvideProvisioner(p))
+	rt.GET("/private", RequireAuthenticated, func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	rt.GET("/play", RequirePlayer, func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	return eng
+}
+
+func TestRequireAuthenticatedProvisions(t *testing.T) {
+	provisioned := make([]string, 0)
+	handler := _configureProvisionedHandler(Identity{Uid: "uid"}, funcProvisioner(func(ctx context.Context, id Identity) error {
+		provisioned = append(provisioned, id.Uid)
+		return nil
+	}))
+
+	httpexpect.Default(t, "/").
+		GET("/private").
+		WithHeader("Authorization", "Bearer x").
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusOK)
+
+	assert.Equal(t, []string{"uid"}, provisioned)
+}
+
+func TestRequireAuthenticatedProvisionFailure(t *testing.T) {
+	handler := _configureProvisionedHandler(Identity{Uid: "uid"}, funcProvisioner(func(ctx context.Context, id Identity) error {
+		return errors.New("unavailable")
+	}))
+
+	httpexpect.Default(t, "/").
+		GET("/private").
+		WithHeader("Authorization", "Bearer x").
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusInternalServerError)
+}
+
+func TestGuestsAreNotProvisioned(t *testing.T) {
+	handler := _configureProvisionedHandler(Identity{Uid: "guest:1", Guest: true}, funcProvisioner(func(ctx context.Context, id Identity) error {
+		return errors.New("guests must not be provisioned")
+	}))
+
+	httpexpect.Default(t, "/").
+		GET("/play").
+		WithHeader("Authorization", "Bearer x").
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusOK)
+}
+
+func TestIdentifyRefusesDeletedIdentities(t *testing.T) {
+	deleted := funcProvisioner(func(ctx context.Context, id Identity) error {
+		return ErrIdentityDeleted
+	})
+
+	_, err := Identify(context.Background(), &DummyAuthenticator{PlaceHolder: Identity{Uid: "uid"}}, deleted, "x")
+	assert.ErrorIs(t, err, ErrIdentityDeleted)
+
+	// Guests aren't provisioned.
+	id, err := Identify(context.Background(), &DummyAuthenticator{PlaceHolder: Identity{Uid: "guest:1", Guest: true}}, deleted, "x")
+	assert.Nil(t, err)
+	assert.Equal(t, "guest:1", id.Uid)
+}
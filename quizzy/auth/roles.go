@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+type Role string
+
+const (
+	RoleAdmin   Role = "admin"
+	RoleTeacher Role = "teacher"
+	RolePlayer  Role = "player"
+)
+
+// DefaultRole is granted to registered users without any explicit role.
+const DefaultRole = RoleTeacher
+
+// HasRole reports whether the identity was granted any of the given roles.
+func (id Identity) HasRole(roles ...Role) bool {
+	for _, r := range roles {
+		if slices.Contains(id.Roles, r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rolesFromClaims extract roles from token claims, either a "roles" list or a single "role".
+func rolesFromClaims(claims map[string]any) []Role {
+	roles := make([]Role, 0)
+
+	if list, ok := claims["roles"].([]any); ok {
+		for _, r := range list {
+			if s, ok2 := r.(string); ok2 {
+				roles = append(roles, Role(s))
+			}
+		}
+	}
+
+	if s, ok := claims["role"].(string); ok {
+		roles = append(roles, Role(s))
+	}
+
+	return roles
+}
+
+// RoleSource provides roles stored alongside users (e.g. the user document).
+type RoleSource interface {
+	GetRoles(ctx context.Context, uid string) ([]Role, error)
+}
+
+// RoleAuthenticator completes identities returned by the Next authenticator with roles from
+// the given Source. Guests are always players, users without any role get DefaultRole.
+type RoleAuthenticator struct {
+	Next   Authenticator
+	Source RoleSource
+}
+
+func (auth *RoleAuthenticator) Authorize(ctx context.Context, token string) (Identity, error) {
+	id, err := auth.Next.Authorize(ctx, token)
+	if err != nil {
+		return id, err
+	}
+
+	if id.Guest {
+		id.Roles = []Role{RolePlayer}
+		return id, nil
+	}
+
+	stored, err2 := auth.Source.GetRoles(ctx, id.Uid)
+	if err2 != nil {
+		return Identity{}, err2
+	}
+
+	for _, r := range stored {
+		if !slices.Contains(id.Roles, r) {
+			id.Roles = append(id.Roles, r)
+		}
+	}
+
+	if len(id.Roles) == 0 {
+		id.Roles = []Role{DefaultRole}
+	}
+
+	return id, nil
+}
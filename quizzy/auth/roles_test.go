@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type staticRoleSource map[string][]Role
+
+func (s staticRoleSource) GetRoles(ctx context.Context, uid string) ([]Role, error) {
+	return s[uid], nil
+}
+
+func TestRolesFromClaims(t *testing.T) {
+	assert.Equal(t, []Role{RoleAdmin, RoleTeacher}, rolesFromClaims(map[string]any{"roles": []any{"admin", "teacher"}}))
+	assert.Equal(t, []Role{RolePlayer}, rolesFromClaims(map[string]any{"role": "player"}))
+	assert.Empty(t, rolesFromClaims(map[string]any{}))
+}
+
+func TestRoleAuthenticator(t *testing.T) {
+	source := staticRoleSource{"admin-uid": {RoleAdmin}}
+
+	ra := &RoleAuthenticator{Next: &DummyAuthenticator{PlaceHolder: Identity{Uid: "admin-uid", Roles: []Role{RoleTeacher}}}, Source: source}
+	id, err := ra.Authorize(context.Background(), "x")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []Role{RoleTeacher, RoleAdmin}, id.Roles)
+
+	ra.Next = &DummyAuthenticator{PlaceHolder: Identity{Uid: "someone"}}
+	id, err = ra.Authorize(context.Background(), "x")
+	require.NoError(t, err)
+	assert.Equal(t, []Role{DefaultRole}, id.Roles)
+
+	ra.Next = &DummyAuthenticator{PlaceHolder: Identity{Uid: "admin-uid", Guest: true}}
+	id, err = ra.Authorize(context.Background(), "x")
+	require.NoError(t, err)
+	assert.Equal(t, []Role{RolePlayer}, id.Roles)
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"slices"
+)
+
+// Scope restricts what a personal access token is allowed to do.
+type Scope string
+
+const (
+	ScopeQuizzesRead  Scope = "quizzes:read"
+	ScopeQuizzesWrite Scope = "quizzes:write"
+	ScopeUsersRead    Scope = "users:read"
+	ScopeUsersWrite   Scope = "users:write"
+)
+
+// Scopes lists every scope which can be granted to a personal access token.
+var Scopes = []Scope{ScopeQuizzesRead, ScopeQuizzesWrite, ScopeUsersRead, ScopeUsersWrite}
+
+// IsValid reports whether the scope is a known one.
+func (s Scope) IsValid() bool {
+	return slices.Contains(Scopes, s)
+}
+
+// IsAccessToken reports whether the identity was authenticated with a personal access token.
+func (id Identity) IsAccessToken() bool {
+	return id.AccessTokenId != ""
+}
+
+// HasScope reports whether the identity was granted the given scope. Identities which aren't
+// coming from a personal access token are never restricted.
+func (id Identity) HasScope(scope Scope) bool {
+	return !id.IsAccessToken() || slices.Contains(id.Scopes, scope)
+}
+
+// RequireScope middleware refuses with 403 personal access tokens which weren't granted
+// the read scope for safe methods (GET, HEAD), or the write scope for any other method.
+// It must be placed after RequireAuthenticated.
+func RequireScope(read Scope, write Scope) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		scope := write
+		if ctx.Request.Method == http.MethodGet || ctx.Request.Method == http.MethodHead {
+			scope = read
+		}
+
+		if !UseIdentity(ctx).HasScope(scope) {
+			ctx.AbortWithStatus(http.StatusForbidden)
+		}
+	}
+}
+
+// RejectAccessTokens middleware refuses with 403 identities authenticated with a personal
+// access token, to keep sensitive routes (e.g. token management) interactive only.
+func RejectAccessTokens(ctx *gin.Context) {
+	if UseIdentity(ctx).IsAccessToken() {
+		ctx.AbortWithStatus(http.StatusForbidden)
+	}
+}
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryCacheSize bounds entries of a MemoryTokenCache, expired ones are swept once reached.
+const memoryCacheSize = 10_000
+
+type memoryEntry struct {
+	id        Identity
+	expiresAt time.Time
+}
+
+// MemoryTokenCache is a TokenCache local to the current instance.
+type MemoryTokenCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	// Keys of entries, by uid.
+	keys map[string]map[string]struct{}
+}
+
+func NewMemoryTokenCache() *MemoryTokenCache {
+	return &MemoryTokenCache{entries: make(map[string]memoryEntry), keys: make(map[string]map[string]struct{})}
+}
+
+// remove drops the given entry, must be called with mu held.
+func (c *MemoryTokenCache) remove(key string) {
+	if entry, ok := c.entries[key]; ok {
+		delete(c.keys[entry.id.Uid], key)
+		if len(c.keys[entry.id.Uid]) == 0 {
+			delete(c.keys, entry.id.Uid)
+		}
+		delete(c.entries, key)
+	}
+}
+
+func (c *MemoryTokenCache) Get(ctx context.Context, key string) (Identity, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return Identity{}, false, nil
+	}
+
+	if !time.Now().Before(entry.expiresAt) {
+		c.remove(key)
+		return Identity{}, false, nil
+	}
+
+	return entry.id, true, nil
+}
+
+func (c *MemoryTokenCache) Set(ctx context.Context, key string, id Identity, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= memoryCacheSize {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				c.remove(k)
+			}
+		}
+
+		// Still full of valid entries, they will be verified again.
+		if len(c.entries) >= memoryCacheSize {
+			c.entries = make(map[string]memoryEntry)
+			c.keys = make(map[string]map[string]struct{})
+		}
+	}
+
+	// Tokens are not kept in memory longer than needed.
+	id.Token = ""
+	c.remove(key)
+	c.entries[key] = memoryEntry{id: id, expiresAt: now.Add(ttl)}
+	if c.keys[id.Uid] == nil {
+		c.keys[id.Uid] = make(map[string]struct{})
+	}
+	c.keys[id.Uid][key] = struct{}{}
+	return nil
+}
+
+func (c *MemoryTokenCache) Invalidate(ctx context.Context, uid string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.keys[uid] {
+		delete(c.entries, key)
+	}
+	delete(c.keys, uid)
+	return nil
+}
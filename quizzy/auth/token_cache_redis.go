@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"quizzy.app/backend/quizzy/services"
+	"strings"
+	"time"
+)
+
+// cachedIdentity is the serialized form of an Identity, the token itself is never stored.
+type cachedIdentity struct {
+	Uid           string    `json:"uid"`
+	Email         string    `json:"email,omitempty"`
+	Guest         bool      `json:"guest,omitempty"`
+	ExecutionCode string    `json:"executionCode,omitempty"`
+	Roles         []Role    `json:"roles,omitempty"`
+	AccessTokenId string    `json:"accessTokenId,omitempty"`
+	Scopes        []Scope   `json:"scopes,omitempty"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+}
+
+// RedisTokenCache is a TokenCache shared between instances, entries are stored
+// under "<prefix>:auth:<key>", and keys of a user's entries in the "<prefix>:auth:uid:<uid>" set.
+type RedisTokenCache struct {
+	client  *redis.Client
+	prefix  string
+	timeout time.Duration
+}
+
+func NewRedisTokenCache(client *redis.Client, prefix string, timeout time.Duration) *RedisTokenCache {
+	return &RedisTokenCache{client: client, prefix: prefix, timeout: timeout}
+}
+
+func (c *RedisTokenCache) key(key string) string {
+	return strings.Join([]string{c.prefix, "auth", key}, ":")
+}
+
+func (c *RedisTokenCache) uidKey(uid string) string {
+	return strings.Join([]string{c.prefix, "auth", "uid", uid}, ":")
+}
+
+func (c *RedisTokenCache) Get(ctx context.Context, key string) (Identity, bool, error) {
+	ctx, cancel := services.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	raw, err := c.client.Get(ctx, c.key(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return Identity{}, false, nil
+	} else if err != nil {
+		return Identity{}, false, err
+	}
+
+	var ci cachedIdentity
+	if err2 := json.Unmarshal(raw, &ci); err2 != nil {
+		return Identity{}, false, err2
+	}
+
+	return Identity{
+		Uid:           ci.Uid,
+		Email:         ci.Email,
+		Guest:         ci.Guest,
+		ExecutionCode: ci.ExecutionCode,
+		Roles:         ci.Roles,
+		AccessTokenId: ci.AccessTokenId,
+		Scopes:        ci.Scopes,
+		ExpiresAt:     ci.ExpiresAt,
+	}, true, nil
+}
+
+func (c *RedisTokenCache) Set(ctx context.Context, key string, id Identity, ttl time.Duration) error {
+	ctx, cancel := services.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	raw, err := json.Marshal(cachedIdentity{
+		Uid:           id.Uid,
+		Email:         id.Email,
+		Guest:         id.Guest,
+		ExecutionCode: id.ExecutionCode,
+		Roles:         id.Roles,
+		AccessTokenId: id.AccessTokenId,
+		Scopes:        id.Scopes,
+		ExpiresAt:     id.ExpiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	// The set of keys lives as long as the longest living entry of the user.
+	_, err2 := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, c.key(key), raw, ttl)
+		pipe.SAdd(ctx, c.uidKey(id.Uid), key)
+		pipe.ExpireNX(ctx, c.uidKey(id.Uid), ttl)
+		pipe.ExpireGT(ctx, c.uidKey(id.Uid), ttl)
+		return nil
+	})
+	return err2
+}
+
+func (c *RedisTokenCache) Invalidate(ctx context.Context, uid string) error {
+	ctx, cancel := services.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	keys, err := c.client.SMembers(ctx, c.uidKey(uid)).Result()
+	if err != nil {
+		return err
+	}
+
+	toDelete := []string{c.uidKey(uid)}
+	for _, key := range keys {
+		toDelete = append(toDelete, c.key(key))
+	}
+
+	return c.client.Del(ctx, toDelete...).Err()
+}
@@ -1,8 +1,10 @@
 package cfg
 
 import (
+	"log/slog"
 	"os"
 	"strings"
+	"time"
 )
 
 const (
@@ -11,6 +13,16 @@ const (
 	EnvTest        = "TEST"
 )
 
+const (
+	AuthProviderFirebase = "firebase"
+	AuthProviderJwt      = "jwt"
+)
+
+const (
+	LogFormatText = "text"
+	LogFormatJson = "json"
+)
+
 type Env string
 
 func (env Env) IsTest() bool {
@@ -34,6 +46,79 @@ type AppConfig struct {
 	BasePath string
 	// URI redis
 	RedisUri string
+	// Prefix of every key written to redis.
+	RedisKeyPrefix string
+	// Per-operation timeouts applied to external services calls.
+	Timeouts Timeouts
+	// Lifetime of an execution code, once elapsed the code is released.
+	CodeTTL time.Duration
+	// Identity provider used to authenticate users (firebase, jwt).
+	AuthProvider string
+	// Local JWT verification settings, used by the jwt provider.
+	Jwt JwtConfig
+	// Secret used to sign guest tokens.
+	GuestSecret string
+	// Lifetime of a guest token.
+	GuestTokenTTL time.Duration
+	// Verified tokens cache settings.
+	AuthCache AuthCacheConfig
+	// How long a provisioned user is remembered before checking its document again.
+	ProvisionCacheTTL time.Duration
+	// How often the in-process library index is rebuilt, catching up with other instances.
+	LibraryRebuildInterval time.Duration
+	// Application logs settings.
+	Log LogConfig
+	// Bearer token Prometheus scrapers must send to read metrics, metrics aren't served if empty.
+	MetricsToken string
+}
+
+// LogConfig describe how application logs are written.
+type LogConfig struct {
+	// Lowest level written (debug, info, warn, error).
+	Level string
+	// Output format (text, json).
+	Format string
+}
+
+// JwtConfig describe how JWTs issued by a third party identity provider (Keycloak, ...)
+// are verified locally.
+type JwtConfig struct {
+	// Expected "iss" claim, not checked if empty.
+	Issuer string
+	// Expected "aud" claim, not checked if empty.
+	Audience string
+	// Shared secret for HS256 signed tokens.
+	HmacSecret string
+	// Path to a JWKS file holding public keys for RS256 / ES256 signed tokens.
+	JwksFile string
+}
+
+const (
+	AuthCacheNone   = "none"
+	AuthCacheMemory = "memory"
+	AuthCacheRedis  = "redis"
+)
+
+// AuthCacheConfig describe how verified tokens are cached, to avoid verifying them
+// against the identity provider on every request.
+type AuthCacheConfig struct {
+	// Cache backend (none, memory, redis).
+	Backend string
+	// Longest time a token is trusted without checking whether it was revoked.
+	RevocationInterval time.Duration
+}
+
+// Timeouts describe how long a single operation against an external service
+// may last before being cancelled. A zero value disables the deadline.
+type Timeouts struct {
+	// Firestore read / write operation.
+	Firestore time.Duration
+	// Redis command.
+	Redis time.Duration
+	// Token verification.
+	Auth time.Duration
+	// Dependency probe of health checks.
+	Health time.Duration
 }
 
 // getEnvDefault returns environment variable matching to the given key if found,
@@ -46,6 +131,23 @@ func getEnvDefault(key, def string) string {
 	return def
 }
 
+// getEnvDuration returns environment variable matching to the given key parsed as a
+// time.Duration (e.g. "5s", "250ms"), if not found or invalid, the default value is returned.
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	v, f := os.LookupEnv(key)
+	if !f {
+		return def
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		slog.Warn("invalid duration, falling back to default", "key", key, "value", v, "default", def)
+		return def
+	}
+
+	return d
+}
+
 // LoadCfgFromEnv generate a new AppConfig from environment.
 func LoadCfgFromEnv() AppConfig {
 	env := strings.ToUpper(getEnvDefault("APP_ENV", EnvProduction))
@@ -66,5 +168,33 @@ func LoadCfgFromEnv() AppConfig {
 		FirebaseConfFile: os.Getenv("APP_FIREBASE_CONF_FILE"),
 		BasePath:         getEnvDefault("APP_BASE_PATH", "/"),
 		RedisUri:         os.Getenv("APP_REDIS_URI"),
+		RedisKeyPrefix:   getEnvDefault("APP_REDIS_KEY_PREFIX", "quizzy"),
+		Timeouts: Timeouts{
+			Firestore: getEnvDuration("APP_FIRESTORE_TIMEOUT", 5*time.Second),
+			Redis:     getEnvDuration("APP_REDIS_TIMEOUT", 2*time.Second),
+			Auth:      getEnvDuration("APP_AUTH_TIMEOUT", 5*time.Second),
+			Health:    getEnvDuration("APP_HEALTH_TIMEOUT", time.Second),
+		},
+		CodeTTL:      getEnvDuration("APP_CODE_TTL", 4*time.Hour),
+		AuthProvider: strings.ToLower(getEnvDefault("APP_AUTH_PROVIDER", AuthProviderFirebase)),
+		Jwt: JwtConfig{
+			Issuer:     os.Getenv("APP_JWT_ISSUER"),
+			Audience:   os.Getenv("APP_JWT_AUDIENCE"),
+			HmacSecret: os.Getenv("APP_JWT_HMAC_SECRET"),
+			JwksFile:   os.Getenv("APP_JWT_JWKS_FILE"),
+		},
+		GuestSecret:   os.Getenv("APP_GUEST_SECRET"),
+		GuestTokenTTL: getEnvDuration("APP_GUEST_TOKEN_TTL", 3*time.Hour),
+		AuthCache: AuthCacheConfig{
+			Backend:            strings.ToLower(getEnvDefault("APP_AUTH_CACHE", AuthCacheMemory)),
+			RevocationInterval: getEnvDuration("APP_AUTH_REVOCATION_INTERVAL", time.Minute),
+		},
+		ProvisionCacheTTL:      getEnvDuration("APP_PROVISION_CACHE_TTL", 10*time.Minute),
+		LibraryRebuildInterval: getEnvDuration("APP_LIBRARY_REBUILD_INTERVAL", 5*time.Minute),
+		Log: LogConfig{
+			Level:  strings.ToLower(getEnvDefault("APP_LOG_LEVEL", "info")),
+			Format: strings.ToLower(getEnvDefault("APP_LOG_FORMAT", LogFormatText)),
+		},
+		MetricsToken: os.Getenv("APP_METRICS_TOKEN"),
 	}
 }
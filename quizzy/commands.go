@@ -0,0 +1,85 @@
+package quizzy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"quizzy.app/backend/quizzy/cfg"
+	"quizzy.app/backend/quizzy/quizzes"
+	"quizzy.app/backend/quizzy/services"
+	"quizzy.app/backend/quizzy/users"
+	"text/tabwriter"
+)
+
+var ErrUnknownCommand = errors.New("unknown command: available commands are redis:keys, redis:migrate, users:backfill-usernames")
+
+// RunCommand executes the given administration command instead of serving the API.
+//
+//	redis:keys                lists active codes and rooms
+//	redis:migrate             moves keys written before the keyspace was namespaced under the configured prefix,
+//	                          codes bound to quizzes get a new execution stored in Firestore
+//	users:backfill-usernames  reserves usernames of users registered before usernames were reserved
+func RunCommand(args []string) error {
+	config := cfg.LoadCfgFromEnv()
+	ctx := context.Background()
+
+	switch args[0] {
+	case "redis:keys", "redis:migrate":
+		return runRedisCommand(ctx, config, args[0])
+	case "users:backfill-usernames":
+		fbs, err := services.ConfigureFirebase(config)
+		if err != nil {
+			return err
+		}
+		defer fbs.Store.Close()
+
+		n, conflicts, err2 := users.BackfillUsernames(ctx, users.NewFirestore(fbs.Store, config.Timeouts.Firestore))
+		fmt.Printf("%d username(s) reserved\n", n)
+		for _, user := range conflicts {
+			fmt.Printf("username %q of user %s is already reserved by another user\n", user.Username, user.Id)
+		}
+		return err2
+	default:
+		return ErrUnknownCommand
+	}
+}
+
+func runRedisCommand(ctx context.Context, config cfg.AppConfig, command string) error {
+	rc, rcErr := services.ConfigureRedis(config)
+	if rcErr != nil {
+		return rcErr
+	}
+	defer rc.Close()
+
+	resolver := quizzes.NewRedisCodeResolver(rc, config)
+
+	switch command {
+	case "redis:keys":
+		ks, err := resolver.Keyspace(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "CODE\tEXECUTION\tQUIZ\tOWNER\tTTL")
+		for _, c := range ks.Codes {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Code, c.Ref.ExecutionId, c.Ref.QuizId, c.Ref.OwnerId, c.TTL)
+		}
+		_, _ = fmt.Fprintln(w, "\nROOM\tPARTICIPANTS")
+		for _, r := range ks.Rooms {
+			_, _ = fmt.Fprintf(w, "%s\t%d\n", r.ExecutionId, r.Participants)
+		}
+		return w.Flush()
+	default:
+		fbs, err := services.ConfigureFirebase(config)
+		if err != nil {
+			return err
+		}
+		defer fbs.Store.Close()
+
+		n, err2 := resolver.MigrateLegacyKeys(ctx, quizzes.ConfigureStore(fbs.Store, config.Timeouts.Firestore))
+		fmt.Printf("%d key(s) moved under prefix %q\n", n, config.RedisKeyPrefix)
+		return err2
+	}
+}
@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	HeaderRequestId = "X-Request-ID"
+	KeyRequestId    = "requestId"
+	// MaxRequestIdLength bounds request ids given by clients, longer ones are replaced.
+	MaxRequestIdLength = 128
+)
+
+// RequestId middleware identifies every request, reusing the id given by the client (or a
+// proxy) in the X-Request-ID header if any. The id is sent back in the same header, exposed
+// to handlers with UseRequestId, and written by every log call made with the request context.
+func RequestId(ctx *gin.Context) {
+	id := ctx.GetHeader(HeaderRequestId)
+	if len(id) == 0 || len(id) > MaxRequestIdLength {
+		id = uuid.New().String()
+	}
+
+	ctx.Set(KeyRequestId, id)
+	ctx.Header(HeaderRequestId, id)
+	ctx.Request = ctx.Request.WithContext(With(ctx.Request.Context(), slog.String(AttrRequestId, id)))
+	ctx.Next()
+}
+
+func UseRequestId(ctx *gin.Context) string {
+	return ctx.GetString(KeyRequestId)
+}
+
+// AccessLog middleware logs every handled request, once handled. It must be placed after
+// RequestId. Server errors are logged as errors.
+func AccessLog(ctx *gin.Context) {
+	start := time.Now()
+	ctx.Next()
+
+	level := slog.LevelInfo
+	if ctx.Writer.Status() >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+
+	// Handlers may have enriched the request context (e.g. with the authenticated uid).
+	slog.Log(ctx.Request.Context(), level, "request handled",
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.Request.URL.Path),
+		slog.String("route", ctx.FullPath()),
+		slog.Int("status", ctx.Writer.Status()),
+		slog.Duration("latency", time.Since(start)),
+		slog.String("clientIp", ctx.ClientIP()),
+	)
+}
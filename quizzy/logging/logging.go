@@ -0,0 +1,66 @@
+// Package logging configures structured application logs, and correlates them: attributes
+// attached to a context (request id, uid, execution code) are written by every log call
+// made with this context (slog.InfoContext, ...).
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"quizzy.app/backend/quizzy/cfg"
+)
+
+const (
+	AttrRequestId = "requestId"
+	AttrUid       = "uid"
+	AttrExecution = "execution"
+)
+
+type attrsKey struct{}
+
+// With returns a copy of the given context carrying the given attributes, along with
+// attributes it already carries.
+func With(ctx context.Context, attrs ...slog.Attr) context.Context {
+	current, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return context.WithValue(ctx, attrsKey{}, append(current[:len(current):len(current)], attrs...))
+}
+
+// contextHandler decorates a slog.Handler, adding attributes carried by the context of every record.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs, ok := ctx.Value(attrsKey{}).([]slog.Attr); ok {
+		record.AddAttrs(attrs...)
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// NewLogger returns a logger writing to the given writer, with the configured level and format.
+func NewLogger(w io.Writer, conf cfg.LogConfig) (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(conf.Level)); err != nil {
+		return nil, fmt.Errorf("unknown log level %q", conf.Level)
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	switch conf.Format {
+	case cfg.LogFormatText:
+		return slog.New(contextHandler{slog.NewTextHandler(w, opts)}), nil
+	case cfg.LogFormatJson:
+		return slog.New(contextHandler{slog.NewJSONHandler(w, opts)}), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q", conf.Format)
+	}
+}
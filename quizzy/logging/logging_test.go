@@ -0,0 +1,130 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"net/http"
+	"quizzy.app/backend/quizzy/cfg"
+	"strings"
+	"testing"
+)
+
+func _decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	lines := make([]map[string]any, 0)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry map[string]any
+		assert.Nil(t, json.Unmarshal([]byte(line), &entry))
+		lines = append(lines, entry)
+	}
+
+	return lines
+}
+
+func TestLoggerWritesContextAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(&buf, cfg.LogConfig{Level: "info", Format: cfg.LogFormatJson})
+	assert.Nil(t, err)
+
+	ctx := With(context.Background(), slog.String(AttrRequestId, "request"))
+	child := With(ctx, slog.String(AttrUid, "uid"))
+
+	logger.InfoContext(child, "child", "key", "value")
+	logger.InfoContext(ctx, "parent")
+	logger.DebugContext(child, "filtered")
+
+	lines := _decodeLines(t, &buf)
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "child", lines[0]["msg"])
+	assert.Equal(t, "request", lines[0][AttrRequestId])
+	assert.Equal(t, "uid", lines[0][AttrUid])
+	assert.Equal(t, "value", lines[0]["key"])
+	// Attributes of a child context never leak to its parent.
+	assert.Equal(t, "request", lines[1][AttrRequestId])
+	assert.NotContains(t, lines[1], AttrUid)
+}
+
+func TestLoggerTextFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(&buf, cfg.LogConfig{Level: "debug", Format: cfg.LogFormatText})
+	assert.Nil(t, err)
+
+	logger.With("key", "value").DebugContext(With(context.Background(), slog.String(AttrExecution, "ABC123")), "hello")
+	assert.Contains(t, buf.String(), "level=DEBUG")
+	assert.Contains(t, buf.String(), "key=value")
+	assert.Contains(t, buf.String(), "execution=ABC123")
+}
+
+func TestNewLoggerRejectsUnknownSettings(t *testing.T) {
+	_, err := NewLogger(&bytes.Buffer{}, cfg.LogConfig{Level: "verbose", Format: cfg.LogFormatText})
+	assert.NotNil(t, err)
+
+	_, err = NewLogger(&bytes.Buffer{}, cfg.LogConfig{Level: "info", Format: "xml"})
+	assert.NotNil(t, err)
+}
+
+func _configureLoggedHandler(t *testing.T) (http.Handler, *bytes.Buffer) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(&buf, cfg.LogConfig{Level: "info", Format: cfg.LogFormatJson})
+	assert.Nil(t, err)
+
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	eng := gin.New()
+	eng.Use(RequestId, AccessLog)
+	eng.GET("/items/:item-id", func(ctx *gin.Context) {
+		// Like authentication middlewares, handlers may enrich the request context.
+		ctx.Request = ctx.Request.WithContext(With(ctx.Request.Context(), slog.String(AttrUid, "uid")))
+		ctx.String(http.StatusOK, UseRequestId(ctx))
+	})
+	return eng, &buf
+}
+
+func TestRequestIdIsGenerated(t *testing.T) {
+	handler, buf := _configureLoggedHandler(t)
+
+	res := httpexpect.Default(t, "/").
+		GET("/items/1").
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusOK)
+	id := res.Header(HeaderRequestId).NotEmpty().Raw()
+	res.Body().IsEqual(id)
+
+	lines := _decodeLines(t, buf)
+	assert.Len(t, lines, 1)
+	assert.Equal(t, "request handled", lines[0]["msg"])
+	assert.Equal(t, id, lines[0][AttrRequestId])
+	assert.Equal(t, "uid", lines[0][AttrUid])
+	assert.Equal(t, "/items/:item-id", lines[0]["route"])
+	assert.Equal(t, float64(http.StatusOK), lines[0]["status"])
+}
+
+func TestRequestIdIsReused(t *testing.T) {
+	handler, _ := _configureLoggedHandler(t)
+	ex := httpexpect.Default(t, "/")
+
+	ex.GET("/items/1").
+		WithHandler(handler).
+		WithHeader(HeaderRequestId, "from-proxy").
+		Expect().
+		Status(http.StatusOK).
+		Header(HeaderRequestId).IsEqual("from-proxy")
+
+	ex.GET("/items/1").
+		WithHandler(handler).
+		WithHeader(HeaderRequestId, strings.Repeat("x", MaxRequestIdLength+1)).
+		Expect().
+		Status(http.StatusOK).
+		Header(HeaderRequestId).Length().IsEqual(36)
+}
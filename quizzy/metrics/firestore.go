@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"io"
+	"sync"
+	"time"
+)
+
+// FirestoreOptions returns client options timing every Firestore RPC.
+func FirestoreOptions() []option.ClientOption {
+	return []option.ClientOption{
+		option.WithGRPCDialOption(grpc.WithChainUnaryInterceptor(unaryInterceptor)),
+		option.WithGRPCDialOption(grpc.WithChainStreamInterceptor(streamInterceptor)),
+	}
+}
+
+func observeFirestore(method string, start time.Time, err error) {
+	firestoreDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		firestoreErrors.WithLabelValues(method, status.Code(err).String()).Inc()
+	}
+}
+
+func unaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	observeFirestore(method, start, err)
+	return err
+}
+
+// streamInterceptor times streaming RPCs (queries, batched reads) until their last message
+// is received. Streams abandoned by their caller before the end aren't observed.
+func streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	start := time.Now()
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		observeFirestore(method, start, err)
+		return nil, err
+	}
+
+	return &observedStream{ClientStream: stream, method: method, start: start}, nil
+}
+
+type observedStream struct {
+	grpc.ClientStream
+	method string
+	start  time.Time
+	once   sync.Once
+}
+
+func (s *observedStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.once.Do(func() {
+			if errors.Is(err, io.EOF) {
+				observeFirestore(s.method, s.start, nil)
+			} else {
+				observeFirestore(s.method, s.start, err)
+			}
+		})
+	}
+
+	return err
+}
@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+)
+
+// unmatchedRoute labels requests matching no route, so unknown paths can't create metrics at will.
+const unmatchedRoute = "unmatched"
+
+// Middleware counts and times every request, labelled by its route pattern (e.g. "/quiz/:quiz-id")
+// rather than by its path.
+func Middleware(ctx *gin.Context) {
+	start := time.Now()
+	ctx.Next()
+
+	route := ctx.FullPath()
+	if route == "" {
+		route = unmatchedRoute
+	}
+
+	method := ctx.Request.Method
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(ctx.Writer.Status())).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,123 @@
+// Package metrics exposes Prometheus metrics of the application: HTTP requests, WebSocket
+// activity and calls to external services (Firestore, Redis).
+package metrics
+
+import (
+	"crypto/subtle"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+)
+
+const namespace = "quizzy"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests handled, by route and status code.",
+	}, []string{"method", "route", "status"})
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Time spent handling HTTP requests, by route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// WsConnections is the number of open WebSocket connections.
+	WsConnections = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "ws",
+		Name:      "connections",
+		Help:      "Open WebSocket connections.",
+	})
+	// WsRooms is the number of hosted executions.
+	WsRooms = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "ws",
+		Name:      "rooms",
+		Help:      "Executions currently hosted.",
+	})
+	// WsParticipants is the number of players connected to a hosted execution.
+	WsParticipants = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "ws",
+		Name:      "participants",
+		Help:      "Players connected to hosted executions.",
+	})
+	wsEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ws",
+		Name:      "events_total",
+		Help:      "WebSocket events processed, by type.",
+	}, []string{"event"})
+
+	firestoreDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "firestore",
+		Name:      "call_duration_seconds",
+		Help:      "Time spent in Firestore calls, by RPC method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+	firestoreErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "firestore",
+		Name:      "errors_total",
+		Help:      "Failed Firestore calls, by RPC method and gRPC code.",
+	}, []string{"method", "code"})
+
+	redisDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "redis",
+		Name:      "command_duration_seconds",
+		Help:      "Time spent in Redis commands, by command.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"command"})
+	redisErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "redis",
+		Name:      "errors_total",
+		Help:      "Failed Redis commands, by command.",
+	}, []string{"command"})
+)
+
+// wsEventNames are the WebSocket events clients may send, any other one is counted as unknown
+// so clients can't create metrics at will.
+var wsEventNames = map[string]bool{
+	"host":         true,
+	"join":         true,
+	"nextQuestion": true,
+	"answer":       true,
+}
+
+// CountWsEvent counts a WebSocket event of the given type as processed.
+func CountWsEvent(name string) {
+	if !wsEventNames[name] {
+		name = "unknown"
+	}
+
+	wsEvents.WithLabelValues(name).Inc()
+}
+
+// ConfigureRouting serves every registered metric, in the Prometheus text format, to scrapers
+// authenticated with the given bearer token. Metrics aren't served at all without a token.
+func ConfigureRouting(rt *gin.RouterGroup, token string) {
+	if len(token) == 0 {
+		return
+	}
+
+	rt.GET("/metrics", requireToken(token), gin.WrapH(promhttp.Handler()))
+}
+
+func requireToken(token string) gin.HandlerFunc {
+	expected := []byte("Bearer " + token)
+	return func(ctx *gin.Context) {
+		if subtle.ConstantTimeCompare([]byte(ctx.GetHeader("Authorization")), expected) != 1 {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func _configureMetricsHandler(token string) http.Handler {
+	eng := gin.New()
+	eng.Use(Middleware)
+	rt := eng.Group("")
+	rt.GET("/quiz/:quiz-id", func(ctx *gin.Context) { ctx.Status(http.StatusNoContent) })
+	ConfigureRouting(rt, token)
+	return eng
+}
+
+func TestMiddlewareLabelsByRoute(t *testing.T) {
+	handler := _configureMetricsHandler("secret")
+	ex := httpexpect.Default(t, "/")
+	before := testutil.ToFloat64(httpRequests.WithLabelValues("GET", "/quiz/:quiz-id", "204"))
+	unmatched := testutil.ToFloat64(httpRequests.WithLabelValues("GET", unmatchedRoute, "404"))
+
+	ex.GET("/quiz/first").WithHandler(handler).Expect().Status(http.StatusNoContent)
+	ex.GET("/quiz/second").WithHandler(handler).Expect().Status(http.StatusNoContent)
+	ex.GET("/unknown").WithHandler(handler).Expect().Status(http.StatusNotFound)
+
+	assert.Equal(t, before+2, testutil.ToFloat64(httpRequests.WithLabelValues("GET", "/quiz/:quiz-id", "204")))
+	assert.Equal(t, unmatched+1, testutil.ToFloat64(httpRequests.WithLabelValues("GET", unmatchedRoute, "404")))
+
+	ex.GET("/metrics").WithHandler(handler).
+		WithHeader("Authorization", "Bearer secret").
+		Expect().
+		Status(http.StatusOK).
+		Body().
+		Contains(`quizzy_http_requests_total{method="GET",route="/quiz/:quiz-id",status="204"}`).
+		Contains("quizzy_http_request_duration_seconds_bucket")
+}
+
+func TestMetricsRequireToken(t *testing.T) {
+	ex := httpexpect.Default(t, "/")
+
+	handler := _configureMetricsHandler("secret")
+	ex.GET("/metrics").WithHandler(handler).
+		Expect().Status(http.StatusUnauthorized)
+	ex.GET("/metrics").WithHandler(handler).WithHeader("Authorization", "Bearer wrong").
+		Expect().Status(http.StatusUnauthorized)
+
+	// Without any token, metrics aren't served.
+	ex.GET("/metrics").WithHandler(_configureMetricsHandler("")).WithHeader("Authorization", "Bearer ").
+		Expect().Status(http.StatusNotFound)
+}
+
+func TestCountWsEvent(t *testing.T) {
+	answers := testutil.ToFloat64(wsEvents.WithLabelValues("answer"))
+	unknown := testutil.ToFloat64(wsEvents.WithLabelValues("unknown"))
+
+	CountWsEvent("answer")
+	CountWsEvent("whatever")
+	CountWsEvent("")
+
+	assert.Equal(t, answers+1, testutil.ToFloat64(wsEvents.WithLabelValues("answer")))
+	assert.Equal(t, unknown+2, testutil.ToFloat64(wsEvents.WithLabelValues("unknown")))
+}
+
+func TestRedisHook(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rc := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	rc.AddHook(RedisHook{})
+	ctx := context.Background()
+	getErrors := testutil.ToFloat64(redisErrors.WithLabelValues("get"))
+	incrErrors := testutil.ToFloat64(redisErrors.WithLabelValues("incr"))
+
+	assert.Nil(t, rc.Set(ctx, "key", "value", 0).Err())
+	// A missing key isn't an error.
+	assert.ErrorIs(t, rc.Get(ctx, "missing").Err(), redis.Nil)
+	// Incrementing a string is.
+	assert.NotNil(t, rc.Incr(ctx, "key").Err())
+
+	assert.Equal(t, getErrors, testutil.ToFloat64(redisErrors.WithLabelValues("get")))
+	assert.Equal(t, incrErrors+1, testutil.ToFloat64(redisErrors.WithLabelValues("incr")))
+	assert.Positive(t, testutil.CollectAndCount(redisDuration))
+}
+
+type fakeStream struct {
+	grpc.ClientStream
+	errs []error
+}
+
+func (s *fakeStream) RecvMsg(m any) error {
+	err := s.errs[0]
+	s.errs = s.errs[1:]
+	return err
+}
+
+func TestFirestoreInterceptors(t *testing.T) {
+	method := "/google.firestore.v1.Firestore/GetDocument"
+	notFound := testutil.ToFloat64(firestoreErrors.WithLabelValues(method, codes.NotFound.String()))
+
+	err := unaryInterceptor(context.Background(), method, nil, nil, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return status.Error(codes.NotFound, "missing")
+		})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, notFound+1, testutil.ToFloat64(firestoreErrors.WithLabelValues(method, codes.NotFound.String())))
+
+	// Streams ending normally aren't errors, and are observed once.
+	method = "/google.firestore.v1.Firestore/RunQuery"
+	stream, err := streamInterceptor(context.Background(), &grpc.StreamDesc{}, nil, method,
+		func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return &fakeStream{errs: []error{nil, io.EOF, io.EOF}}, nil
+		})
+	assert.Nil(t, err)
+	assert.Nil(t, stream.RecvMsg(nil))
+	assert.ErrorIs(t, stream.RecvMsg(nil), io.EOF)
+	assert.ErrorIs(t, stream.RecvMsg(nil), io.EOF)
+
+	var observed dto.Metric
+	assert.Nil(t, firestoreDuration.WithLabelValues(method).(prometheus.Histogram).Write(&observed))
+	assert.Equal(t, uint64(1), observed.GetHistogram().GetSampleCount())
+	assert.Zero(t, testutil.ToFloat64(firestoreErrors.WithLabelValues(method, codes.Unknown.String())))
+}
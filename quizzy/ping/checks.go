@@ -0,0 +1,142 @@
+package ping
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"quizzy.app/backend/quizzy/services"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK      = "OK"
+	StatusKO      = "KO"
+	StatusPartial = "Partial"
+)
+
+var (
+	ErrNotConfigured = errors.New("service not configured")
+)
+
+// Check probes a single dependency of the application.
+type Check struct {
+	// Name of the dependency, as reported in responses.
+	Name string
+	// Whether the application can't serve requests while the dependency is down.
+	Required bool
+	// Probe contacts the dependency, returning an error if it's unreachable.
+	Probe func(ctx context.Context) error
+}
+
+// CheckResult is the outcome of a single Check.
+type CheckResult struct {
+	Status   string `json:"status"`
+	Required bool   `json:"required"`
+	// Latency of the probe, in milliseconds.
+	Latency float64 `json:"latency"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// RedisCheck probes the given redis client with a PING command.
+func RedisCheck(rc *redis.Client) Check {
+	return Check{
+		Name:     "redis",
+		Required: true,
+		Probe: func(ctx context.Context) error {
+			if rc == nil {
+				return ErrNotConfigured
+			}
+
+			return rc.Ping(ctx).Err()
+		},
+	}
+}
+
+// FirestoreCheck probes the given firestore client by reading a document. The document
+// doesn't have to exist, a "not found" answer proves the database is reachable.
+func FirestoreCheck(fbs *services.FirebaseServices) Check {
+	return Check{
+		Name:     "database",
+		Required: true,
+		Probe: func(ctx context.Context) error {
+			if fbs == nil || fbs.Store == nil {
+				return ErrNotConfigured
+			}
+
+			_, err := fbs.Store.Collection("health").Doc("probe").Get(ctx)
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			return err
+		},
+	}
+}
+
+// runChecks runs every given check concurrently, each one being cancelled once the given
+// timeout is elapsed.
+func runChecks(ctx context.Context, checks []Check, timeout time.Duration) map[string]CheckResult {
+	results := make(map[string]CheckResult, len(checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			probeCtx, cancel := services.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check.Probe(probeCtx)
+			result := CheckResult{
+				Status:   StatusOK,
+				Required: check.Required,
+				Latency:  float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				result.Status = StatusKO
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			results[check.Name] = result
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
+
+// isReady reports whether every required dependency is up.
+func isReady(results map[string]CheckResult) bool {
+	for _, result := range results {
+		if result.Required && result.Status != StatusOK {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getGlobalStatus returns OK when every dependency is up, KO when all are down, Partial otherwise.
+func getGlobalStatus(results map[string]CheckResult) string {
+	koc := 0
+	for _, result := range results {
+		if result.Status != StatusOK {
+			koc++
+		}
+	}
+
+	if koc == 0 {
+		return StatusOK
+	} else if koc == len(results) {
+		return StatusKO
+	} else {
+		return StatusPartial
+	}
+}
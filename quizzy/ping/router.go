@@ -3,69 +3,80 @@ package ping
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
-	"maps"
+	"net/http"
 	"quizzy.app/backend/quizzy/services"
-	"strings"
+	"time"
 )
 
 type Controller struct {
-	FbService   *services.FirebaseServices
-	RedisClient *redis.Client
+	Checks []Check
+	// How long a single probe may last before its dependency is reported down.
+	Timeout time.Duration
 }
 
-func Configure(fbs *services.FirebaseServices, rc *redis.Client) *Controller {
-	return &Controller{FbService: fbs, RedisClient: rc}
+func Configure(fbs *services.FirebaseServices, rc *redis.Client, timeout time.Duration) *Controller {
+	return &Controller{
+		Checks:  []Check{FirestoreCheck(fbs), RedisCheck(rc)},
+		Timeout: timeout,
+	}
+}
+
+// HealthResponse describe the state of the application and of each of its dependencies.
+type HealthResponse struct {
+	Status  string                 `json:"status"`
+	Details map[string]CheckResult `json:"details,omitempty"`
 }
 
-// pingHandler ping vérifie l'état des services Firebase et Redis et retourne leur disponibilité
-// @Summary Vérifier la disponibilité des services
-// @Description Vérifie si Firebase et Redis sont accessibles et retourne leur état
-// @Tags HealthCheck
-// @Produce json
-// @Success 200 {object} map[string]interface{} "Statut des services"
-// @Router /ping [get]
 func (pc *Controller) ConfigureRouting(rt *gin.RouterGroup) {
 	rt.GET("/ping", pc.ping)
+	rt.GET("/livez", pc.livez)
+	rt.GET("/readyz", pc.readyz)
 }
 
+// ping vérifie l'état des services Firebase et Redis et retourne leur disponibilité
+// @Summary Vérifier la disponibilité des services
+// @Description Contacte Firestore (lecture d'un document) et Redis (commande PING), et retourne l'état et la latence (en millisecondes) de chacun. Le statut global vaut OK, KO ou Partial.
+// @Tags HealthCheck
+// @Produce json
+// @Success 200 {object} HealthResponse "Statut des services"
+// @Router /ping [get]
 func (pc *Controller) ping(c *gin.Context) {
-	details := map[string]string{
-		"database": "OK",
-		"redis":    "OK",
-	}
-
-	// Checking firebase services (firestore + firebase) availability.
-	if pc.FbService == nil {
-		details["database"] = "KO"
-	}
-
-	if pc.FbService == nil {
-		details["redis"] = "KO"
-	}
-
-	c.JSON(200, gin.H{
-		"status":  getGlobalStatus(details),
-		"details": details,
+	results := runChecks(c.Request.Context(), pc.Checks, pc.Timeout)
+	c.JSON(http.StatusOK, HealthResponse{
+		Status:  getGlobalStatus(results),
+		Details: results,
 	})
 }
 
-func getGlobalStatus(details map[string]string) string {
-	koc := 0
+// livez indique si le processus est en vie
+// @Summary Sonde de vivacité
+// @Description Répond tant que le serveur traite des requêtes, sans contacter ses dépendances.
+// @Tags HealthCheck
+// @Produce json
+// @Success 200 {object} HealthResponse "Serveur en vie"
+// @Router /livez [get]
+func (pc *Controller) livez(c *gin.Context) {
+	c.JSON(http.StatusOK, HealthResponse{Status: StatusOK})
+}
+
+// readyz indique si le serveur peut traiter des requêtes
+// @Summary Sonde de disponibilité
+// @Description Contacte chaque dépendance, et répond 503 si l'une des dépendances requises est indisponible.
+// @Tags HealthCheck
+// @Produce json
+// @Success 200 {object} HealthResponse "Dépendances requises disponibles"
+// @Failure 503 {object} HealthResponse "Dépendance requise indisponible"
+// @Router /readyz [get]
+func (pc *Controller) readyz(c *gin.Context) {
+	results := runChecks(c.Request.Context(), pc.Checks, pc.Timeout)
 
-	for v := range maps.Values(details) {
-		switch strings.ToUpper(v) {
-		case "OK":
-			continue
-		case "KO":
-			koc++
-		}
+	code := http.StatusOK
+	if !isReady(results) {
+		code = http.StatusServiceUnavailable
 	}
 
-	if koc == 0 {
-		return "OK"
-	} else if koc == len(details) {
-		return "KO"
-	} else {
-		return "Partial"
-	}
+	c.JSON(code, HealthResponse{
+		Status:  getGlobalStatus(results),
+		Details: results,
+	})
 }
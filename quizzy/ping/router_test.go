@@ -0,0 +1,109 @@
+package ping
+
+import (
+	"context"
+	"errors"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func _upCheck(name string) Check {
+	return Check{Name: name, Required: true, Probe: func(ctx context.Context) error { return nil }}
+}
+
+func _downCheck(name string, required bool) Check {
+	return Check{Name: name, Required: required, Probe: func(ctx context.Context) error { return errors.New("unreachable") }}
+}
+
+// _hangingCheck only returns once its context is cancelled.
+func _hangingCheck(name string) Check {
+	return Check{Name: name, Required: true, Probe: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+}
+
+func _configureHealthHandler(checks ...Check) http.Handler {
+	eng := gin.New()
+	(&Controller{Checks: checks, Timeout: 50 * time.Millisecond}).ConfigureRouting(eng.Group(""))
+	return eng
+}
+
+func TestReadyWhenDependenciesAreUp(t *testing.T) {
+	handler := _configureHealthHandler(_upCheck("database"), _upCheck("redis"))
+	ex := httpexpect.Default(t, "/")
+
+	obj := ex.GET("/readyz").WithHandler(handler).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	obj.Value("status").IsEqual(StatusOK)
+	obj.Value("details").Object().Value("database").Object().Value("status").IsEqual(StatusOK)
+	obj.Value("details").Object().Value("redis").Object().Value("latency").Number().Ge(0)
+
+	ex.GET("/ping").WithHandler(handler).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("status").IsEqual(StatusOK)
+}
+
+func TestNotReadyWhenRequiredDependencyIsDown(t *testing.T) {
+	handler := _configureHealthHandler(_upCheck("database"), _downCheck("redis", true))
+	ex := httpexpect.Default(t, "/")
+
+	obj := ex.GET("/readyz").WithHandler(handler).
+		Expect().
+		Status(http.StatusServiceUnavailable).
+		JSON().Object()
+	obj.Value("status").IsEqual(StatusPartial)
+	redis := obj.Value("details").Object().Value("redis").Object()
+	redis.Value("status").IsEqual(StatusKO)
+	redis.Value("error").IsEqual("unreachable")
+
+	// Liveness never depends on other services.
+	ex.GET("/livez").WithHandler(handler).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("status").IsEqual(StatusOK)
+	ex.GET("/ping").WithHandler(handler).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("status").IsEqual(StatusPartial)
+}
+
+func TestReadyWhenOptionalDependencyIsDown(t *testing.T) {
+	handler := _configureHealthHandler(_upCheck("database"), _downCheck("cache", false))
+
+	httpexpect.Default(t, "/").
+		GET("/readyz").WithHandler(handler).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("status").IsEqual(StatusPartial)
+}
+
+func TestProbesTimeOut(t *testing.T) {
+	handler := _configureHealthHandler(_hangingCheck("database"))
+
+	obj := httpexpect.Default(t, "/").
+		GET("/readyz").WithHandler(handler).
+		Expect().
+		Status(http.StatusServiceUnavailable).
+		JSON().Object()
+	obj.Value("status").IsEqual(StatusKO)
+	obj.Value("details").Object().Value("database").Object().Value("error").IsEqual(context.DeadlineExceeded.Error())
+}
+
+func TestUnconfiguredServicesAreDown(t *testing.T) {
+	handler := _configureHealthHandler(FirestoreCheck(nil), RedisCheck(nil))
+
+	obj := httpexpect.Default(t, "/").
+		GET("/readyz").WithHandler(handler).
+		Expect().
+		Status(http.StatusServiceUnavailable).
+		JSON().Object()
+	obj.Value("status").IsEqual(StatusKO)
+	obj.Value("details").Object().Value("database").Object().Value("error").IsEqual(ErrNotConfigured.Error())
+}
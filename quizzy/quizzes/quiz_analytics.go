@@ -0,0 +1,179 @@
+package quizzes
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidAnswer   = errors.New("invalid answer")
+	ErrAlreadyAnswered = errors.New("already answered")
+)
+
+// MaxNicknameLength is the maximum length of a participant nickname, in characters.
+const MaxNicknameLength = 32
+
+// Participant is a player who joined an execution, stored under
+// "users/{ownerId}/quizzes/{quizId}/executions/{executionId}/participants/{uid}". Its uid is also
+// stored in the document, so participations of a user are found across executions.
+type Participant struct {
+	Id       string `firestore:"uid" json:"id"`
+	Nickname string `firestore:"nickname" json:"nickname"`
+	// Registered is set for players authenticated with their account, rather than as guests
+	// or anonymously.
+	Registered bool      `firestore:"registered" json:"registered"`
+	JoinedAt   time.Time `firestore:"joinedAt" json:"joinedAt"`
+}
+
+// NormalizeNickname trims the given nickname to MaxNicknameLength, a default one is derived
+// from the participant id when empty.
+func NormalizeNickname(nickname, uid string) string {
+	nickname = strings.TrimSpace(nickname)
+	if utf8.RuneCountInString(nickname) > MaxNicknameLength {
+		nickname = string([]rune(nickname)[:MaxNicknameLength])
+	}
+
+	if nickname == "" {
+		nickname = "player-" + uid[:min(6, len(uid))]
+	}
+	return nickname
+}
+
+// PlayerAnswer is the answer of a participant to a question of an execution, stored under
+// "users/{ownerId}/quizzes/{quizId}/executions/{executionId}/answers/{questionId}_{uid}".
+// Only the first answer of a participant to a question is kept.
+type PlayerAnswer struct {
+	PlayerId   string `firestore:"playerId" json:"playerId"`
+	QuestionId string `firestore:"questionId" json:"questionId"`
+	// AnswerId is empty when the participant skipped the question.
+	AnswerId  string `firestore:"answerId" json:"answerId"`
+	IsCorrect bool   `firestore:"isCorrect" json:"isCorrect"`
+	// ResponseTime is the time elapsed from the question being asked to the answer, in milliseconds.
+	ResponseTime int64     `firestore:"responseTime" json:"responseTime"`
+	AnsweredAt   time.Time `firestore:"answeredAt" json:"answeredAt"`
+}
+
+// IsSkipped reports whether the participant skipped the question.
+func (a *PlayerAnswer) IsSkipped() bool {
+	return a.AnswerId == ""
+}
+
+// AnswerStats counts participants who picked an answer.
+type AnswerStats struct {
+	AnswerId  string `json:"answerId"`
+	Title     string `json:"title"`
+	IsCorrect bool   `json:"isCorrect"`
+	Count     int    `json:"count"`
+}
+
+type QuestionAnalytics struct {
+	QuestionId string `json:"questionId"`
+	Title      string `json:"title"`
+	// Asked is false for questions the execution didn't reach, their counts are all 0.
+	Asked bool `json:"asked"`
+	// Participants counts players who were there when the question was asked, or answered it.
+	Participants int `json:"participants"`
+	Answered     int `json:"answered"`
+	Correct      int `json:"correct"`
+	// CorrectRate is the percentage of participants who answered correctly, skips included.
+	CorrectRate float64 `json:"correctRate"`
+	// Skipped counts participants who skipped the question or didn't answer it.
+	Skipped int `json:"skipped"`
+	// MedianResponseTime of participants who answered, in milliseconds.
+	MedianResponseTime int64         `json:"medianResponseTime"`
+	Answers            []AnswerStats `json:"answers"`
+}
+
+type ExecutionAnalytics struct {
+	ExecutionId  string              `json:"executionId"`
+	Participants int                 `json:"participants"`
+	Questions    []QuestionAnalytics `json:"questions"`
+}
+
+// ComputeAnalytics aggregates stored answers of the given execution, per question of the quiz.
+// Answers to questions removed from the quiz since are ignored.
+func ComputeAnalytics(quiz Quiz, execution Execution, participants []Participant, answers []PlayerAnswer) ExecutionAnalytics {
+	byQuestion := make(map[string][]PlayerAnswer)
+	for _, answer := range answers {
+		byQuestion[answer.QuestionId] = append(byQuestion[answer.QuestionId], answer)
+	}
+
+	questions := make([]QuestionAnalytics, 0, len(quiz.Questions))
+	for _, question := range quiz.Questions {
+		stats := QuestionAnalytics{
+			QuestionId: question.Id,
+			Title:      question.Title,
+			Answers:    make([]AnswerStats, 0, len(question.Answers)),
+		}
+
+		counts := make(map[string]int)
+		answered := make(map[string]bool)
+		times := make([]int64, 0)
+		for _, answer := range byQuestion[question.Id] {
+			answered[answer.PlayerId] = true
+			if answer.IsSkipped() {
+				continue
+			}
+
+			counts[answer.AnswerId]++
+			times = append(times, answer.ResponseTime)
+			stats.Answered++
+			if answer.IsCorrect {
+				stats.Correct++
+			}
+		}
+
+		for _, answer := range question.Answers {
+			stats.Answers = append(stats.Answers, AnswerStats{
+				AnswerId:  answer.Id,
+				Title:     answer.Title,
+				IsCorrect: answer.IsCorrect,
+				Count:     counts[answer.Id],
+			})
+		}
+
+		askedAt, asked := execution.AskedAt[question.Id]
+		stats.Asked = asked || len(answered) > 0
+		if stats.Asked {
+			stats.Participants = len(answered)
+			for _, participant := range participants {
+				if !answered[participant.Id] && !participant.JoinedAt.After(askedAt) {
+					stats.Participants++
+				}
+			}
+
+			stats.Skipped = stats.Participants - stats.Answered
+			if stats.Participants > 0 {
+				stats.CorrectRate = float64(stats.Correct) * 100 / float64(stats.Participants)
+			}
+			stats.MedianResponseTime = median(times)
+		}
+
+		questions = append(questions, stats)
+	}
+
+	return ExecutionAnalytics{
+		ExecutionId:  execution.Id,
+		Participants: len(participants),
+		Questions:    questions,
+	}
+}
+
+func median(values []int64) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i] < values[j]
+	})
+
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
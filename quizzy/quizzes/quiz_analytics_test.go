@@ -0,0 +1,122 @@
+package quizzes
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func _twoQuestionsQuiz() Quiz {
+	quiz := _readyQuiz()
+	quiz.Questions = append(quiz.Questions, Question{
+		Id:    uuid.New().String(),
+		Title: "second",
+		Answers: []Answer{
+			{Id: uuid.New().String(), Title: "a", IsCorrect: true},
+			{Id: uuid.New().String(), Title: "b"},
+		},
+	})
+	return quiz
+}
+
+func TestComputeAnalytics(t *testing.T) {
+	quiz := _twoQuestionsQuiz()
+	first := quiz.Questions[0]
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	execution := Execution{
+		Id:      "execution",
+		AskedAt: map[string]time.Time{first.Id: start},
+	}
+
+	participants := []Participant{
+		{Id: "alice", JoinedAt: start.Add(-time.Minute)},
+		{Id: "bob", JoinedAt: start.Add(-time.Minute)},
+		{Id: "carol", JoinedAt: start.Add(-time.Minute)},
+		{Id: "dave", JoinedAt: start.Add(-time.Minute)},
+		// Joined once the question was asked, without answering it.
+		{Id: "late", JoinedAt: start.Add(time.Minute)},
+	}
+	answers := []PlayerAnswer{
+		{PlayerId: "alice", QuestionId: first.Id, AnswerId: first.Answers[0].Id, IsCorrect: true, ResponseTime: 1000},
+		{PlayerId: "bob", QuestionId: first.Id, AnswerId: first.Answers[1].Id, ResponseTime: 4000},
+		{PlayerId: "carol", QuestionId: first.Id, AnswerId: first.Answers[0].Id, IsCorrect: true, ResponseTime: 2000},
+		{PlayerId: "dave", QuestionId: first.Id},
+		{PlayerId: "alice", QuestionId: "removed", AnswerId: "removed"},
+	}
+
+	analytics := ComputeAnalytics(quiz, execution, participants, answers)
+	assert.Equal(t, 5, analytics.Participants)
+	assert.Len(t, analytics.Questions, 2)
+
+	stats := analytics.Questions[0]
+	assert.True(t, stats.Asked)
+	assert.Equal(t, 4, stats.Participants)
+	assert.Equal(t, 3, stats.Answered)
+	assert.Equal(t, 2, stats.Correct)
+	assert.Equal(t, 50.0, stats.CorrectRate)
+	assert.Equal(t, 1, stats.Skipped)
+	assert.Equal(t, int64(2000), stats.MedianResponseTime)
+	assert.Equal(t, 2, stats.Answers[0].Count)
+	assert.Equal(t, 1, stats.Answers[1].Count)
+
+	// Questions the execution didn't reach.
+	assert.False(t, analytics.Questions[1].Asked)
+	assert.Equal(t, 0, analytics.Questions[1].Skipped)
+	assert.Len(t, analytics.Questions[1].Answers, 2)
+}
+
+func TestMedianOfEvenCount(t *testing.T) {
+	assert.Equal(t, int64(0), median(nil))
+	assert.Equal(t, int64(250), median([]int64{400, 100, 200, 300}))
+}
+
+func TestAnswerQuestion(t *testing.T) {
+	svc := _createDummyQuizService()
+	ctx := context.Background()
+	quiz := _twoQuestionsQuiz()
+	_ = svc.Create(ctx, "owner", quiz)
+
+	execution, err := svc.StartQuiz(ctx, "owner", quiz)
+	assert.Nil(t, err)
+	first := quiz.Questions[0]
+
+	// Nothing can be answered before the first question is asked.
+	_, err = svc.AnswerQuestion(ctx, execution, quiz, "alice", first.Id, first.Answers[0].Id)
+	assert.ErrorIs(t, err, ErrInvalidAnswer)
+
+	execution.Status = ExecutionStarted
+	execution.Cursor = 1
+	execution.AskedAt = map[string]time.Time{first.Id: time.Now().UTC().Add(-time.Second)}
+	assert.Nil(t, svc.UpdateExecution(ctx, execution))
+	assert.Nil(t, svc.JoinExecution(ctx, execution, Participant{Id: "alice", Nickname: "Alice", JoinedAt: time.Now().UTC().Add(-time.Minute)}))
+	assert.Nil(t, svc.JoinExecution(ctx, execution, Participant{Id: "bob", JoinedAt: time.Now().UTC().Add(-time.Minute)}))
+	// Rejoining keeps the participant as first recorded.
+	assert.Nil(t, svc.JoinExecution(ctx, execution, Participant{Id: "alice", Nickname: "Other"}))
+
+	answer, err := svc.AnswerQuestion(ctx, execution, quiz, "alice", first.Id, first.Answers[0].Id)
+	assert.Nil(t, err)
+	assert.True(t, answer.IsCorrect)
+	assert.GreaterOrEqual(t, answer.ResponseTime, int64(1000))
+
+	_, err = svc.AnswerQuestion(ctx, execution, quiz, "alice", first.Id, first.Answers[1].Id)
+	assert.ErrorIs(t, err, ErrAlreadyAnswered)
+	_, err = svc.AnswerQuestion(ctx, execution, quiz, "bob", quiz.Questions[1].Id, quiz.Questions[1].Answers[0].Id)
+	assert.ErrorIs(t, err, ErrInvalidAnswer)
+	_, err = svc.AnswerQuestion(ctx, execution, quiz, "bob", first.Id, "unknown")
+	assert.ErrorIs(t, err, ErrInvalidAnswer)
+
+	analytics, err := svc.GetExecutionAnalytics(ctx, quiz, execution)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, analytics.Participants)
+	assert.Equal(t, 1, analytics.Questions[0].Correct)
+	assert.Equal(t, 1, analytics.Questions[0].Skipped)
+}
+
+func TestNormalizeNickname(t *testing.T) {
+	assert.Equal(t, "Alice", NormalizeNickname("  Alice ", "uid"))
+	assert.Equal(t, "player-abcdef", NormalizeNickname("", "abcdef-123"))
+	assert.Len(t, []rune(NormalizeNickname(strings.Repeat("é", 40), "uid")), MaxNicknameLength)
+}
@@ -2,15 +2,38 @@ package quizzes
 
 import (
 	"crypto/rand"
-	"fmt"
+	"math/big"
+	"strings"
 )
 
-const CodeLength = 3
+const (
+	// CodeLength is the number of characters of an execution code.
+	CodeLength = 6
+	// CodeAlphabet lists characters used to build execution codes. Ambiguous characters
+	// (0/O, 1/I/L) are left out, so codes can easily be read aloud or copied from a board.
+	CodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+	// MaxCodeAttempts is the number of codes tried before giving up reserving one.
+	MaxCodeAttempts = 8
+)
 
+// GenerateCode returns a new random execution code, made of CodeLength characters from CodeAlphabet.
+// Generated codes aren't guaranteed to be unique, they must be reserved through QuizCodeResolver.BindCode.
 func GenerateCode() (string, error) {
+	max := big.NewInt(int64(len(CodeAlphabet)))
 	b := make([]byte, CodeLength)
-	if _, err := rand.Read(b); err != nil {
-		return "", err
+
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = CodeAlphabet[n.Int64()]
 	}
-	return fmt.Sprintf("%X", b), nil
+
+	return string(b), nil
+}
+
+// NormalizeCode returns the canonical form of a code typed by a user.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
 }
@@ -0,0 +1,86 @@
+package quizzes
+
+import (
+	"context"
+	"errors"
+	"quizzy.app/backend/quizzy/auth"
+	"slices"
+	"time"
+)
+
+var (
+	ErrInvalidCollaborator = errors.New("invalid collaborator")
+	ErrUserNotFound        = errors.New("invited user not found")
+)
+
+// QuizRole is the role granted to a user on a quiz.
+type QuizRole string
+
+const (
+	// QuizRoleOwner is the implicit role of the user owning the quiz, it can't be granted.
+	QuizRoleOwner QuizRole = "owner"
+	// QuizRoleEditor may edit the quiz, its questions, and start executions.
+	QuizRoleEditor QuizRole = "editor"
+	// QuizRoleViewer may only read the quiz and its executions.
+	QuizRoleViewer QuizRole = "viewer"
+)
+
+// quizRoleRanks orders roles, a role grants every permission of lower ranked ones.
+var quizRoleRanks = map[QuizRole]int{
+	QuizRoleViewer: 1,
+	QuizRoleEditor: 2,
+	QuizRoleOwner:  3,
+}
+
+// Grants reports whether the role includes permissions of the given one.
+func (r QuizRole) Grants(other QuizRole) bool {
+	return quizRoleRanks[r] >= quizRoleRanks[other] && quizRoleRanks[other] > 0
+}
+
+// IsGrantable reports whether the role can be granted to a collaborator.
+func (r QuizRole) IsGrantable() bool {
+	return r == QuizRoleEditor || r == QuizRoleViewer
+}
+
+// Collaborator is a user granted a role on a quiz owned by someone else, stored under
+// "users/{ownerId}/quizzes/{quizId}/collaborators/{uid}".
+type Collaborator struct {
+	Uid      string    `firestore:"-" json:"uid"`
+	Username string    `firestore:"username" json:"username"`
+	Role     QuizRole  `firestore:"role" json:"role"`
+	AddedAt  time.Time `firestore:"addedAt" json:"addedAt"`
+}
+
+// SharedQuiz is the reverse index of a Collaborator, stored under "users/{uid}/shared/{quizId}",
+// so quizzes shared with a user are found without knowing their owner.
+type SharedQuiz struct {
+	QuizId  string   `firestore:"-" json:"quizId"`
+	OwnerId string   `firestore:"ownerId" json:"ownerId"`
+	Role    QuizRole `firestore:"role" json:"role"`
+}
+
+// QuizAccess is a quiz along with its owner and the role granted to the user accessing it.
+type QuizAccess struct {
+	Quiz    Quiz     `json:"quiz"`
+	OwnerId string   `json:"ownerId"`
+	Role    QuizRole `json:"role"`
+}
+
+// UserRef identifies a registered user.
+type UserRef struct {
+	Uid      string
+	Username string
+	// Roles granted to the user, with auth.DefaultRole if none was.
+	Roles []auth.Role
+}
+
+// ManagesQuizzes tells whether the user may reach quiz routes, which are restricted to teachers.
+func (u UserRef) ManagesQuizzes() bool {
+	return slices.Contains(u.Roles, auth.RoleTeacher) || slices.Contains(u.Roles, auth.RoleAdmin)
+}
+
+// UserResolver finds registered users invited as collaborators, it is implemented by the users module.
+type UserResolver interface {
+	// FindUser returns the user matching the given email or username, ok is false if none matches.
+	FindUser(ctx context.Context, emailOrUsername string) (user UserRef, ok bool, err error)
+}
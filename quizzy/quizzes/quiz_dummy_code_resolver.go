@@ -1,41 +1,54 @@
 package quizzes
 
-import "fmt"
+import (
+	"context"
+)
 
 type dummyCodeResolver struct {
-	entries map[string]string
+	entries map[string]ExecutionRef
 	rooms   map[string]int
 }
 
-func (d *dummyCodeResolver) IncrRoomPeople(roomId string) error {
-	v := d.rooms[roomId]
-	d.rooms[roomId] = v + 1
+func _newDummyCodeResolver() *dummyCodeResolver {
+	return &dummyCodeResolver{
+		entries: make(map[string]ExecutionRef),
+		rooms:   make(map[string]int),
+	}
+}
+
+func (d *dummyCodeResolver) IncrRoomPeople(ctx context.Context, executionId string) error {
+	v := d.rooms[executionId]
+	d.rooms[executionId] = v + 1
 	return nil
 }
 
-func (d *dummyCodeResolver) GetRoomPeople(roomId string) (int, error) {
-	return d.rooms[roomId], nil
+func (d *dummyCodeResolver) GetRoomPeople(ctx context.Context, executionId string) (int, error) {
+	return d.rooms[executionId], nil
 }
 
-func (d *dummyCodeResolver) ResetRoomPeople(roomId string) error {
-	d.rooms[roomId] = 0
+func (d *dummyCodeResolver) ResetRoomPeople(ctx context.Context, executionId string) error {
+	d.rooms[executionId] = 0
 	return nil
 }
 
-func (d *dummyCodeResolver) BindCode(ownerId string, quiz Quiz) error {
-	d.entries[quiz.Code] = fmt.Sprintf("%s@%s", ownerId, quiz.Id)
+func (d *dummyCodeResolver) BindCode(ctx context.Context, code string, ref ExecutionRef) error {
+	if _, ok := d.entries[code]; ok {
+		return ErrCodeTaken
+	}
+
+	d.entries[code] = ref
 	return nil
 }
 
-func (d *dummyCodeResolver) UnbindCode(code string) error {
+func (d *dummyCodeResolver) UnbindCode(ctx context.Context, code string) error {
 	delete(d.entries, code)
 	return nil
 }
 
-func (d *dummyCodeResolver) GetQuiz(code string) (string, error) {
-	if quiz, ok := d.entries[code]; ok {
-		return quiz, nil
+func (d *dummyCodeResolver) Resolve(ctx context.Context, code string) (ExecutionRef, error) {
+	if ref, ok := d.entries[NormalizeCode(code)]; ok {
+		return ref, nil
 	}
 
-	return "", ErrNotFound
+	return ExecutionRef{}, ErrNotFound
 }
@@ -1,22 +1,71 @@
 package quizzes
 
+import (
+	"context"
+	"slices"
+	"time"
+)
+
 type dummyEntry struct {
-	ownerId string
-	quizzes []Quiz
+	ownerId     string
+	quizzes     []Quiz
+	executions  []Execution
+	folders     []Folder
+	collections []Collection
 }
 
 func (ent *dummyEntry) _getQuiz(id string) *Quiz {
-	for _, q := range ent.quizzes {
-		if q.Id == id {
-			return &q
+	for i := range ent.quizzes {
+		if ent.quizzes[i].Id == id {
+			return &ent.quizzes[i]
 		}
 	}
 
 	return nil
 }
 
+// dummyCollaborator is a collaborator of the given quiz.
+type dummyCollaborator struct {
+	ownerId string
+	quizId  string
+	Collaborator
+}
+
+// dummyReaction is a reaction of the given user.
+type dummyReaction struct {
+	uid string
+	Reaction
+}
+
 type dummyQuizStoreImpl struct {
-	entries []dummyEntry
+	entries       []dummyEntry
+	collaborators []dummyCollaborator
+	reactions     []dummyReaction
+	participants  []dummyParticipant
+	answers       []dummyAnswer
+	history       []dummyHistory
+	stats         []dummyStats
+}
+
+type dummyStats struct {
+	ownerId string
+	quizId  string
+	QuizStats
+}
+
+type dummyHistory struct {
+	uid string
+	HistoryEntry
+}
+
+type dummyParticipant struct {
+	ref ExecutionRef
+	Participant
+}
+
+type dummyAnswer struct {
+	ref ExecutionRef
+	PlayerAnswer
 }
 
 func _newDummyStore(placeholder []dummyEntry) Store {
@@ -31,21 +80,34 @@ func _newDummyStore(placeholder []dummyEntry) Store {
 }
 
 func (d *dummyQuizStoreImpl) _getEntry(ownerId string) *dummyEntry {
-	for _, ent := range d.entries {
-		if ent.ownerId == ownerId {
-			return &ent
+	for i := range d.entries {
+		if d.entries[i].ownerId == ownerId {
+			return &d.entries[i]
 		}
 	}
 
 	return nil
 }
 
-func (d *dummyQuizStoreImpl) Upsert(ownerId string, quiz Quiz) error {
+func (d *dummyQuizStoreImpl) _getOrCreateEntry(ownerId string) *dummyEntry {
+	if ent := d._getEntry(ownerId); ent != nil {
+		return ent
+	}
+
+	d.entries = append(d.entries, dummyEntry{ownerId: ownerId})
+	return &d.entries[len(d.entries)-1]
+}
+
+func (d *dummyQuizStoreImpl) Upsert(ctx context.Context, ownerId string, quiz Quiz) error {
 	if ent := d._getEntry(ownerId); ent != nil {
 		if q := ent._getQuiz(quiz.Id); q != nil {
 			q.Title = quiz.Title
 			q.Description = quiz.Description
-			q.Code = quiz.Code
+			q.Visibility = quiz.Visibility
+			q.Tags = quiz.Tags
+			q.Category = quiz.Category
+			q.Language = quiz.Language
+			q.FolderId = quiz.FolderId
 			q.Questions = quiz.Questions
 		} else {
 			ent.quizzes = append(ent.quizzes, quiz)
@@ -60,7 +122,7 @@ func (d *dummyQuizStoreImpl) Upsert(ownerId string, quiz Quiz) error {
 	return nil
 }
 
-func (d *dummyQuizStoreImpl) GetUnique(ownerId, uid string) (Quiz, error) {
+func (d *dummyQuizStoreImpl) GetUnique(ctx context.Context, ownerId, uid string) (Quiz, error) {
 	if ent := d._getEntry(ownerId); ent != nil {
 		if q := ent._getQuiz(uid); q != nil {
 			return *q, nil
@@ -70,7 +132,7 @@ func (d *dummyQuizStoreImpl) GetUnique(ownerId, uid string) (Quiz, error) {
 	return Quiz{}, ErrNotFound
 }
 
-func (d *dummyQuizStoreImpl) GetQuizzes(ownerId string) ([]Quiz, error) {
+func (d *dummyQuizStoreImpl) GetQuizzes(ctx context.Context, ownerId string) ([]Quiz, error) {
 	if ent := d._getEntry(ownerId); ent != nil {
 		return ent.quizzes, nil
 	}
@@ -78,17 +140,60 @@ func (d *dummyQuizStoreImpl) GetQuizzes(ownerId string) ([]Quiz, error) {
 	return []Quiz{}, ErrNotFound
 }
 
-func (d *dummyQuizStoreImpl) Patch(ownerId, uid string, fields []FieldPatchOp) error {
-	quiz, err := d.GetUnique(ownerId, uid)
-	if err != nil {
-		return err
+func (d *dummyQuizStoreImpl) _getQuiz(ownerId, quizId string) *Quiz {
+	if ent := d._getEntry(ownerId); ent != nil {
+		return ent._getQuiz(quizId)
+	}
+
+	return nil
+}
+
+func (d *dummyQuizStoreImpl) _getExecution(ref ExecutionRef) *Execution {
+	if ent := d._getEntry(ref.OwnerId); ent != nil {
+		for i := range ent.executions {
+			if ent.executions[i].QuizId == ref.QuizId && ent.executions[i].Id == ref.ExecutionId {
+				return &ent.executions[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+func (q *Quiz) _getQuestion(id string) *Question {
+	for i := range q.Questions {
+		if q.Questions[i].Id == id {
+			return &q.Questions[i]
+		}
+	}
+
+	return nil
+}
+
+func (d *dummyQuizStoreImpl) Patch(ctx context.Context, ownerId, uid string, fields []FieldPatchOp) error {
+	quiz := d._getQuiz(ownerId, uid)
+	if quiz == nil {
+		return ErrNotFound
 	}
 
 	for _, field := range fields {
 		if field.Op == "replace" {
-			if field.Path == "/title" {
+			switch field.Path {
+			case "/title":
 				quiz.Title = field.Value.(string)
-			} else {
+			case "/description":
+				quiz.Description = field.Value.(string)
+			case "/visibility":
+				quiz.Visibility = Visibility(field.Value.(string))
+			case "/tags":
+				quiz.Tags = field.Value.([]string)
+			case "/category":
+				quiz.Category = field.Value.(string)
+			case "/language":
+				quiz.Language = field.Value.(string)
+			case "/folderId":
+				quiz.FolderId = field.Value.(string)
+			default:
 				return ErrInvalidPatchField
 			}
 		} else {
@@ -99,62 +204,306 @@ func (d *dummyQuizStoreImpl) Patch(ownerId, uid string, fields []FieldPatchOp) e
 	return nil
 }
 
-func (d *dummyQuizStoreImpl) GetUniqueQuestion(ownerId, quizId, questionId string) (Question, error) {
-	for _, u := range d.entries {
-		if u.ownerId == ownerId {
-			for _, q := range u.quizzes {
-				if q.Id == quizId {
-					for _, qu := range q.Questions {
-						if qu.Id == questionId {
-							return qu, nil
-						}
-					}
-				}
-			}
+func (d *dummyQuizStoreImpl) GetUniqueQuestion(ctx context.Context, ownerId, quizId, questionId string) (Question, error) {
+	if q := d._getQuiz(ownerId, quizId); q != nil {
+		if qu := q._getQuestion(questionId); qu != nil {
+			return *qu, nil
 		}
 	}
 
 	return Question{}, ErrNotFound
 }
 
-func (d *dummyQuizStoreImpl) UpsertQuestion(ownerId, quizId string, question Question) error {
-	for _, u := range d.entries {
-		if u.ownerId == ownerId {
-			for _, q := range u.quizzes {
-				if q.Id == quizId {
-					for _, qu := range q.Questions {
-						if qu.Id == question.Id {
-							qu.Title = question.Title
-							qu.Answers = question.Answers
-							return nil
-						}
-					}
-
-					q.Questions = append(q.Questions, question)
-					return nil
-				}
+func (d *dummyQuizStoreImpl) UpsertQuestion(ctx context.Context, ownerId, quizId string, question Question) error {
+	if q := d._getQuiz(ownerId, quizId); q != nil {
+		if qu := q._getQuestion(question.Id); qu != nil {
+			qu.Title = question.Title
+			qu.Answers = question.Answers
+		} else {
+			q.Questions = append(q.Questions, question)
+		}
+	}
+
+	return nil
+}
+
+func (d *dummyQuizStoreImpl) UpdateQuestion(ctx context.Context, ownerId, quizId string, question Question) error {
+	if q := d._getQuiz(ownerId, quizId); q != nil {
+		if qu := q._getQuestion(question.Id); qu != nil {
+			qu.Title = question.Title
+			qu.Answers = question.Answers
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+func (d *dummyQuizStoreImpl) UpsertExecution(ctx context.Context, ownerId, quizId string, execution Execution) error {
+	ent := d._getEntry(ownerId)
+	if ent == nil {
+		return ErrNotFound
+	}
+
+	execution.OwnerId = ownerId
+	execution.QuizId = quizId
+	for i := range ent.executions {
+		if ent.executions[i].Id == execution.Id {
+			execution.ResultsRecorded = ent.executions[i].ResultsRecorded
+			ent.executions[i] = execution
+			return nil
+		}
+	}
+
+	ent.executions = append(ent.executions, execution)
+	return nil
+}
+
+func (d *dummyQuizStoreImpl) GetExecution(ctx context.Context, ownerId, quizId, executionId string) (Execution, error) {
+	if ent := d._getEntry(ownerId); ent != nil {
+		for _, e := range ent.executions {
+			if e.QuizId == quizId && e.Id == executionId {
+				return e, nil
+			}
+		}
+	}
+
+	return Execution{}, ErrNotFound
+}
+
+func (d *dummyQuizStoreImpl) GetExecutions(ctx context.Context, ownerId, quizId string) ([]Execution, error) {
+	arr := make([]Execution, 0)
+	if ent := d._getEntry(ownerId); ent != nil {
+		for _, e := range ent.executions {
+			if e.QuizId == quizId {
+				arr = append(arr, e)
+			}
+		}
+	}
+
+	return arr, nil
+}
+
+func (d *dummyQuizStoreImpl) DeleteAll(ctx context.Context, ownerId string) error {
+	d.participants = slices.DeleteFunc(d.participants, func(p dummyParticipant) bool {
+		return p.ref.OwnerId == ownerId || p.Id == ownerId
+	})
+	d.answers = slices.DeleteFunc(d.answers, func(a dummyAnswer) bool {
+		return a.ref.OwnerId == ownerId || a.PlayerId == ownerId
+	})
+	d.history = slices.DeleteFunc(d.history, func(h dummyHistory) bool {
+		return h.uid == ownerId
+	})
+	d.stats = slices.DeleteFunc(d.stats, func(s dummyStats) bool {
+		return s.ownerId == ownerId
+	})
+
+	for i := range d.entries {
+		if d.entries[i].ownerId == ownerId {
+			d.entries = append(d.entries[:i], d.entries[i+1:]...)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (d *dummyQuizStoreImpl) UpsertCollaborator(ctx context.Context, ownerId, quizId string, collaborator Collaborator) error {
+	for i, c := range d.collaborators {
+		if c.ownerId == ownerId && c.quizId == quizId && c.Uid == collaborator.Uid {
+			d.collaborators[i].Collaborator = collaborator
+			return nil
+		}
+	}
+
+	d.collaborators = append(d.collaborators, dummyCollaborator{ownerId: ownerId, quizId: quizId, Collaborator: collaborator})
+	return nil
+}
+
+func (d *dummyQuizStoreImpl) GetCollaborators(ctx context.Context, ownerId, quizId string) ([]Collaborator, error) {
+	arr := make([]Collaborator, 0)
+	for _, c := range d.collaborators {
+		if c.ownerId == ownerId && c.quizId == quizId {
+			arr = append(arr, c.Collaborator)
+		}
+	}
+
+	return arr, nil
+}
+
+func (d *dummyQuizStoreImpl) DeleteCollaborator(ctx context.Context, ownerId, quizId, uid string) error {
+	for i, c := range d.collaborators {
+		if c.ownerId == ownerId && c.quizId == quizId && c.Uid == uid {
+			d.collaborators = append(d.collaborators[:i], d.collaborators[i+1:]...)
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+func (d *dummyQuizStoreImpl) GetSharedQuiz(ctx context.Context, uid, quizId string) (SharedQuiz, error) {
+	for _, c := range d.collaborators {
+		if c.Uid == uid && c.quizId == quizId {
+			return SharedQuiz{QuizId: quizId, OwnerId: c.ownerId, Role: c.Role}, nil
+		}
+	}
+
+	return SharedQuiz{}, ErrNotFound
+}
+
+func (d *dummyQuizStoreImpl) GetSharedQuizzes(ctx context.Context, uid string) ([]SharedQuiz, error) {
+	arr := make([]SharedQuiz, 0)
+	for _, c := range d.collaborators {
+		if c.Uid == uid {
+			arr = append(arr, SharedQuiz{QuizId: c.quizId, OwnerId: c.ownerId, Role: c.Role})
+		}
+	}
+
+	return arr, nil
+}
+
+func (d *dummyQuizStoreImpl) GetListed(ctx context.Context) ([]OwnedQuiz, error) {
+	arr := make([]OwnedQuiz, 0)
+	for _, ent := range d.entries {
+		for _, quiz := range ent.quizzes {
+			if quiz.IsListed() {
+				arr = append(arr, OwnedQuiz{OwnerId: ent.ownerId, Quiz: quiz})
+			}
+		}
+	}
+
+	return arr, nil
+}
+
+func (d *dummyQuizStoreImpl) UpdateReaction(ctx context.Context, uid, ownerId, quizId string, update func(reaction *Reaction)) error {
+	old, index := Reaction{QuizId: quizId, OwnerId: ownerId}, -1
+	for i, r := range d.reactions {
+		if r.uid == uid && r.QuizId == quizId {
+			old, index = r.Reaction, i
+		}
+	}
+
+	reaction := old
+	update(&reaction)
+	reaction.UpdatedAt = time.Now().UTC()
+
+	if index >= 0 {
+		d.reactions = append(d.reactions[:index], d.reactions[index+1:]...)
+	}
+	if !reaction.IsEmpty() {
+		d.reactions = append(d.reactions, dummyReaction{uid: uid, Reaction: reaction})
+	}
+
+	if quiz := d._getQuiz(ownerId, quizId); quiz != nil {
+		delta := DeltaOf(old, reaction)
+		quiz.Stars += delta.Stars
+		quiz.RatingCount += delta.RatingCount
+		quiz.RatingSum += delta.RatingSum
+	}
+
+	return nil
+}
+
+func (d *dummyQuizStoreImpl) GetReactions(ctx context.Context, uid string) ([]Reaction, error) {
+	arr := make([]Reaction, 0)
+	for _, r := range d.reactions {
+		if r.uid == uid {
+			arr = append(arr, r.Reaction)
+		}
+	}
+
+	return arr, nil
+}
+
+func (d *dummyQuizStoreImpl) GetQuizzesInFolder(ctx context.Context, ownerId, folderId string) ([]Quiz, error) {
+	arr := make([]Quiz, 0)
+	if ent := d._getEntry(ownerId); ent != nil {
+		for _, quiz := range ent.quizzes {
+			if quiz.FolderId == folderId {
+				arr = append(arr, quiz)
 			}
 		}
 	}
 
+	return arr, nil
+}
+
+func (d *dummyQuizStoreImpl) UpsertFolder(ctx context.Context, ownerId string, folder Folder) error {
+	ent := d._getOrCreateEntry(ownerId)
+	for i := range ent.folders {
+		if ent.folders[i].Id == folder.Id {
+			ent.folders[i] = folder
+			return nil
+		}
+	}
+
+	ent.folders = append(ent.folders, folder)
 	return nil
 }
 
-func (d *dummyQuizStoreImpl) UpdateQuestion(ownerId, quizId string, question Question) error {
-	for _, u := range d.entries {
-		if u.ownerId == ownerId {
-			for _, q := range u.quizzes {
-				if q.Id == quizId {
-					for _, qu := range q.Questions {
-						if qu.Id == question.Id {
-							qu.Title = question.Title
-							qu.Answers = question.Answers
-							return nil
-						}
-					}
+func (d *dummyQuizStoreImpl) GetFolders(ctx context.Context, ownerId string) ([]Folder, error) {
+	arr := make([]Folder, 0)
+	if ent := d._getEntry(ownerId); ent != nil {
+		arr = append(arr, ent.folders...)
+	}
+
+	return arr, nil
+}
 
-					return ErrNotFound
-				}
+func (d *dummyQuizStoreImpl) DeleteFolder(ctx context.Context, ownerId, folderId string) error {
+	if ent := d._getEntry(ownerId); ent != nil {
+		for i := range ent.folders {
+			if ent.folders[i].Id == folderId {
+				ent.folders = append(ent.folders[:i], ent.folders[i+1:]...)
+				return nil
+			}
+		}
+	}
+
+	return ErrNotFound
+}
+
+func (d *dummyQuizStoreImpl) UpsertCollection(ctx context.Context, ownerId string, collection Collection) error {
+	ent := d._getOrCreateEntry(ownerId)
+	for i := range ent.collections {
+		if ent.collections[i].Id == collection.Id {
+			ent.collections[i] = collection
+			return nil
+		}
+	}
+
+	ent.collections = append(ent.collections, collection)
+	return nil
+}
+
+func (d *dummyQuizStoreImpl) GetCollection(ctx context.Context, ownerId, collectionId string) (Collection, error) {
+	if ent := d._getEntry(ownerId); ent != nil {
+		for _, c := range ent.collections {
+			if c.Id == collectionId {
+				return c, nil
+			}
+		}
+	}
+
+	return Collection{}, ErrNotFound
+}
+
+func (d *dummyQuizStoreImpl) GetCollections(ctx context.Context, ownerId string) ([]Collection, error) {
+	arr := make([]Collection, 0)
+	if ent := d._getEntry(ownerId); ent != nil {
+		arr = append(arr, ent.collections...)
+	}
+
+	return arr, nil
+}
+
+func (d *dummyQuizStoreImpl) DeleteCollection(ctx context.Context, ownerId, collectionId string) error {
+	if ent := d._getEntry(ownerId); ent != nil {
+		for i := range ent.collections {
+			if ent.collections[i].Id == collectionId {
+				ent.collections = append(ent.collections[:i], ent.collections[i+1:]...)
+				return nil
 			}
 		}
 	}
@@ -167,3 +516,124 @@ func _createDummyStore() Store {
 		entries: make([]dummyEntry, 0),
 	}
 }
+
+func (d *dummyQuizStoreImpl) InsertFork(ctx context.Context, uid string, fork Quiz) error {
+	if d._getQuiz(fork.ForkedFrom.OwnerId, fork.ForkedFrom.QuizId) == nil {
+		return ErrNotFound
+	}
+
+	ent := d._getOrCreateEntry(uid)
+	ent.quizzes = append(ent.quizzes, fork)
+	// Entries may have moved while appending.
+	d._getQuiz(fork.ForkedFrom.OwnerId, fork.ForkedFrom.QuizId).Forks++
+	return nil
+}
+
+func (d *dummyQuizStoreImpl) AddParticipant(ctx context.Context, ref ExecutionRef, participant Participant) error {
+	for _, p := range d.participants {
+		if p.ref == ref && p.Id == participant.Id {
+			return nil
+		}
+	}
+
+	d.participants = append(d.participants, dummyParticipant{ref: ref, Participant: participant})
+	return nil
+}
+
+func (d *dummyQuizStoreImpl) GetParticipants(ctx context.Context, ref ExecutionRef) ([]Participant, error) {
+	arr := make([]Participant, 0)
+	for _, p := range d.participants {
+		if p.ref == ref {
+			arr = append(arr, p.Participant)
+		}
+	}
+
+	return arr, nil
+}
+
+func (d *dummyQuizStoreImpl) AddAnswer(ctx context.Context, ref ExecutionRef, answer PlayerAnswer) error {
+	for _, a := range d.answers {
+		if a.ref == ref && a.QuestionId == answer.QuestionId && a.PlayerId == answer.PlayerId {
+			return ErrAlreadyAnswered
+		}
+	}
+
+	d.answers = append(d.answers, dummyAnswer{ref: ref, PlayerAnswer: answer})
+	return nil
+}
+
+func (d *dummyQuizStoreImpl) GetAnswers(ctx context.Context, ref ExecutionRef) ([]PlayerAnswer, error) {
+	arr := make([]PlayerAnswer, 0)
+	for _, a := range d.answers {
+		if a.ref == ref {
+			arr = append(arr, a.PlayerAnswer)
+		}
+	}
+
+	return arr, nil
+}
+
+func (d *dummyQuizStoreImpl) UpsertHistory(ctx context.Context, uid string, entry HistoryEntry) error {
+	for i := range d.history {
+		if d.history[i].uid == uid && d.history[i].ExecutionId == entry.ExecutionId {
+			d.history[i].HistoryEntry = entry
+			return nil
+		}
+	}
+
+	d.history = append(d.history, dummyHistory{uid: uid, HistoryEntry: entry})
+	return nil
+}
+
+func (d *dummyQuizStoreImpl) GetHistory(ctx context.Context, uid string, offset, limit int) ([]HistoryEntry, int, error) {
+	all := make([]HistoryEntry, 0)
+	for _, h := range d.history {
+		if h.uid == uid {
+			all = append(all, h.HistoryEntry)
+		}
+	}
+
+	slices.SortStableFunc(all, func(a, b HistoryEntry) int {
+		return b.PlayedAt.Compare(a.PlayedAt)
+	})
+
+	arr := make([]HistoryEntry, 0)
+	for i := offset; i < len(all) && len(arr) < limit; i++ {
+		arr = append(arr, all[i])
+	}
+
+	return arr, len(all), nil
+}
+
+func (d *dummyQuizStoreImpl) AddStats(ctx context.Context, ref ExecutionRef, delta QuizStats) error {
+	execution := d._getExecution(ref)
+	if execution == nil {
+		return ErrNotFound
+	} else if execution.ResultsRecorded {
+		return nil
+	}
+
+	execution.ResultsRecorded = true
+	ownerId, quizId := ref.OwnerId, ref.QuizId
+	for i := range d.stats {
+		if d.stats[i].ownerId == ownerId && d.stats[i].quizId == quizId {
+			d.stats[i].Add(delta)
+			return nil
+		}
+	}
+
+	stats := dummyStats{ownerId: ownerId, quizId: quizId}
+	stats.Add(delta)
+	d.stats = append(d.stats, stats)
+	return nil
+}
+
+func (d *dummyQuizStoreImpl) GetStats(ctx context.Context, ownerId, quizId string) (QuizStats, error) {
+	for _, s := range d.stats {
+		if s.ownerId == ownerId && s.quizId == quizId {
+			return s.QuizStats, nil
+		}
+	}
+
+	return QuizStats{}, nil
+}
@@ -0,0 +1,97 @@
+package quizzes
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidFolder     = errors.New("invalid folder")
+	ErrFolderNotEmpty    = errors.New("folder not empty")
+	ErrInvalidCollection = errors.New("invalid collection")
+)
+
+const (
+	// RootFolder is the folder of quizzes which weren't moved to any folder.
+	RootFolder = ""
+	// MaxFolderDepth is how deep folders may be nested.
+	MaxFolderDepth = 8
+	// MaxNameLength is the maximum length of a folder or a collection name, in characters.
+	MaxNameLength = 64
+)
+
+// Folder organizes quizzes of a user as a tree, stored under "users/{uid}/folders/{folderId}".
+// A quiz belongs to a single folder, see Quiz.FolderId.
+type Folder struct {
+	Id   string `firestore:"-" json:"id"`
+	Name string `firestore:"name" json:"name"`
+	// ParentId is RootFolder for top-level folders.
+	ParentId  string    `firestore:"parentId" json:"parentId"`
+	CreatedAt time.Time `firestore:"createdAt" json:"createdAt"`
+}
+
+// Collection is an ordered selection of quizzes of a user, stored under
+// "users/{uid}/collections/{collectionId}". Unlike folders, a quiz may be part of many collections.
+type Collection struct {
+	Id          string    `firestore:"-" json:"id"`
+	Name        string    `firestore:"name" json:"name"`
+	Description string    `firestore:"description" json:"description"`
+	QuizIds     []string  `firestore:"quizIds" json:"quizIds"`
+	CreatedAt   time.Time `firestore:"createdAt" json:"createdAt"`
+}
+
+// PatchFolderRequest lists folder fields which may be changed, omitted fields are kept as is.
+type PatchFolderRequest struct {
+	Name *string `json:"name,omitempty"`
+	// ParentId moves the folder, RootFolder ("") moves it to the top level.
+	ParentId *string `json:"parentId,omitempty"`
+}
+
+// PatchCollectionRequest lists collection fields which may be changed, omitted fields are kept as is.
+type PatchCollectionRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// CollectionWithQuizzes is a collection along with its quizzes, removed ones are skipped.
+type CollectionWithQuizzes struct {
+	Collection
+	Quizzes []Quiz `json:"quizzes"`
+}
+
+// folderDepth returns the depth of the given folder (1 for top-level folders), and whether
+// its ancestors are known and free of cycles.
+func folderDepth(folders map[string]Folder, id string) (int, bool) {
+	depth := 0
+	for id != RootFolder {
+		folder, ok := folders[id]
+		if !ok || depth > len(folders) {
+			return depth, false
+		}
+
+		id = folder.ParentId
+		depth++
+	}
+
+	return depth, true
+}
+
+// isDescendant reports whether the given folder is the ancestor folder itself, or one of its descendants.
+func isDescendant(folders map[string]Folder, id, ancestor string) bool {
+	for i := 0; id != RootFolder && i <= len(folders); i++ {
+		if id == ancestor {
+			return true
+		}
+		id = folders[id].ParentId
+	}
+
+	return false
+}
+
+// normalizeName trims the given folder or collection name, and reports whether it is valid.
+func normalizeName(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	return name, name != "" && utf8.RuneCountInString(name) <= MaxNameLength
+}
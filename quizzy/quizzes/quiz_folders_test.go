@@ -0,0 +1,161 @@
+package quizzes
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func _strPtr(s string) *string {
+	return &s
+}
+
+func _createTitledQuiz(t *testing.T, svc QuizService, ownerId, title string) string {
+	quiz := Quiz{Id: uuid.New().String(), Title: title, Questions: make([]Question, 0)}
+	assert.Nil(t, svc.Create(context.Background(), ownerId, quiz))
+	return quiz.Id
+}
+
+func TestFoldersNesting(t *testing.T) {
+	svc := _createDummyQuizService()
+	ctx := context.Background()
+
+	root, err := svc.CreateFolder(ctx, "owner", "  Histoire ", RootFolder)
+	assert.Nil(t, err)
+	assert.Equal(t, "Histoire", root.Name)
+
+	child, err := svc.CreateFolder(ctx, "owner", "Antiquité", root.Id)
+	assert.Nil(t, err)
+
+	// Unknown parents, blank names.
+	_, err = svc.CreateFolder(ctx, "owner", "Orphelin", "unknown")
+	assert.ErrorIs(t, err, ErrInvalidFolder)
+	_, err = svc.CreateFolder(ctx, "owner", " ", RootFolder)
+	assert.ErrorIs(t, err, ErrInvalidFolder)
+
+	// A folder can't be moved below itself.
+	_, err = svc.PatchFolder(ctx, "owner", root.Id, PatchFolderRequest{ParentId: _strPtr(child.Id)})
+	assert.ErrorIs(t, err, ErrInvalidFolder)
+	_, err = svc.PatchFolder(ctx, "owner", root.Id, PatchFolderRequest{ParentId: _strPtr(root.Id)})
+	assert.ErrorIs(t, err, ErrInvalidFolder)
+
+	moved, err := svc.PatchFolder(ctx, "owner", child.Id, PatchFolderRequest{Name: _strPtr("Rome"), ParentId: _strPtr(RootFolder)})
+	assert.Nil(t, err)
+	assert.Equal(t, "Rome", moved.Name)
+	assert.Equal(t, RootFolder, moved.ParentId)
+
+	_, err = svc.PatchFolder(ctx, "owner", "unknown", PatchFolderRequest{Name: _strPtr("Rome")})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	folders, err := svc.GetFolders(ctx, "owner")
+	assert.Nil(t, err)
+	assert.Len(t, folders, 2)
+}
+
+func TestFoldersDepth(t *testing.T) {
+	svc := _createDummyQuizService()
+	ctx := context.Background()
+
+	parent := RootFolder
+	var ids []string
+	for i := 0; i < MaxFolderDepth; i++ {
+		folder, err := svc.CreateFolder(ctx, "owner", "Niveau", parent)
+		assert.Nil(t, err)
+		ids = append(ids, folder.Id)
+		parent = folder.Id
+	}
+
+	_, err := svc.CreateFolder(ctx, "owner", "Trop profond", parent)
+	assert.ErrorIs(t, err, ErrInvalidFolder)
+
+	// Moving a subtree takes its height into account.
+	other, err := svc.CreateFolder(ctx, "owner", "Autre", RootFolder)
+	assert.Nil(t, err)
+	_, err = svc.PatchFolder(ctx, "owner", ids[0], PatchFolderRequest{ParentId: _strPtr(other.Id)})
+	assert.ErrorIs(t, err, ErrInvalidFolder)
+	_, err = svc.PatchFolder(ctx, "owner", ids[1], PatchFolderRequest{ParentId: _strPtr(other.Id)})
+	assert.Nil(t, err)
+}
+
+func TestMoveQuizAndDeleteFolder(t *testing.T) {
+	svc := _createDummyQuizService()
+	ctx := context.Background()
+
+	quizId := _createTitledQuiz(t, svc, "owner", "Rome")
+	_createTitledQuiz(t, svc, "owner", "Athènes")
+
+	folder, err := svc.CreateFolder(ctx, "owner", "Antiquité", RootFolder)
+	assert.Nil(t, err)
+	sub, err := svc.CreateFolder(ctx, "owner", "Rome", folder.Id)
+	assert.Nil(t, err)
+
+	assert.ErrorIs(t, svc.MoveQuiz(ctx, "owner", quizId, "unknown"), ErrInvalidFolder)
+	assert.Nil(t, svc.MoveQuiz(ctx, "owner", quizId, sub.Id))
+
+	inFolder, err := svc.GetAllInFolder(ctx, "owner", sub.Id)
+	assert.Nil(t, err)
+	assert.Len(t, inFolder, 1)
+	assert.Equal(t, quizId, inFolder[0].Id)
+
+	atRoot, err := svc.GetAllInFolder(ctx, "owner", RootFolder)
+	assert.Nil(t, err)
+	assert.Len(t, atRoot, 1)
+
+	_, err = svc.GetAllInFolder(ctx, "owner", "unknown")
+	assert.ErrorIs(t, err, ErrInvalidFolder)
+
+	// Folders must be emptied first.
+	assert.ErrorIs(t, svc.DeleteFolder(ctx, "owner", folder.Id), ErrFolderNotEmpty)
+	assert.ErrorIs(t, svc.DeleteFolder(ctx, "owner", sub.Id), ErrFolderNotEmpty)
+
+	assert.Nil(t, svc.MoveQuiz(ctx, "owner", quizId, RootFolder))
+	assert.Nil(t, svc.DeleteFolder(ctx, "owner", sub.Id))
+	assert.Nil(t, svc.DeleteFolder(ctx, "owner", folder.Id))
+	assert.ErrorIs(t, svc.DeleteFolder(ctx, "owner", folder.Id), ErrNotFound)
+}
+
+func TestCollections(t *testing.T) {
+	svc := _createDummyQuizService()
+	ctx := context.Background()
+
+	first := _createTitledQuiz(t, svc, "owner", "Rome")
+	second := _createTitledQuiz(t, svc, "owner", "Athènes")
+	foreign := _createTitledQuiz(t, svc, "other", "Carthage")
+
+	_, err := svc.CreateCollection(ctx, "owner", "", "")
+	assert.ErrorIs(t, err, ErrInvalidCollection)
+
+	collection, err := svc.CreateCollection(ctx, "owner", "Révisions", "Pour le bac")
+	assert.Nil(t, err)
+	assert.Empty(t, collection.QuizIds)
+
+	_, err = svc.AddToCollection(ctx, "owner", collection.Id, second)
+	assert.Nil(t, err)
+	_, err = svc.AddToCollection(ctx, "owner", collection.Id, first)
+	assert.Nil(t, err)
+	// Adding twice is a no-op, quizzes of other users can't be added.
+	collection, err = svc.AddToCollection(ctx, "owner", collection.Id, first)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{second, first}, collection.QuizIds)
+	_, err = svc.AddToCollection(ctx, "owner", collection.Id, foreign)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	full, err := svc.GetCollection(ctx, "owner", collection.Id)
+	assert.Nil(t, err)
+	assert.Len(t, full.Quizzes, 2)
+	assert.Equal(t, "Athènes", full.Quizzes[0].Title)
+
+	collection, err = svc.RemoveFromCollection(ctx, "owner", collection.Id, second)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{first}, collection.QuizIds)
+
+	patched, err := svc.PatchCollection(ctx, "owner", collection.Id, PatchCollectionRequest{Description: _strPtr("")})
+	assert.Nil(t, err)
+	assert.Equal(t, "Révisions", patched.Name)
+	assert.Equal(t, "", patched.Description)
+
+	assert.Nil(t, svc.DeleteCollection(ctx, "owner", collection.Id))
+	_, err = svc.GetCollection(ctx, "owner", collection.Id)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
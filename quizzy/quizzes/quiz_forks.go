@@ -0,0 +1,50 @@
+package quizzes
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+var (
+	ErrInvalidFork = errors.New("invalid fork")
+)
+
+// ForkSource records the quiz a fork was copied from. The source may have been changed
+// or removed since, it is only kept for attribution.
+type ForkSource struct {
+	QuizId   string    `firestore:"quizId" json:"quizId"`
+	OwnerId  string    `firestore:"ownerId" json:"ownerId"`
+	ForkedAt time.Time `firestore:"forkedAt" json:"forkedAt"`
+}
+
+// NewFork returns a private copy of the given quiz of the given owner, its questions and answers
+// getting new ids. Reactions, folders and forks of the source aren't copied.
+func NewFork(ownerId string, source Quiz) Quiz {
+	questions := make([]Question, 0, len(source.Questions))
+	for _, question := range source.Questions {
+		answers := make([]Answer, 0, len(question.Answers))
+		for _, answer := range question.Answers {
+			answers = append(answers, Answer{Id: uuid.New().String(), Title: answer.Title, IsCorrect: answer.IsCorrect})
+		}
+
+		questions = append(questions, Question{Id: uuid.New().String(), Title: question.Title, Answers: answers})
+	}
+
+	return Quiz{
+		Id:          uuid.New().String(),
+		Title:       source.Title,
+		Description: source.Description,
+		Visibility:  VisibilityPrivate,
+		Tags:        append(make([]string, 0, len(source.Tags)), source.Tags...),
+		Category:    source.Category,
+		Language:    source.Language,
+		FolderId:    RootFolder,
+		ForkedFrom: &ForkSource{
+			QuizId:   source.Id,
+			OwnerId:  ownerId,
+			ForkedAt: time.Now().UTC(),
+		},
+		Questions: questions,
+	}
+}
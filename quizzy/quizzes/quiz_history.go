@@ -0,0 +1,68 @@
+package quizzes
+
+import (
+	"time"
+)
+
+// HistoryEntry is an execution a registered player took part in, stored under
+// "users/{uid}/history/{executionId}" once the execution ended. Entries are copies,
+// so players keep their results when quizzes are changed or removed.
+type HistoryEntry struct {
+	ExecutionId string `firestore:"-" json:"executionId"`
+	OwnerId     string `firestore:"ownerId" json:"ownerId"`
+	QuizId      string `firestore:"quizId" json:"quizId"`
+	QuizTitle   string `firestore:"quizTitle" json:"quizTitle"`
+	// PlayedAt is when the execution was started.
+	PlayedAt time.Time `firestore:"playedAt" json:"playedAt"`
+	Score    int       `firestore:"score" json:"score"`
+	// MaxScore is the number of questions of the quiz when it was played.
+	MaxScore     int `firestore:"maxScore" json:"maxScore"`
+	Rank         int `firestore:"rank" json:"rank"`
+	Participants int `firestore:"participants" json:"participants"`
+}
+
+// HistoryPage is a page of the history of a player, most recent executions first.
+type HistoryPage struct {
+	Data     []HistoryEntry `json:"data"`
+	Total    int            `json:"total"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"pageSize"`
+}
+
+// normalizePage applies defaults and bounds to the given pagination.
+func normalizePage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultPageSize
+	} else if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	return page, pageSize
+}
+
+// NewHistoryEntries returns history entries of registered participants of the given ended execution.
+func NewHistoryEntries(quiz Quiz, execution Execution, results []ParticipantResult) map[string]HistoryEntry {
+	entries := make(map[string]HistoryEntry)
+	for _, result := range results {
+		if !result.Registered {
+			continue
+		}
+
+		entries[result.Id] = HistoryEntry{
+			ExecutionId:  execution.Id,
+			OwnerId:      execution.OwnerId,
+			QuizId:       execution.QuizId,
+			QuizTitle:    quiz.Title,
+			PlayedAt:     execution.CreatedAt,
+			Score:        result.Score,
+			MaxScore:     len(quiz.Questions),
+			Rank:         result.Rank,
+			Participants: len(results),
+		}
+	}
+
+	return entries
+}
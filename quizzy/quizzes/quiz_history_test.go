@@ -0,0 +1,110 @@
+package quizzes
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// _playExecution runs an execution of the given quiz where alice (registered) answers
+// correctly and an anonymous player answers wrongly, then ends it.
+func _playExecution(t *testing.T, svc QuizService, quiz Quiz) Execution {
+	ctx := context.Background()
+	execution, err := svc.StartQuiz(ctx, "owner", quiz)
+	assert.Nil(t, err)
+
+	question := quiz.Questions[0]
+	execution.Status = ExecutionStarted
+	execution.Cursor = 1
+	execution.AskedAt = map[string]time.Time{question.Id: time.Now().UTC()}
+	assert.Nil(t, svc.UpdateExecution(ctx, execution))
+
+	_ = svc.JoinExecution(ctx, execution, Participant{Id: "alice", Nickname: "Alice", Registered: true})
+	_ = svc.JoinExecution(ctx, execution, Participant{Id: "anonymous", Nickname: "Anonymous"})
+	_, _ = svc.AnswerQuestion(ctx, execution, quiz, "alice", question.Id, question.Answers[0].Id)
+	_, _ = svc.AnswerQuestion(ctx, execution, quiz, "anonymous", question.Id, question.Answers[1].Id)
+
+	assert.Nil(t, svc.EndExecution(ctx, execution))
+	return execution
+}
+
+func TestEndExecutionRecordsHistory(t *testing.T) {
+	svc := _createDummyQuizService()
+	ctx := context.Background()
+	quiz := _readyQuiz()
+	_ = svc.Create(ctx, "owner", quiz)
+
+	execution := _playExecution(t, svc, quiz)
+
+	history, err := svc.GetHistory(ctx, "alice", 0, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, history.Total)
+	assert.Equal(t, DefaultPageSize, history.PageSize)
+	assert.Equal(t, HistoryEntry{
+		ExecutionId:  execution.Id,
+		OwnerId:      "owner",
+		QuizId:       quiz.Id,
+		QuizTitle:    quiz.Title,
+		PlayedAt:     execution.CreatedAt,
+		Score:        1,
+		MaxScore:     1,
+		Rank:         1,
+		Participants: 2,
+	}, history.Data[0])
+
+	// Only registered players have a history.
+	anonymous, _ := svc.GetHistory(ctx, "anonymous", 1, 10)
+	assert.Equal(t, 0, anonymous.Total)
+
+	// Executions ended before their first question aren't recorded.
+	waiting, _ := svc.StartQuiz(ctx, "owner", quiz)
+	_ = svc.JoinExecution(ctx, waiting, Participant{Id: "alice", Registered: true})
+	assert.Nil(t, svc.EndExecution(ctx, waiting))
+
+	history, _ = svc.GetHistory(ctx, "alice", 1, 10)
+	assert.Equal(t, 1, history.Total)
+}
+
+func TestHistoryPagination(t *testing.T) {
+	svc := _createDummyQuizService()
+	ctx := context.Background()
+	quiz := _readyQuiz()
+	_ = svc.Create(ctx, "owner", quiz)
+
+	for i := 0; i < 3; i++ {
+		_playExecution(t, svc, quiz)
+	}
+
+	page, err := svc.GetHistory(ctx, "alice", 1, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, page.Total)
+	assert.Len(t, page.Data, 2)
+	assert.False(t, page.Data[0].PlayedAt.Before(page.Data[1].PlayedAt))
+
+	page, _ = svc.GetHistory(ctx, "alice", 2, 2)
+	assert.Len(t, page.Data, 1)
+
+	// Deleting the account removes the history.
+	_ = svc.Create(ctx, "alice", _readyQuiz())
+	assert.Nil(t, svc.DeleteAll(ctx, "alice"))
+	page, _ = svc.GetHistory(ctx, "alice", 1, 2)
+	assert.Equal(t, 0, page.Total)
+}
+
+func TestDeleteAllRemovesParticipations(t *testing.T) {
+	svc := _createDummyQuizService()
+	ctx := context.Background()
+	quiz := _readyQuiz()
+	_ = svc.Create(ctx, "owner", quiz)
+	_ = svc.Create(ctx, "alice", _readyQuiz())
+	execution := _playExecution(t, svc, quiz)
+
+	assert.Nil(t, svc.DeleteAll(ctx, "alice"))
+
+	// Only the participation of the deleted user is removed from the execution of another owner.
+	results, err := svc.GetExecutionResults(ctx, quiz, execution)
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "anonymous", results[0].Id)
+}
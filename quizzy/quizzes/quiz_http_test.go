@@ -1,21 +1,27 @@
 package quizzes
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/gavv/httpexpect/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"net/http"
 	"quizzy.app/backend/quizzy/auth"
+	"strings"
 	"testing"
+	"time"
 )
 
 func _configureTestHandler(id auth.Identity, data []dummyEntry) http.HandlerFunc {
 	eng := gin.Default()
 	rt := eng.Group("", auth.ProvideAuthenticator(&auth.DummyAuthenticator{PlaceHolder: id}))
 	con := Controller{
-		Service: &QuizServiceImpl{store: _newDummyStore(data)},
+		Service: &QuizServiceImpl{store: _newDummyStore(data), resolver: _newDummyCodeResolver()},
 	}
 	con.ConfigureRouting(rt)
 
@@ -27,9 +33,23 @@ func _fakeId() auth.Identity {
 		Token: "x",
 		Uid:   uuid.New().String(),
 		Email: "test@mail.net",
+		Roles: []auth.Role{auth.RoleTeacher},
 	}
 }
 
+func TestPlayerCannotManageQuizzes(t *testing.T) {
+	id := _fakeId()
+	id.Roles = []auth.Role{auth.RolePlayer}
+	handler := _configureTestHandler(id, nil)
+
+	httpexpect.Default(t, "").
+		GET("/quiz").
+		WithHeader("Authorization", "Bearer x").
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusForbidden)
+}
+
 func TestPostQuiz(t *testing.T) {
 	id := _fakeId()
 	handler := _configureTestHandler(id, nil)
@@ -65,3 +85,490 @@ func TestPostQuizWithoutAuthorization(t *testing.T) {
 		NoContent().
 		Status(http.StatusUnauthorized)
 }
+
+func TestStartQuizTwice(t *testing.T) {
+	id := _fakeId()
+	quiz := _readyQuiz()
+	handler := _configureTestHandler(id, []dummyEntry{{ownerId: id.Uid, quizzes: []Quiz{quiz}}})
+	ex := httpexpect.Default(t, "")
+
+	var first, second Execution
+	ex.POST(fmt.Sprintf("/quiz/%s/start", quiz.Id)).
+		WithHandler(handler).
+		WithHeader("Authorization", "Bearer x").
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Decode(&first)
+
+	resp := ex.POST(fmt.Sprintf("/quiz/%s/start", quiz.Id)).
+		WithHandler(handler).
+		WithHeader("Authorization", "Bearer x").
+		Expect().
+		Status(http.StatusCreated)
+	resp.JSON().Object().Decode(&second)
+	resp.Headers().
+		Value("Location").
+		Array().
+		HasValue(0, fmt.Sprintf("http://localhost:8000/execution/%s", second.Code))
+
+	assert.NotEqual(t, first.Code, second.Code)
+
+	ex.GET(fmt.Sprintf("/quiz/%s/executions", quiz.Id)).
+		WithHandler(handler).
+		WithHeader("Authorization", "Bearer x").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Length().IsEqual(2)
+
+	ex.GET(fmt.Sprintf("/quiz/%s/executions/%s", quiz.Id, first.Id)).
+		WithHandler(handler).
+		WithHeader("Authorization", "Bearer x").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("code").IsEqual(first.Code)
+}
+
+// unavailableCodeResolver fails to reserve any code, like an unreachable Redis.
+type unavailableCodeResolver struct {
+	*dummyCodeResolver
+}
+
+func (r unavailableCodeResolver) BindCode(ctx context.Context, code string, ref ExecutionRef) error {
+	return errors.New("connection refused")
+}
+
+func TestStartQuizBackendFailure(t *testing.T) {
+	id := _fakeId()
+	quiz := _readyQuiz()
+	eng := gin.Default()
+	rt := eng.Group("", auth.ProvideAuthenticator(&auth.DummyAuthenticator{PlaceHolder: id}))
+	con := Controller{
+		Service: &QuizServiceImpl{
+			store:    _newDummyStore([]dummyEntry{{ownerId: id.Uid, quizzes: []Quiz{quiz}}}),
+			resolver: unavailableCodeResolver{_newDummyCodeResolver()},
+		},
+	}
+	con.ConfigureRouting(rt)
+
+	httpexpect.Default(t, "").POST(fmt.Sprintf("/quiz/%s/start", quiz.Id)).
+		WithHandler(eng).
+		WithHeader("Authorization", "Bearer x").
+		Expect().
+		Status(http.StatusInternalServerError)
+}
+
+func TestGuestCanOnlyPlay(t *testing.T) {
+	id := _fakeId()
+	quiz := _readyQuiz()
+	svc := &QuizServiceImpl{store: _newDummyStore([]dummyEntry{{ownerId: id.Uid, quizzes: []Quiz{quiz}}}), resolver: _newDummyCodeResolver()}
+	guests := auth.NewGuestIssuer("secret", time.Hour)
+
+	eng := gin.Default()
+	rt := eng.Group("", auth.ProvideAuthenticator(&auth.GuestAuthenticator{Issuer: guests, Next: &auth.DummyAuthenticator{PlaceHolder: id}}))
+	con := Controller{Service: svc, Guests: guests}
+	con.ConfigureRouting(rt)
+	ex := httpexpect.Default(t, "")
+
+	execution, _ := svc.StartQuiz(context.Background(), id.Uid, quiz)
+
+	var guest CreateGuestResponse
+	ex.POST(fmt.Sprintf("/execution/%s/guest", strings.ToLower(execution.Code))).
+		WithHandler(eng).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Decode(&guest)
+
+	assert.NotEmpty(t, guest.Token)
+	assert.True(t, strings.HasPrefix(guest.Uid, "guest:"))
+
+	// Guest identities are never accepted on authoring routes.
+	ex.GET("/quiz").
+		WithHandler(eng).
+		WithHeader("Authorization", "Bearer "+guest.Token).
+		Expect().
+		Status(http.StatusForbidden)
+
+	ex.POST("/execution/UNKNOWN/guest").
+		WithHandler(eng).
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+// fakeUserResolver resolves users from a fixed list, by username.
+type fakeUserResolver map[string]UserRef
+
+func (r fakeUserResolver) FindUser(ctx context.Context, emailOrUsername string) (UserRef, bool, error) {
+	user, ok := r[emailOrUsername]
+	return user, ok, nil
+}
+
+// _configureSharedHandler serves the same quizzes to the given identity, through the given service.
+func _configureSharedHandler(id auth.Identity, svc QuizService, users fakeUserResolver) http.HandlerFunc {
+	eng := gin.Default()
+	rt := eng.Group("", auth.ProvideAuthenticator(&auth.DummyAuthenticator{PlaceHolder: id}))
+	con := Controller{Service: svc, Users: users}
+	con.ConfigureRouting(rt)
+
+	return eng.ServeHTTP
+}
+
+func TestCollaboratorRoles(t *testing.T) {
+	owner, editor, viewer := _fakeId(), _fakeId(), _fakeId()
+	quiz := _readyQuiz()
+	svc := &QuizServiceImpl{
+		store:    _newDummyStore([]dummyEntry{{ownerId: owner.Uid, quizzes: []Quiz{quiz}}}),
+		resolver: _newDummyCodeResolver(),
+	}
+	users := fakeUserResolver{
+		"ed":  {Uid: editor.Uid, Username: "ed", Roles: []auth.Role{auth.RoleTeacher}},
+		"vic": {Uid: viewer.Uid, Username: "vic", Roles: []auth.Role{auth.RoleAdmin}},
+		"pat": {Uid: "player", Username: "pat", Roles: []auth.Role{auth.RolePlayer}},
+	}
+	ex := httpexpect.Default(t, "")
+	quizPath := fmt.Sprintf("/quiz/%s", quiz.Id)
+	patch := PatchQuizRequest{{Op: "replace", Path: "/title", Value: "renamed"}}
+
+	ownerHandler := _configureSharedHandler(owner, svc, users)
+	editorHandler := _configureSharedHandler(editor, svc, users)
+	viewerHandler := _configureSharedHandler(viewer, svc, users)
+
+	ex.GET(quizPath).WithHandler(editorHandler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusNotFound)
+
+	ex.POST(quizPath+"/collaborators").WithHandler(ownerHandler).WithHeader("Authorization", "Bearer x").
+		WithJSON(InviteCollaboratorRequest{User: "ed", Role: QuizRoleEditor}).
+		Expect().Status(http.StatusCreated)
+	ex.POST(quizPath+"/collaborators").WithHandler(ownerHandler).WithHeader("Authorization", "Bearer x").
+		WithJSON(InviteCollaboratorRequest{User: "vic", Role: QuizRoleViewer}).
+		Expect().Status(http.StatusCreated)
+	ex.POST(quizPath+"/collaborators").WithHandler(ownerHandler).WithHeader("Authorization", "Bearer x").
+		WithJSON(InviteCollaboratorRequest{User: "nobody", Role: QuizRoleViewer}).
+		Expect().Status(http.StatusNotFound)
+	// Players can't reach quiz routes, they can't collaborate.
+	ex.POST(quizPath+"/collaborators").WithHandler(ownerHandler).WithHeader("Authorization", "Bearer x").
+		WithJSON(InviteCollaboratorRequest{User: "pat", Role: QuizRoleViewer}).
+		Expect().Status(http.StatusBadRequest)
+
+	// Editors may edit the quiz, but not manage its collaborators.
+	ex.PATCH(quizPath).WithHandler(editorHandler).WithHeader("Authorization", "Bearer x").
+		WithJSON(patch).
+		Expect().Status(http.StatusNoContent)
+	ex.POST(quizPath+"/collaborators").WithHandler(editorHandler).WithHeader("Authorization", "Bearer x").
+		WithJSON(InviteCollaboratorRequest{User: "vic", Role: QuizRoleEditor}).
+		Expect().Status(http.StatusForbidden)
+
+	// Viewers may only read.
+	ex.GET(quizPath).WithHandler(viewerHandler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusOK).
+		JSON().Object().Value("title").IsEqual("renamed")
+	ex.PATCH(quizPath).WithHandler(viewerHandler).WithHeader("Authorization", "Bearer x").
+		WithJSON(patch).
+		Expect().Status(http.StatusForbidden)
+	ex.POST(quizPath+"/start").WithHandler(viewerHandler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusForbidden)
+	ex.GET("/quiz/shared").WithHandler(viewerHandler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusOK).
+		JSON().Array().Length().IsEqual(1)
+
+	// A collaborator may leave, but can't remove others.
+	ex.DELETE(fmt.Sprintf("%s/collaborators/%s", quizPath, editor.Uid)).WithHandler(viewerHandler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusForbidden)
+	ex.DELETE(fmt.Sprintf("%s/collaborators/%s", quizPath, viewer.Uid)).WithHandler(viewerHandler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusNoContent)
+	ex.GET(quizPath).WithHandler(viewerHandler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusNotFound)
+
+	ex.GET(quizPath+"/collaborators").WithHandler(ownerHandler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusOK).
+		JSON().Array().Length().IsEqual(1)
+}
+
+// grpcNotFoundStore reports missing quizzes like Firestore does, with a gRPC NotFound error.
+type grpcNotFoundStore struct {
+	Store
+}
+
+func (s grpcNotFoundStore) GetUnique(ctx context.Context, ownerId, quizId string) (Quiz, error) {
+	quiz, err := s.Store.GetUnique(ctx, ownerId, quizId)
+	if errors.Is(err, ErrNotFound) {
+		return quiz, status.Error(codes.NotFound, "no such document")
+	}
+	return quiz, err
+}
+
+func TestProvideSharedQuizWithGrpcNotFound(t *testing.T) {
+	owner, viewer := _fakeId(), _fakeId()
+	quiz := _readyQuiz()
+	svc := &QuizServiceImpl{
+		store:    grpcNotFoundStore{_newDummyStore([]dummyEntry{{ownerId: owner.Uid, quizzes: []Quiz{quiz}}})},
+		resolver: _newDummyCodeResolver(),
+	}
+	_, err := svc.AddCollaborator(context.Background(), owner.Uid, quiz.Id, UserRef{Uid: viewer.Uid, Username: "vic"}, QuizRoleViewer)
+	assert.Nil(t, err)
+	ex := httpexpect.Default(t, "")
+
+	ex.GET(fmt.Sprintf("/quiz/%s", quiz.Id)).WithHandler(_configureSharedHandler(viewer, svc, nil)).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusOK).
+		JSON().Object().Value("id").IsEqual(quiz.Id)
+	ex.GET("/quiz/missing").WithHandler(_configureSharedHandler(viewer, svc, nil)).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusNotFound)
+}
+
+func TestGetLibrary(t *testing.T) {
+	id := _fakeId()
+	index := NewMemorySearchIndex()
+	svc := &QuizServiceImpl{
+		store:    NewIndexedStore(_createDummyStore(), index),
+		resolver: _newDummyCodeResolver(),
+		index:    index,
+	}
+	handler := _configureSharedHandler(id, svc, nil)
+	ex := httpexpect.Default(t, "")
+
+	ex.POST("/quiz").WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		WithJSON(CreateQuizRequest{Title: "Géographie", Visibility: VisibilityPublic, Tags: []string{"Europe", "capitales"}, Language: "fr"}).
+		Expect().Status(http.StatusCreated)
+	ex.POST("/quiz").WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		WithJSON(CreateQuizRequest{Title: "Brouillon"}).
+		Expect().Status(http.StatusCreated)
+	ex.POST("/quiz").WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		WithJSON(CreateQuizRequest{Title: "Invalide", Language: "not a language"}).
+		Expect().Status(http.StatusBadRequest)
+
+	// The library is public, no token required.
+	obj := ex.GET("/library").WithHandler(handler).
+		WithQuery("q", "geographie").
+		WithQuery("tags", "europe,capitales").
+		Expect().Status(http.StatusOK).
+		JSON().Object()
+	obj.Value("total").IsEqual(1)
+	obj.Value("data").Array().Value(0).Object().Value("language").IsEqual("fr")
+	obj.Value("facets").Object().Value("tags").Array().Length().IsEqual(2)
+
+	ex.GET("/library").WithHandler(handler).
+		WithQuery("page", "first").
+		Expect().Status(http.StatusBadRequest)
+}
+
+func TestStarAndRateLibraryQuiz(t *testing.T) {
+	id := _fakeId()
+	quiz := _readyQuiz()
+	quiz.Visibility = VisibilityPublic
+	svc, _ := _reactionService(quiz)
+	handler := _configureSharedHandler(id, svc, nil)
+	ex := httpexpect.Default(t, "")
+	path := fmt.Sprintf("/library/%s", quiz.Id)
+
+	ex.PUT(path+"/star").WithHandler(handler).
+		Expect().Status(http.StatusUnauthorized)
+	ex.PUT(path+"/star").WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusNoContent)
+	ex.PUT("/library/missing/star").WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusNotFound)
+	ex.PUT(path+"/rating").WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		WithJSON(RateQuizRequest{Rating: 4}).
+		Expect().Status(http.StatusNoContent)
+	ex.PUT(path+"/rating").WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		WithJSON(RateQuizRequest{Rating: 9}).
+		Expect().Status(http.StatusBadRequest)
+
+	stored, _ := svc.Get(context.Background(), "owner", quiz.Id)
+	assert.Equal(t, 1, stored.Stars)
+	assert.Equal(t, 4.0, stored.RatingAverage())
+
+	ex.GET("/library").WithHandler(handler).WithQuery("sort", "unknown").
+		Expect().Status(http.StatusBadRequest)
+}
+
+func TestOrganizeQuizzes(t *testing.T) {
+	id := _fakeId()
+	handler := _configureSharedHandler(id, _createDummyQuizService(), nil)
+	ex := httpexpect.Default(t, "")
+
+	ex.POST("/quiz").WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		WithJSON(CreateQuizRequest{Title: "Rome"}).
+		Expect().Status(http.StatusCreated)
+	quizId := ex.GET("/quiz").WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusOK).
+		JSON().Object().Value("data").Array().Value(0).Object().Value("id").String().Raw()
+
+	folderId := ex.POST("/folders").WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		WithJSON(CreateFolderRequest{Name: "Antiquité"}).
+		Expect().Status(http.StatusCreated).
+		JSON().Object().Value("id").String().Raw()
+	ex.POST("/folders").WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		WithJSON(CreateFolderRequest{Name: "Orphelin", ParentId: "unknown"}).
+		Expect().Status(http.StatusBadRequest)
+
+	ex.PUT("/quiz/{id}/folder", quizId).WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		WithJSON(MoveQuizRequest{FolderId: folderId}).
+		Expect().Status(http.StatusNoContent)
+
+	ex.GET("/quiz").WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		WithQuery("folder", folderId).
+		Expect().Status(http.StatusOK).
+		JSON().Object().Value("data").Array().Length().IsEqual(1)
+	ex.GET("/quiz").WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		WithQuery("folder", RootFolderParam).
+		Expect().Status(http.StatusOK).
+		JSON().Object().Value("data").Array().Length().IsEqual(0)
+	ex.GET("/quiz").WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		WithQuery("folder", "unknown").
+		Expect().Status(http.StatusBadRequest)
+
+	ex.DELETE("/folders/{id}", folderId).WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusConflict)
+
+	collectionId := ex.POST("/collections").WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		WithJSON(CreateCollectionRequest{Name: "Révisions"}).
+		Expect().Status(http.StatusCreated).
+		JSON().Object().Value("id").String().Raw()
+	ex.PUT("/collections/{cid}/quizzes/{qid}", collectionId, quizId).WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusOK).
+		JSON().Object().Value("quizIds").Array().IsEqual([]string{quizId})
+	ex.PUT("/collections/{cid}/quizzes/{qid}", collectionId, "unknown").WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusNotFound)
+	ex.GET("/collections/{id}", collectionId).WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusOK).
+		JSON().Object().Value("quizzes").Array().Length().IsEqual(1)
+	ex.DELETE("/collections/{id}", collectionId).WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusNoContent)
+}
+
+func TestForkLibraryQuiz(t *testing.T) {
+	id := _fakeId()
+	quiz := _readyQuiz()
+	quiz.Visibility = VisibilityPublic
+	unlisted := _twoQuestionsQuiz()
+	unlisted.Visibility = VisibilityUnlisted
+	svc, _ := _reactionService(quiz, unlisted)
+	handler := _configureSharedHandler(id, svc, nil)
+	ex := httpexpect.Default(t, "")
+	path := fmt.Sprintf("/library/%s/fork", quiz.Id)
+
+	obj := ex.POST(path).WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusCreated).
+		JSON().Object()
+	obj.Value("forkedFrom").Object().Value("quizId").IsEqual(quiz.Id)
+	obj.Value("visibility").IsEqual(VisibilityPrivate)
+
+	ex.GET("/quiz/{id}", obj.Value("id").String().Raw()).WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusOK).
+		JSON().Object().Value("questions").Array().Length().IsEqual(1)
+	ex.POST("/library/missing/fork").WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusNotFound)
+	// Only quizzes of the library are found without their owner.
+	ex.POST("/library/{id}/fork", unlisted.Id).WithHandler(handler).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusNotFound)
+
+	source, _ := svc.Get(context.Background(), "owner", quiz.Id)
+	assert.Equal(t, 1, source.Forks)
+
+	player := _fakeId()
+	player.Roles = []auth.Role{auth.RolePlayer}
+	ex.POST(path).WithHandler(_configureSharedHandler(player, svc, nil)).WithHeader("Authorization", "Bearer x").
+		Expect().Status(http.StatusForbidden)
+}
+
+func TestGetExecutionAnalyticsAndResults(t *testing.T) {
+	id := _fakeId()
+	quiz := _readyQuiz()
+	svc := &QuizServiceImpl{
+		store:    _newDummyStore([]dummyEntry{{ownerId: id.Uid, quizzes: []Quiz{quiz}}}),
+		resolver: _newDummyCodeResolver(),
+	}
+	handler := _configureSharedHandler(id, svc, nil)
+	ex := httpexpect.Default(t, "")
+
+	execution, err := svc.StartQuiz(context.Background(), id.Uid, quiz)
+	assert.Nil(t, err)
+	execution.Status = ExecutionStarted
+	execution.Cursor = 1
+	_ = svc.JoinExecution(context.Background(), execution, Participant{Id: "player", Nickname: "Player"})
+	_, err = svc.AnswerQuestion(context.Background(), execution, quiz, "player", quiz.Questions[0].Id, quiz.Questions[0].Answers[0].Id)
+	assert.Nil(t, err)
+
+	obj := ex.GET(fmt.Sprintf("/quiz/%s/executions/%s/analytics", quiz.Id, execution.Id)).
+		WithHandler(handler).
+		WithHeader("Authorization", "Bearer x").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	obj.Value("participants").IsEqual(1)
+	question := obj.Value("questions").Array().Value(0).Object()
+	question.Value("correctRate").IsEqual(100)
+	question.Value("answers").Array().Value(0).Object().Value("count").IsEqual(1)
+
+	ex.GET(fmt.Sprintf("/quiz/%s/executions/%s/results", quiz.Id, execution.Id)).
+		WithHandler(handler).
+		WithHeader("Authorization", "Bearer x").
+		Expect().
+		Status(http.StatusOK).
+		ContentType("text/csv", "utf-8").
+		Body().IsEqual("Nickname,Score,Rank,Q1 question\nPlayer,1,1,1\n")
+	ex.GET(fmt.Sprintf("/quiz/%s/executions/%s/results", quiz.Id, execution.Id)).
+		WithHandler(handler).
+		WithHeader("Authorization", "Bearer x").
+		WithQuery("format", "xlsx").
+		Expect().
+		Status(http.StatusOK).
+		Header("Content-Disposition").IsEqual(fmt.Sprintf(`attachment; filename="results-%s.xlsx"`, execution.Code))
+	ex.GET(fmt.Sprintf("/quiz/%s/executions/%s/results", quiz.Id, execution.Id)).
+		WithHandler(handler).
+		WithHeader("Authorization", "Bearer x").
+		WithQuery("format", "pdf").
+		Expect().
+		Status(http.StatusBadRequest)
+
+	ex.GET(fmt.Sprintf("/quiz/%s/executions/unknown/analytics", quiz.Id)).
+		WithHandler(handler).
+		WithHeader("Authorization", "Bearer x").
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+func TestGetQuizStats(t *testing.T) {
+	id := _fakeId()
+	quiz := _readyQuiz()
+	svc := &QuizServiceImpl{
+		store:    _newDummyStore([]dummyEntry{{ownerId: id.Uid, quizzes: []Quiz{quiz}}}),
+		resolver: _newDummyCodeResolver(),
+	}
+	handler := _configureSharedHandler(id, svc, nil)
+	ex := httpexpect.Default(t, "")
+
+	obj := ex.GET(fmt.Sprintf("/quiz/%s/stats", quiz.Id)).
+		WithHandler(handler).
+		WithHeader("Authorization", "Bearer x").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	obj.Value("sessions").IsEqual(0)
+	obj.Value("scoreDistribution").Array().Length().IsEqual(ScoreBucketCount)
+
+	execution, err := svc.StartQuiz(context.Background(), id.Uid, quiz)
+	assert.Nil(t, err)
+	execution.Status = ExecutionStarted
+	execution.Cursor = 1
+	execution.AskedAt = map[string]time.Time{quiz.Questions[0].Id: time.Now().UTC()}
+	_ = svc.JoinExecution(context.Background(), execution, Participant{Id: "player", Nickname: "Player"})
+	_, _ = svc.AnswerQuestion(context.Background(), execution, quiz, "player", quiz.Questions[0].Id, quiz.Questions[0].Answers[0].Id)
+	assert.Nil(t, svc.EndExecution(context.Background(), execution))
+
+	obj = ex.GET(fmt.Sprintf("/quiz/%s/stats", quiz.Id)).
+		WithHandler(handler).
+		WithHeader("Authorization", "Bearer x").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	obj.Value("sessions").IsEqual(1)
+	obj.Value("players").IsEqual(1)
+	obj.Value("averageScoreRate").IsEqual(100)
+	obj.Value("hardestQuestions").Array().Value(0).Object().Value("questionId").IsEqual(quiz.Questions[0].Id)
+
+	ex.GET("/quiz/unknown/stats").
+		WithHandler(handler).
+		WithHeader("Authorization", "Bearer x").
+		Expect().
+		Status(http.StatusNotFound)
+}
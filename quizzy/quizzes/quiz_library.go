@@ -0,0 +1,204 @@
+package quizzes
+
+import (
+	"context"
+	"golang.org/x/text/unicode/norm"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxTags is the maximum number of tags of a single quiz.
+	MaxTags = 10
+	// MaxTagLength is the maximum length of a tag or a category, in characters.
+	MaxTagLength = 32
+
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var languagePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}([-_][a-zA-Z0-9]{2,8})*$`)
+
+// NormalizeTags lower-cases and trims the given tags, dropping empty and duplicated ones.
+func NormalizeTags(tags []string) []string {
+	arr := make([]string, 0, len(tags))
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			arr = append(arr, tag)
+		}
+	}
+
+	return arr
+}
+
+// validTags reports whether the given normalized tags may be stored.
+func validTags(tags []string) bool {
+	if len(tags) > MaxTags {
+		return false
+	}
+	for _, tag := range tags {
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return false
+		}
+	}
+
+	return true
+}
+
+func validCategory(category string) bool {
+	return utf8.RuneCountInString(category) <= MaxTagLength
+}
+
+func validLanguage(language string) bool {
+	return language == "" || languagePattern.MatchString(language)
+}
+
+// NormalizeMetadata normalizes library metadata (tags, category, language) of the given quiz,
+// and reports whether they are valid.
+func (q *Quiz) NormalizeMetadata() bool {
+	q.Tags = NormalizeTags(q.Tags)
+	q.Category = strings.ToLower(strings.TrimSpace(q.Category))
+	q.Language = strings.TrimSpace(q.Language)
+
+	return validTags(q.Tags) && validCategory(q.Category) && validLanguage(q.Language)
+}
+
+// LibraryEntry is a public quiz as listed in the library.
+type LibraryEntry struct {
+	Id            string   `json:"id"`
+	OwnerId       string   `json:"ownerId"`
+	Title         string   `json:"title"`
+	Description   string   `json:"description"`
+	Tags          []string `json:"tags"`
+	Category      string   `json:"category"`
+	Language      string   `json:"language"`
+	QuestionCount int      `json:"questionCount"`
+	Stars         int      `json:"stars"`
+	RatingCount   int      `json:"ratingCount"`
+	RatingAverage float64  `json:"ratingAverage"`
+	Forks         int      `json:"forks"`
+}
+
+// NewLibraryEntry returns the library entry of the given quiz, without its questions.
+func NewLibraryEntry(ownerId string, quiz Quiz) LibraryEntry {
+	return LibraryEntry{
+		Id:            quiz.Id,
+		OwnerId:       ownerId,
+		Title:         quiz.Title,
+		Description:   quiz.Description,
+		Tags:          quiz.Tags,
+		Category:      quiz.Category,
+		Language:      quiz.Language,
+		QuestionCount: len(quiz.Questions),
+		Stars:         quiz.Stars,
+		RatingCount:   quiz.RatingCount,
+		RatingAverage: quiz.RatingAverage(),
+		Forks:         quiz.Forks,
+	}
+}
+
+// SearchSort tells how search results are ordered.
+type SearchSort string
+
+const (
+	// SortRelevance orders by text relevance, it is the default.
+	SortRelevance SearchSort = "relevance"
+	// SortPopular orders by stars, then by number of ratings.
+	SortPopular SearchSort = "popular"
+	// SortRating orders by average rating, then by number of ratings.
+	SortRating SearchSort = "rating"
+)
+
+// IsValid reports whether the sort is a known one.
+func (s SearchSort) IsValid() bool {
+	return s == SortRelevance || s == SortPopular || s == SortRating
+}
+
+// SearchQuery describe a library search, every criterion must match.
+type SearchQuery struct {
+	// Text is matched against titles, descriptions, tags and questions, every word must match.
+	Text string
+	// Tags must all be held by matching quizzes.
+	Tags     []string
+	Category string
+	Language string
+	Sort     SearchSort
+	// Page number, starting at 1.
+	Page     int
+	PageSize int
+}
+
+// Normalize applies defaults and bounds to the query pagination, and normalizes its filters.
+func (q *SearchQuery) Normalize() {
+	q.Tags = NormalizeTags(q.Tags)
+	q.Category = strings.ToLower(strings.TrimSpace(q.Category))
+	q.Language = strings.TrimSpace(q.Language)
+
+	if !q.Sort.IsValid() {
+		q.Sort = SortRelevance
+	}
+	q.Page, q.PageSize = normalizePage(q.Page, q.PageSize)
+}
+
+// Facet counts matching quizzes holding the given value.
+type Facet struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// SearchFacets count every matching quiz, not only the returned page.
+type SearchFacets struct {
+	Tags       []Facet `json:"tags"`
+	Categories []Facet `json:"categories"`
+	Languages  []Facet `json:"languages"`
+}
+
+type SearchResult struct {
+	Data     []LibraryEntry `json:"data"`
+	Total    int            `json:"total"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"pageSize"`
+	Facets   SearchFacets   `json:"facets"`
+}
+
+// SearchIndex indexes public quizzes of the library. It is kept up to date by the store
+// returned by NewIndexedStore, so implementations only have to mirror what they are given.
+type SearchIndex interface {
+	// Index adds or replaces the given quiz, quizzes which aren't listed are removed instead.
+	Index(ctx context.Context, ownerId string, quiz Quiz) error
+
+	// Remove removes the given quiz, if indexed.
+	Remove(ctx context.Context, ownerId, quizId string) error
+
+	// RemoveOwner removes every quiz of the given owner.
+	RemoveOwner(ctx context.Context, ownerId string) error
+
+	// Owner returns the owner of the given quiz, ErrNotFound if it isn't indexed.
+	Owner(ctx context.Context, quizId string) (string, error)
+
+	// Replace removes every indexed quiz, and indexes the given ones instead.
+	Replace(ctx context.Context, quizzes []OwnedQuiz) error
+
+	// Search returns the page of quizzes matching the given normalized query.
+	Search(ctx context.Context, query SearchQuery) (SearchResult, error)
+}
+
+// Tokenize splits the given text into lower-cased words without diacritics,
+// so "Éléphant" matches "elephant".
+func Tokenize(text string) []string {
+	folded := make([]rune, 0, len(text))
+	for _, r := range norm.NFD.String(strings.ToLower(text)) {
+		if !unicode.Is(unicode.Mn, r) {
+			folded = append(folded, r)
+		}
+	}
+
+	return strings.FieldsFunc(string(folded), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,302 @@
+package quizzes
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func _libraryQuiz(id, title string, tags ...string) Quiz {
+	quiz := _readyQuiz()
+	quiz.Id = id
+	quiz.Title = title
+	quiz.Visibility = VisibilityPublic
+	quiz.Tags = tags
+	return quiz
+}
+
+func _search(t *testing.T, index SearchIndex, query SearchQuery) SearchResult {
+	query.Normalize()
+	result, err := index.Search(context.Background(), query)
+	assert.Nil(t, err)
+	return result
+}
+
+func TestTokenizeFoldsDiacritics(t *testing.T) {
+	assert.Equal(t, []string{"l", "elephant", "d", "afrique", "2024"}, Tokenize("L'Éléphant d'Afrique (2024)"))
+}
+
+func TestMemoryIndexSearch(t *testing.T) {
+	index := NewMemorySearchIndex()
+	ctx := context.Background()
+
+	history := _libraryQuiz("history", "Histoire de France", "histoire", "france")
+	history.Category = "history"
+	geography := _libraryQuiz("geography", "Capitales", "geographie", "france")
+	geography.Description = "Les capitales de l'histoire"
+	private := _libraryQuiz("private", "Histoire secrète")
+	private.Visibility = VisibilityUnlisted
+
+	assert.Nil(t, index.Index(ctx, "owner", history))
+	assert.Nil(t, index.Index(ctx, "owner", geography))
+	assert.Nil(t, index.Index(ctx, "owner", private))
+
+	// Title matches rank first, unlisted quizzes are never returned.
+	result := _search(t, index, SearchQuery{Text: "histoire"})
+	assert.Equal(t, 2, result.Total)
+	assert.Equal(t, "history", result.Data[0].Id)
+	assert.Equal(t, "geography", result.Data[1].Id)
+	assert.Equal(t, Facet{Value: "france", Count: 2}, result.Facets.Tags[0])
+
+	// Prefixes match, every word must match.
+	assert.Equal(t, 1, _search(t, index, SearchQuery{Text: "capit hist"}).Total)
+	assert.Equal(t, 0, _search(t, index, SearchQuery{Text: "histoire espagne"}).Total)
+
+	// Filters.
+	assert.Equal(t, 1, _search(t, index, SearchQuery{Tags: []string{"France", "geographie"}}).Total)
+	assert.Equal(t, 1, _search(t, index, SearchQuery{Category: "History"}).Total)
+
+	// Pagination.
+	page := _search(t, index, SearchQuery{Page: 2, PageSize: 1})
+	assert.Equal(t, 2, page.Total)
+	assert.Len(t, page.Data, 1)
+	assert.Equal(t, "history", page.Data[0].Id)
+
+	// Making a quiz private removes it.
+	history.Visibility = VisibilityPrivate
+	assert.Nil(t, index.Index(ctx, "owner", history))
+	assert.Equal(t, 1, _search(t, index, SearchQuery{Text: "histoire"}).Total)
+
+	assert.Nil(t, index.RemoveOwner(ctx, "owner"))
+	assert.Equal(t, 0, _search(t, index, SearchQuery{}).Total)
+}
+
+func TestIndexedStoreFollowsWrites(t *testing.T) {
+	index := NewMemorySearchIndex()
+	svc := &QuizServiceImpl{
+		store:    NewIndexedStore(_createDummyStore(), index),
+		resolver: _newDummyCodeResolver(),
+		index:    index,
+	}
+	ctx := context.Background()
+
+	quiz := _readyQuiz()
+	assert.Nil(t, svc.Create(ctx, "owner", quiz))
+
+	result, _ := svc.Search(ctx, SearchQuery{})
+	assert.Equal(t, 0, result.Total)
+	_, err := svc.GetLibraryOwner(ctx, quiz.Id)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.Nil(t, svc.Patch(ctx, "owner", quiz.Id, []FieldPatchOp{
+		{Op: "replace", Path: "/visibility", Value: "public"},
+		{Op: "replace", Path: "/tags", Value: []any{"Maths", " maths ", "Algèbre"}},
+	}))
+
+	result, _ = svc.Search(ctx, SearchQuery{Tags: []string{"algèbre"}})
+	assert.Equal(t, 1, result.Total)
+	assert.Equal(t, []string{"maths", "algèbre"}, result.Data[0].Tags)
+	ownerId, err := svc.GetLibraryOwner(ctx, quiz.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, "owner", ownerId)
+
+	assert.Nil(t, svc.UpdateQuestion(ctx, "owner", quiz.Id, Question{Id: quiz.Questions[0].Id, Title: "Théorème de Pythagore"}))
+	result, _ = svc.Search(ctx, SearchQuery{Text: "pythagore"})
+	assert.Equal(t, 1, result.Total)
+
+	assert.Nil(t, svc.DeleteAll(ctx, "owner"))
+	result, _ = svc.Search(ctx, SearchQuery{})
+	assert.Equal(t, 0, result.Total)
+	_, err = svc.GetLibraryOwner(ctx, quiz.Id)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestRebuildIndex(t *testing.T) {
+	store := _newDummyStore([]dummyEntry{{ownerId: "owner", quizzes: []Quiz{
+		_libraryQuiz("public", "Public"),
+		{Id: "private", Title: "Private", Visibility: VisibilityPrivate},
+	}}})
+	index := NewMemorySearchIndex()
+
+	assert.Nil(t, RebuildIndex(context.Background(), store, index))
+	assert.Equal(t, 1, _search(t, index, SearchQuery{}).Total)
+
+	// Quizzes unlisted since the last build are dropped.
+	assert.Nil(t, store.Patch(context.Background(), "owner", "public", []FieldPatchOp{
+		{Op: "replace", Path: "/visibility", Value: "private"},
+	}))
+	assert.Nil(t, RebuildIndex(context.Background(), store, index))
+	assert.Equal(t, 0, _search(t, index, SearchQuery{}).Total)
+	_, err := index.Owner(context.Background(), "public")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestIndexRebuilderReadiness(t *testing.T) {
+	store := _newDummyStore([]dummyEntry{{ownerId: "owner", quizzes: []Quiz{_libraryQuiz("first", "First")}}})
+	index := NewMemorySearchIndex()
+	indexer := &IndexRebuilder{Store: store, Index: index, Interval: 10 * time.Millisecond}
+	assert.ErrorIs(t, indexer.Ready(context.Background()), ErrIndexNotReady)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		indexer.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return indexer.Ready(ctx) == nil }, time.Second, 5*time.Millisecond)
+	ownerId, err := index.Owner(ctx, "first")
+	assert.Nil(t, err)
+	assert.Equal(t, "owner", ownerId)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("rebuilder still running once its context is done")
+	}
+}
+
+func TestPatchQuizRejectsInvalidMetadata(t *testing.T) {
+	svc := _createDummyQuizService()
+	quiz := _readyQuiz()
+	_ = svc.Create(context.Background(), "owner", quiz)
+
+	for _, field := range []FieldPatchOp{
+		{Op: "replace", Path: "/tags", Value: "not-a-list"},
+		{Op: "replace", Path: "/tags", Value: []any{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"}},
+		{Op: "replace", Path: "/language", Value: "not a language"},
+	} {
+		assert.ErrorIs(t, svc.Patch(context.Background(), "owner", quiz.Id, []FieldPatchOp{field}), ErrInvalidPatchField)
+	}
+}
+
+func _reactionService(quizzes ...Quiz) (*QuizServiceImpl, SearchIndex) {
+	index := NewMemorySearchIndex()
+	store := NewIndexedStore(_newDummyStore([]dummyEntry{{ownerId: "owner", quizzes: quizzes}}), index)
+	_ = RebuildIndex(context.Background(), store, index)
+
+	return &QuizServiceImpl{store: store, resolver: _newDummyCodeResolver(), index: index}, index
+}
+
+func TestReactionsUpdateAggregates(t *testing.T) {
+	svc, _ := _reactionService(_libraryQuiz("quiz", "Quiz"))
+	ctx := context.Background()
+
+	assert.Nil(t, svc.Star(ctx, "alice", "owner", "quiz"))
+	assert.Nil(t, svc.Star(ctx, "alice", "owner", "quiz"))
+	assert.Nil(t, svc.Star(ctx, "bob", "owner", "quiz"))
+	assert.Nil(t, svc.Rate(ctx, "alice", "owner", "quiz", 5))
+	assert.Nil(t, svc.Rate(ctx, "bob", "owner", "quiz", 2))
+	assert.Nil(t, svc.Rate(ctx, "bob", "owner", "quiz", 4))
+
+	quiz, _ := svc.Get(ctx, "owner", "quiz")
+	assert.Equal(t, 2, quiz.Stars)
+	assert.Equal(t, 2, quiz.RatingCount)
+	assert.Equal(t, 4.5, quiz.RatingAverage())
+
+	assert.Nil(t, svc.Unstar(ctx, "bob", "owner", "quiz"))
+	assert.Nil(t, svc.Unrate(ctx, "alice", "owner", "quiz"))
+
+	quiz, _ = svc.Get(ctx, "owner", "quiz")
+	assert.Equal(t, 1, quiz.Stars)
+	assert.Equal(t, 1, quiz.RatingCount)
+	assert.Equal(t, 4.0, quiz.RatingAverage())
+
+	favorites, _ := svc.GetFavorites(ctx, "alice")
+	assert.Len(t, favorites, 1)
+	assert.Equal(t, 1, favorites[0].Stars)
+}
+
+func TestReactionsRefusals(t *testing.T) {
+	private := _libraryQuiz("private", "Private")
+	private.Visibility = VisibilityPrivate
+	svc, _ := _reactionService(_libraryQuiz("quiz", "Quiz"), private)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, svc.Star(ctx, "alice", "owner", "private"), ErrNotFound)
+	assert.ErrorIs(t, svc.Star(ctx, "alice", "owner", "missing"), ErrNotFound)
+	assert.ErrorIs(t, svc.Rate(ctx, "alice", "owner", "quiz", 6), ErrInvalidRating)
+	assert.ErrorIs(t, svc.Rate(ctx, "alice", "owner", "quiz", 0), ErrInvalidRating)
+	assert.ErrorIs(t, svc.Rate(ctx, "owner", "owner", "quiz", 5), ErrInvalidRating)
+}
+
+func TestDeleteAllWithdrawsReactions(t *testing.T) {
+	svc, _ := _reactionService(_libraryQuiz("quiz", "Quiz"))
+	ctx := context.Background()
+
+	_ = svc.Create(ctx, "alice", _readyQuiz())
+	_ = svc.Star(ctx, "alice", "owner", "quiz")
+	_ = svc.Rate(ctx, "alice", "owner", "quiz", 3)
+	assert.Nil(t, svc.DeleteAll(ctx, "alice"))
+
+	quiz, _ := svc.Get(ctx, "owner", "quiz")
+	assert.Equal(t, 0, quiz.Stars)
+	assert.Equal(t, 0, quiz.RatingCount)
+
+	reactions, _ := svc.GetReactions(ctx, "alice")
+	assert.Empty(t, reactions)
+}
+
+func TestSearchSortedByPopularity(t *testing.T) {
+	svc, _ := _reactionService(_libraryQuiz("a", "A"), _libraryQuiz("b", "B"), _libraryQuiz("c", "C"))
+	ctx := context.Background()
+
+	_ = svc.Star(ctx, "alice", "owner", "c")
+	_ = svc.Star(ctx, "bob", "owner", "c")
+	_ = svc.Star(ctx, "alice", "owner", "b")
+	_ = svc.Rate(ctx, "alice", "owner", "a", 5)
+	_ = svc.Rate(ctx, "alice", "owner", "b", 3)
+
+	ids := func(result SearchResult) []string {
+		arr := make([]string, 0)
+		for _, e := range result.Data {
+			arr = append(arr, e.Id)
+		}
+		return arr
+	}
+
+	popular, _ := svc.Search(ctx, SearchQuery{Sort: SortPopular})
+	assert.Equal(t, []string{"c", "b", "a"}, ids(popular))
+
+	rated, _ := svc.Search(ctx, SearchQuery{Sort: SortRating})
+	assert.Equal(t, []string{"a", "b", "c"}, ids(rated))
+}
+
+func TestForkCopiesQuizWithProvenance(t *testing.T) {
+	source := _libraryQuiz("quiz", "Histoire", "france")
+	source.Stars = 3
+	private := _libraryQuiz("private", "Private")
+	private.Visibility = VisibilityPrivate
+	svc, index := _reactionService(source, private)
+	ctx := context.Background()
+
+	fork, err := svc.Fork(ctx, "alice", "owner", "quiz")
+	assert.Nil(t, err)
+	assert.NotEqual(t, source.Id, fork.Id)
+	assert.Equal(t, VisibilityPrivate, fork.Visibility)
+	assert.Equal(t, 0, fork.Stars)
+	assert.Equal(t, []string{"france"}, fork.Tags)
+	assert.Equal(t, "quiz", fork.ForkedFrom.QuizId)
+	assert.Equal(t, "owner", fork.ForkedFrom.OwnerId)
+	assert.False(t, fork.ForkedFrom.ForkedAt.IsZero())
+
+	// Questions and answers are copied with new ids.
+	stored, err := svc.Get(ctx, "alice", fork.Id)
+	assert.Nil(t, err)
+	assert.Len(t, stored.Questions, 1)
+	assert.NotEqual(t, source.Questions[0].Id, stored.Questions[0].Id)
+	assert.NotEqual(t, source.Questions[0].Answers[0].Id, stored.Questions[0].Answers[0].Id)
+	assert.Equal(t, source.Questions[0].Answers[0].IsCorrect, stored.Questions[0].Answers[0].IsCorrect)
+	assert.Equal(t, "quiz", stored.ForkedFrom.QuizId)
+
+	original, _ := svc.Get(ctx, "owner", "quiz")
+	assert.Equal(t, 1, original.Forks)
+	assert.Equal(t, 1, _search(t, index, SearchQuery{}).Data[0].Forks)
+
+	_, err = svc.Fork(ctx, "alice", "owner", "private")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = svc.Fork(ctx, "owner", "owner", "quiz")
+	assert.ErrorIs(t, err, ErrInvalidFork)
+}
@@ -0,0 +1,76 @@
+package quizzes
+
+// PublicQuiz is a Quiz as seen by other users, correct answers are only revealed to its owner.
+type PublicQuiz struct {
+	Id          string     `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Visibility  Visibility `json:"visibility"`
+	Tags        []string   `json:"tags"`
+	Category    string     `json:"category"`
+	Language    string     `json:"language"`
+	Stars       int        `json:"stars"`
+	RatingCount int        `json:"ratingCount"`
+	// RatingAverage is 0 if the quiz was never rated.
+	RatingAverage float64 `json:"ratingAverage"`
+	Forks         int     `json:"forks"`
+	// ForkedFrom is omitted unless the quiz was copied from another one.
+	ForkedFrom *ForkSource      `json:"forkedFrom,omitempty"`
+	Questions  []PublicQuestion `json:"questions"`
+}
+
+type PublicQuestion struct {
+	Id      string         `json:"id"`
+	Title   string         `json:"title"`
+	Answers []PublicAnswer `json:"answers"`
+}
+
+type PublicAnswer struct {
+	Id        string `json:"id"`
+	Title     string `json:"title"`
+	IsCorrect *bool  `json:"isCorrect,omitempty"`
+}
+
+// normalize fills defaults of quizzes stored before these fields existed.
+func (q *Quiz) normalize() {
+	if q.Visibility == "" {
+		q.Visibility = VisibilityPrivate
+	}
+	if q.Tags == nil {
+		q.Tags = make([]string, 0)
+	}
+}
+
+// Public returns the public view of the quiz, Answer.IsCorrect is stripped unless reveal is set.
+func (q *Quiz) Public(reveal bool) PublicQuiz {
+	questions := make([]PublicQuestion, 0, len(q.Questions))
+	for _, question := range q.Questions {
+		answers := make([]PublicAnswer, 0, len(question.Answers))
+		for _, answer := range question.Answers {
+			pa := PublicAnswer{Id: answer.Id, Title: answer.Title}
+			if reveal {
+				isCorrect := answer.IsCorrect
+				pa.IsCorrect = &isCorrect
+			}
+			answers = append(answers, pa)
+		}
+
+		questions = append(questions, PublicQuestion{Id: question.Id, Title: question.Title, Answers: answers})
+	}
+
+	return PublicQuiz{
+		Id:            q.Id,
+		Title:         q.Title,
+		Description:   q.Description,
+		Visibility:    q.Visibility,
+		Tags:          q.Tags,
+		Category:      q.Category,
+		Language:      q.Language,
+		Stars:         q.Stars,
+		RatingCount:   q.RatingCount,
+		RatingAverage: q.RatingAverage(),
+		Forks:         q.Forks,
+		ForkedFrom:    q.ForkedFrom,
+		Questions:     questions,
+	}
+}
@@ -0,0 +1,69 @@
+package quizzes
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidRating = errors.New("invalid rating")
+)
+
+const (
+	MinRating = 1
+	MaxRating = 5
+)
+
+// Reaction is how a user reacted to a quiz of the library, stored under "users/{uid}/reactions/{quizId}".
+// Aggregates (Quiz.Stars, Quiz.RatingCount and Quiz.RatingSum) are updated along with it,
+// so they never have to be computed by scanning reactions.
+type Reaction struct {
+	QuizId  string `firestore:"-" json:"quizId"`
+	OwnerId string `firestore:"ownerId" json:"ownerId"`
+	Starred bool   `firestore:"starred" json:"starred"`
+	// Rating from MinRating to MaxRating, 0 if the user didn't rate the quiz.
+	Rating    int       `firestore:"rating" json:"rating,omitempty"`
+	UpdatedAt time.Time `firestore:"updatedAt" json:"updatedAt"`
+}
+
+// IsEmpty reports whether the reaction holds nothing, empty reactions aren't stored.
+func (r *Reaction) IsEmpty() bool {
+	return !r.Starred && r.Rating == 0
+}
+
+// ReactionDelta is how quiz aggregates change when a reaction is replaced by another.
+type ReactionDelta struct {
+	Stars       int
+	RatingCount int
+	RatingSum   int
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// DeltaOf returns how quiz aggregates change when the old reaction is replaced by the new one.
+func DeltaOf(old, new Reaction) ReactionDelta {
+	return ReactionDelta{
+		Stars:       boolToInt(new.Starred) - boolToInt(old.Starred),
+		RatingCount: boolToInt(new.Rating > 0) - boolToInt(old.Rating > 0),
+		RatingSum:   new.Rating - old.Rating,
+	}
+}
+
+// IsZero reports whether the aggregates are left untouched.
+func (d ReactionDelta) IsZero() bool {
+	return d.Stars == 0 && d.RatingCount == 0 && d.RatingSum == 0
+}
+
+// RatingAverage returns the average rating of the quiz, 0 if it was never rated.
+func (q *Quiz) RatingAverage() float64 {
+	if q.RatingCount == 0 {
+		return 0
+	}
+
+	return float64(q.RatingSum) / float64(q.RatingCount)
+}
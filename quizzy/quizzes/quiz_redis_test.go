@@ -0,0 +1,114 @@
+package quizzes
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"quizzy.app/backend/quizzy/cfg"
+	"testing"
+	"time"
+)
+
+func _newRedisResolver(t *testing.T) (*RedisCodeResolver, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	rc := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	return NewRedisCodeResolver(rc, cfg.AppConfig{RedisKeyPrefix: "quizzy", CodeTTL: time.Hour}), mr
+}
+
+func TestRedisBindCodeIsNamespaced(t *testing.T) {
+	re, mr := _newRedisResolver(t)
+	ref := ExecutionRef{OwnerId: "owner", QuizId: "quiz", ExecutionId: "exec"}
+
+	assert.Nil(t, re.BindCode(context.Background(), "ABC234", ref))
+	assert.ErrorIs(t, re.BindCode(context.Background(), "ABC234", ExecutionRef{OwnerId: "other", QuizId: "q", ExecutionId: "e"}), ErrCodeTaken)
+
+	assert.True(t, mr.Exists("quizzy:code:ABC234"))
+	assert.False(t, mr.Exists("ABC234"))
+	assert.Equal(t, time.Hour, mr.TTL("quizzy:code:ABC234"))
+
+	resolved, err := re.Resolve(context.Background(), "abc234")
+	assert.Nil(t, err)
+	assert.Equal(t, ref, resolved)
+
+	assert.Nil(t, re.IncrRoomPeople(context.Background(), "exec"))
+	assert.True(t, mr.Exists("quizzy:room:exec"))
+}
+
+func TestRedisKeyspace(t *testing.T) {
+	re, mr := _newRedisResolver(t)
+	_ = re.BindCode(context.Background(), "ABC234", ExecutionRef{OwnerId: "o", QuizId: "q", ExecutionId: "e"})
+	_ = re.IncrRoomPeople(context.Background(), "e")
+	_ = re.IncrRoomPeople(context.Background(), "e")
+	// Keys of other applications must not be listed.
+	_ = mr.Set("other:code:XYZ", "x")
+
+	ks, err := re.Keyspace(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, ks.Codes, 1)
+	assert.Equal(t, "ABC234", ks.Codes[0].Code)
+	assert.Equal(t, "e", ks.Codes[0].Ref.ExecutionId)
+	assert.Len(t, ks.Rooms, 1)
+	assert.Equal(t, ActiveRoom{ExecutionId: "e", Participants: 2}, ks.Rooms[0])
+}
+
+func TestRedisMigrateLegacyKeys(t *testing.T) {
+	re, mr := _newRedisResolver(t)
+	_ = mr.Set("ABC234", "o@q@e")
+	mr.SetTTL("ABC234", time.Minute)
+	_ = mr.Set("room:e", "3")
+	// Not ours: wrong value format, lowercase key, and room of no execution.
+	_ = mr.Set("XYZ789", "something")
+	_ = mr.Set("abcdef", "o@q@e")
+	_ = mr.Set("room:sessions", "12")
+
+	n, err := re.MigrateLegacyKeys(context.Background(), _createDummyStore())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+
+	assert.True(t, mr.Exists("quizzy:code:ABC234"))
+	assert.Equal(t, time.Minute, mr.TTL("quizzy:code:ABC234"))
+	assert.True(t, mr.Exists("quizzy:room:e"))
+	assert.True(t, mr.Exists("XYZ789"))
+	assert.True(t, mr.Exists("abcdef"))
+	assert.True(t, mr.Exists("room:sessions"))
+	assert.False(t, mr.Exists("quizzy:room:sessions"))
+}
+
+func TestRedisMigrateQuizCodes(t *testing.T) {
+	re, mr := _newRedisResolver(t)
+	ctx := context.Background()
+	quiz := _readyQuiz()
+	store := _newDummyStore([]dummyEntry{{ownerId: "owner", quizzes: []Quiz{quiz}}})
+	// Codes were bound to quizzes, and rooms named after codes, before executions existed.
+	_ = mr.Set("A1B2C3", "ownertore@"+quiz.Id+"tore")
+	_ = mr.Set("room:A1B2C3", "4")
+	// Quiz removed since.
+	_ = mr.Set("D4E5F6", "ownertore@removedtore")
+
+	n, err := re.MigrateLegacyKeys(ctx, store)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+
+	ref, err := re.Resolve(ctx, "A1B2C3")
+	assert.Nil(t, err)
+	assert.Equal(t, "owner", ref.OwnerId)
+	assert.Equal(t, quiz.Id, ref.QuizId)
+	assert.Equal(t, time.Hour, mr.TTL("quizzy:code:A1B2C3"))
+	assert.False(t, mr.Exists("A1B2C3"))
+
+	execution, err := store.GetExecution(ctx, "owner", quiz.Id, ref.ExecutionId)
+	assert.Nil(t, err)
+	assert.Equal(t, ExecutionWaiting, execution.Status)
+	assert.Equal(t, "A1B2C3", execution.Code)
+	people, _ := re.GetRoomPeople(ctx, ref.ExecutionId)
+	assert.Equal(t, 4, people)
+
+	assert.True(t, mr.Exists("D4E5F6"))
+
+	// Migrating again is harmless.
+	n, err = re.MigrateLegacyKeys(ctx, store)
+	assert.Nil(t, err)
+	assert.Zero(t, n)
+}
@@ -0,0 +1,89 @@
+package quizzes
+
+import (
+	"sort"
+)
+
+// Outcome is how a participant did on a question.
+type Outcome string
+
+const (
+	OutcomeCorrect Outcome = "correct"
+	OutcomeWrong   Outcome = "wrong"
+	// OutcomeSkipped is also the outcome of questions left unanswered.
+	OutcomeSkipped Outcome = "skipped"
+)
+
+// ParticipantResult is the result of a participant to an execution. The score counts
+// correct answers, participants with the same score share the same rank.
+type ParticipantResult struct {
+	Participant
+	Score int `json:"score"`
+	Rank  int `json:"rank"`
+	// Outcomes of the participant, in the order of quiz questions.
+	Outcomes []Outcome `json:"outcomes"`
+}
+
+// ComputeResults ranks participants of an execution of the given quiz from their stored answers,
+// best scores first. Answers to questions removed from the quiz since are ignored.
+func ComputeResults(quiz Quiz, participants []Participant, answers []PlayerAnswer) []ParticipantResult {
+	indexes := make(map[string]int, len(quiz.Questions))
+	for i, question := range quiz.Questions {
+		indexes[question.Id] = i
+	}
+
+	results := make([]ParticipantResult, 0, len(participants))
+	byPlayer := make(map[string]int, len(participants))
+	add := func(participant Participant) {
+		outcomes := make([]Outcome, len(quiz.Questions))
+		for i := range outcomes {
+			outcomes[i] = OutcomeSkipped
+		}
+
+		byPlayer[participant.Id] = len(results)
+		results = append(results, ParticipantResult{Participant: participant, Outcomes: outcomes})
+	}
+
+	for _, participant := range participants {
+		if _, ok := byPlayer[participant.Id]; !ok {
+			add(participant)
+		}
+	}
+
+	for _, answer := range answers {
+		index, ok := indexes[answer.QuestionId]
+		if !ok || answer.IsSkipped() {
+			continue
+		}
+
+		// Participants are recorded on a best effort basis, their answers must still count.
+		if _, ok2 := byPlayer[answer.PlayerId]; !ok2 {
+			add(Participant{Id: answer.PlayerId, Nickname: NormalizeNickname("", answer.PlayerId)})
+		}
+
+		result := &results[byPlayer[answer.PlayerId]]
+		if answer.IsCorrect {
+			result.Outcomes[index] = OutcomeCorrect
+			result.Score++
+		} else {
+			result.Outcomes[index] = OutcomeWrong
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Nickname < results[j].Nickname
+	})
+
+	for i := range results {
+		if i > 0 && results[i].Score == results[i-1].Score {
+			results[i].Rank = results[i-1].Rank
+		} else {
+			results[i].Rank = i + 1
+		}
+	}
+
+	return results
+}
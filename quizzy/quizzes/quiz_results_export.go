@@ -0,0 +1,224 @@
+package quizzes
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidFormat = errors.New("invalid format")
+)
+
+// ResultsFormat is a file format results of an execution can be exported to.
+type ResultsFormat string
+
+const (
+	FormatCSV  ResultsFormat = "csv"
+	FormatXLSX ResultsFormat = "xlsx"
+)
+
+// IsValid reports whether the format is a known one.
+func (f ResultsFormat) IsValid() bool {
+	return f == FormatCSV || f == FormatXLSX
+}
+
+func (f ResultsFormat) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// tableWriter writes rows of a table as they come, so files are never held in memory.
+// Cells are either strings, ints, or nil for empty cells.
+type tableWriter interface {
+	writeRow(cells []any) error
+	close() error
+}
+
+// WriteResults writes one row per participant (nickname, score, rank and the outcome of every
+// question, 1 if correct, 0 if wrong, empty if skipped) to w, in the given format.
+func WriteResults(w io.Writer, format ResultsFormat, quiz Quiz, results []ParticipantResult) error {
+	var table tableWriter
+	switch format {
+	case FormatCSV:
+		table = &csvTableWriter{w: csv.NewWriter(w)}
+	case FormatXLSX:
+		table = &xlsxTableWriter{zw: zip.NewWriter(w)}
+	default:
+		return ErrInvalidFormat
+	}
+
+	header := []any{"Nickname", "Score", "Rank"}
+	for i, question := range quiz.Questions {
+		header = append(header, fmt.Sprintf("Q%d %s", i+1, question.Title))
+	}
+	if err := table.writeRow(header); err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		row := []any{result.Nickname, result.Score, result.Rank}
+		for _, outcome := range result.Outcomes {
+			switch outcome {
+			case OutcomeCorrect:
+				row = append(row, 1)
+			case OutcomeWrong:
+				row = append(row, 0)
+			default:
+				row = append(row, nil)
+			}
+		}
+
+		if err := table.writeRow(row); err != nil {
+			return err
+		}
+	}
+
+	return table.close()
+}
+
+type csvTableWriter struct {
+	w *csv.Writer
+}
+
+// csvFormulaPrefixes start cells spreadsheet applications evaluate as formulas.
+const csvFormulaPrefixes = "=+-@\t\r"
+
+func (t *csvTableWriter) writeRow(cells []any) error {
+	record := make([]string, len(cells))
+	for i, c := range cells {
+		switch v := c.(type) {
+		case nil:
+		case string:
+			// Nicknames and titles are user input, they are quoted to be read as text.
+			if len(v) > 0 && strings.ContainsRune(csvFormulaPrefixes, rune(v[0])) {
+				v = "'" + v
+			}
+			record[i] = v
+		default:
+			record[i] = fmt.Sprint(c)
+		}
+	}
+
+	return t.w.Write(record)
+}
+
+func (t *csvTableWriter) close() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+// xlsxTableWriter writes a single sheet workbook, the smallest package spreadsheet
+// applications open. Strings are written inline, so no shared strings table has to be built.
+type xlsxTableWriter struct {
+	zw    *zip.Writer
+	sheet io.Writer
+	rows  int
+}
+
+var xlsxParts = []struct{ name, content string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Results" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+const (
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// columnName returns the name of the given zero-based column (A, B, ..., Z, AA, ...).
+func columnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
+
+// start writes every part but the sheet, which must be the last entry as it is streamed.
+func (t *xlsxTableWriter) start() error {
+	for _, part := range xlsxParts {
+		w, err := t.zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err = io.WriteString(w, part.content); err != nil {
+			return err
+		}
+	}
+
+	sheet, err := t.zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+
+	t.sheet = sheet
+	_, err = io.WriteString(sheet, xlsxSheetStart)
+	return err
+}
+
+func (t *xlsxTableWriter) writeRow(cells []any) error {
+	if t.sheet == nil {
+		if err := t.start(); err != nil {
+			return err
+		}
+	}
+
+	t.rows++
+	if _, err := fmt.Fprintf(t.sheet, `<row r="%d">`, t.rows); err != nil {
+		return err
+	}
+
+	for i, c := range cells {
+		ref := columnName(i) + strconv.Itoa(t.rows)
+
+		var err error
+		switch v := c.(type) {
+		case nil:
+			continue
+		case int:
+			_, err = fmt.Fprintf(t.sheet, `<c r="%s"><v>%d</v></c>`, ref, v)
+		default:
+			if _, err = fmt.Fprintf(t.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref); err == nil {
+				if err = xml.EscapeText(t.sheet, []byte(fmt.Sprint(v))); err == nil {
+					_, err = io.WriteString(t.sheet, `</t></is></c>`)
+				}
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(t.sheet, `</row>`)
+	return err
+}
+
+func (t *xlsxTableWriter) close() error {
+	if t.sheet == nil {
+		if err := t.start(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := io.WriteString(t.sheet, xlsxSheetEnd); err != nil {
+		return err
+	}
+
+	return t.zw.Close()
+}
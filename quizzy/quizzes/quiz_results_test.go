@@ -0,0 +1,138 @@
+package quizzes
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+func _results() (Quiz, []ParticipantResult) {
+	quiz := _twoQuestionsQuiz()
+	first, second := quiz.Questions[0], quiz.Questions[1]
+	participants := []Participant{
+		{Id: "alice", Nickname: "Alice"},
+		{Id: "bob", Nickname: "Bob"},
+		{Id: "carol", Nickname: "Carol <3"},
+	}
+	answers := []PlayerAnswer{
+		{PlayerId: "alice", QuestionId: first.Id, AnswerId: first.Answers[0].Id, IsCorrect: true},
+		{PlayerId: "alice", QuestionId: second.Id, AnswerId: second.Answers[1].Id},
+		{PlayerId: "bob", QuestionId: first.Id, AnswerId: first.Answers[0].Id, IsCorrect: true},
+		{PlayerId: "bob", QuestionId: second.Id},
+		{PlayerId: "carol", QuestionId: second.Id, AnswerId: second.Answers[0].Id, IsCorrect: true},
+		{PlayerId: "carol", QuestionId: first.Id, AnswerId: first.Answers[0].Id, IsCorrect: true},
+		// Recorded participants may be missing.
+		{PlayerId: "unknown-player", QuestionId: first.Id, AnswerId: first.Answers[1].Id},
+	}
+
+	return quiz, ComputeResults(quiz, participants, answers)
+}
+
+func TestComputeResults(t *testing.T) {
+	_, results := _results()
+	assert.Len(t, results, 4)
+
+	assert.Equal(t, "Carol <3", results[0].Nickname)
+	assert.Equal(t, 2, results[0].Score)
+	assert.Equal(t, 1, results[0].Rank)
+
+	// Same scores share the same rank.
+	assert.Equal(t, "Alice", results[1].Nickname)
+	assert.Equal(t, 2, results[1].Rank)
+	assert.Equal(t, []Outcome{OutcomeCorrect, OutcomeWrong}, results[1].Outcomes)
+	assert.Equal(t, "Bob", results[2].Nickname)
+	assert.Equal(t, 2, results[2].Rank)
+	assert.Equal(t, []Outcome{OutcomeCorrect, OutcomeSkipped}, results[2].Outcomes)
+
+	assert.Equal(t, "player-unknow", results[3].Nickname)
+	assert.Equal(t, 0, results[3].Score)
+	assert.Equal(t, 4, results[3].Rank)
+}
+
+func TestWriteResultsCSV(t *testing.T) {
+	quiz, results := _results()
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteResults(&buf, FormatCSV, quiz, results))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	assert.Nil(t, err)
+	assert.Len(t, records, 5)
+	assert.Equal(t, []string{"Nickname", "Score", "Rank", "Q1 question", "Q2 second"}, records[0])
+	assert.Equal(t, []string{"Bob", "1", "2", "1", ""}, records[3])
+}
+
+func TestWriteResultsCSVEscapesFormulas(t *testing.T) {
+	quiz := _twoQuestionsQuiz()
+	results := make([]ParticipantResult, 0)
+	for _, nickname := range []string{"=HYPERLINK(\"http://evil\")", "+1", "-1", "@SUM(A1)", "\tTab", "\rReturn", "Safe=1"} {
+		results = append(results, ParticipantResult{Participant: Participant{Nickname: nickname}, Outcomes: []Outcome{OutcomeSkipped, OutcomeSkipped}})
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteResults(&buf, FormatCSV, quiz, results))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	assert.Nil(t, err)
+	nicknames := make([]string, 0)
+	for _, record := range records[1:] {
+		nicknames = append(nicknames, record[0])
+	}
+	assert.Equal(t, []string{"'=HYPERLINK(\"http://evil\")", "'+1", "'-1", "'@SUM(A1)", "'\tTab", "'\rReturn", "Safe=1"}, nicknames)
+}
+
+func TestWriteResultsXLSX(t *testing.T) {
+	quiz, results := _results()
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteResults(&buf, FormatXLSX, quiz, results))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Nil(t, err)
+
+	var sheet []byte
+	names := make([]string, 0)
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			r, _ := f.Open()
+			sheet, _ = io.ReadAll(r)
+		}
+	}
+	assert.Contains(t, names, "[Content_Types].xml")
+	assert.Contains(t, names, "xl/workbook.xml")
+
+	var parsed struct {
+		Rows []struct {
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Value  string `xml:"v"`
+				Inline string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	assert.Nil(t, xml.Unmarshal(sheet, &parsed))
+	assert.Len(t, parsed.Rows, 5)
+	assert.Equal(t, "Carol <3", parsed.Rows[1].Cells[0].Inline)
+	assert.Equal(t, "C2", parsed.Rows[1].Cells[2].Ref)
+	assert.Equal(t, "1", parsed.Rows[1].Cells[2].Value)
+
+	// Skipped questions are left empty.
+	assert.Len(t, parsed.Rows[3].Cells, 4)
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "BA", columnName(52))
+}
+
+func TestWriteResultsUnknownFormat(t *testing.T) {
+	quiz, results := _results()
+	assert.ErrorIs(t, WriteResults(io.Discard, "pdf", quiz, results), ErrInvalidFormat)
+}
@@ -0,0 +1,301 @@
+package quizzes
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Weights of a word depending on where it was found, titles matter the most.
+const (
+	weightTitle       = 3.0
+	weightTag         = 2.0
+	weightDescription = 1.0
+	weightQuestion    = 1.0
+	// Words only starting with a query word weight less than exact matches.
+	prefixPenalty = 0.5
+)
+
+type memoryDocument struct {
+	entry LibraryEntry
+	words map[string]float64
+}
+
+// memorySearchIndex is an in-process inverted index, mapping every word to the quizzes holding it.
+// It isn't shared between instances, so it is rebuilt on startup then periodically (see IndexRebuilder)
+// to catch up with quizzes written by other instances.
+type memorySearchIndex struct {
+	mu       sync.RWMutex
+	docs     map[string]*memoryDocument
+	postings map[string]map[string]float64
+	// owners of indexed quizzes, by quiz id.
+	owners map[string]string
+}
+
+func NewMemorySearchIndex() SearchIndex {
+	return newMemorySearchIndex()
+}
+
+func newMemorySearchIndex() *memorySearchIndex {
+	return &memorySearchIndex{
+		docs:     make(map[string]*memoryDocument),
+		postings: make(map[string]map[string]float64),
+		owners:   make(map[string]string),
+	}
+}
+
+func documentKey(ownerId, quizId string) string {
+	return ownerId + "/" + quizId
+}
+
+func (idx *memorySearchIndex) Index(ctx context.Context, ownerId string, quiz Quiz) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	key := documentKey(ownerId, quiz.Id)
+	idx.remove(key)
+
+	if !quiz.IsListed() {
+		return nil
+	}
+
+	words := make(map[string]float64)
+	add := func(text string, weight float64) {
+		for _, word := range Tokenize(text) {
+			words[word] += weight
+		}
+	}
+
+	add(quiz.Title, weightTitle)
+	add(quiz.Description, weightDescription)
+	for _, tag := range quiz.Tags {
+		add(tag, weightTag)
+	}
+	for _, question := range quiz.Questions {
+		add(question.Title, weightQuestion)
+	}
+
+	idx.docs[key] = &memoryDocument{entry: NewLibraryEntry(ownerId, quiz), words: words}
+	idx.owners[quiz.Id] = ownerId
+	for word, weight := range words {
+		if idx.postings[word] == nil {
+			idx.postings[word] = make(map[string]float64)
+		}
+		idx.postings[word][key] = weight
+	}
+
+	return nil
+}
+
+// remove must be called with the write lock held.
+func (idx *memorySearchIndex) remove(key string) {
+	doc, ok := idx.docs[key]
+	if !ok {
+		return
+	}
+
+	for word := range doc.words {
+		delete(idx.postings[word], key)
+		if len(idx.postings[word]) == 0 {
+			delete(idx.postings, word)
+		}
+	}
+	delete(idx.docs, key)
+	delete(idx.owners, doc.entry.Id)
+}
+
+func (idx *memorySearchIndex) Remove(ctx context.Context, ownerId, quizId string) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(documentKey(ownerId, quizId))
+	return nil
+}
+
+func (idx *memorySearchIndex) RemoveOwner(ctx context.Context, ownerId string) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for key, doc := range idx.docs {
+		if doc.entry.OwnerId == ownerId {
+			idx.remove(key)
+		}
+	}
+
+	return nil
+}
+
+// Replace builds a new index aside, so searches are served from the previous one meanwhile.
+func (idx *memorySearchIndex) Replace(ctx context.Context, quizzes []OwnedQuiz) error {
+	fresh := newMemorySearchIndex()
+	for _, owned := range quizzes {
+		if err := fresh.Index(ctx, owned.OwnerId, owned.Quiz); err != nil {
+			return err
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.docs, idx.postings, idx.owners = fresh.docs, fresh.postings, fresh.owners
+	return nil
+}
+
+func (idx *memorySearchIndex) Owner(ctx context.Context, quizId string) (string, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if ownerId, ok := idx.owners[quizId]; ok {
+		return ownerId, nil
+	}
+	return "", ErrNotFound
+}
+
+// match returns scores of documents holding a word starting with the given one.
+// Must be called with the read lock held.
+func (idx *memorySearchIndex) match(term string) map[string]float64 {
+	scores := make(map[string]float64)
+	for word, docs := range idx.postings {
+		if !strings.HasPrefix(word, term) {
+			continue
+		}
+
+		factor := 1.0
+		if word != term {
+			factor = prefixPenalty
+		}
+		for key, weight := range docs {
+			scores[key] += weight * factor
+		}
+	}
+
+	return scores
+}
+
+func (idx *memorySearchIndex) Search(ctx context.Context, query SearchQuery) (SearchResult, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	// Every document matches an empty text, with the same score.
+	var scores map[string]float64
+	if terms := Tokenize(query.Text); len(terms) == 0 {
+		scores = make(map[string]float64, len(idx.docs))
+		for key := range idx.docs {
+			scores[key] = 0
+		}
+	} else {
+		for i, term := range terms {
+			matches := idx.match(term)
+			if i == 0 {
+				scores = matches
+				continue
+			}
+
+			for key := range scores {
+				if s, ok := matches[key]; ok {
+					scores[key] += s
+				} else {
+					delete(scores, key)
+				}
+			}
+		}
+	}
+
+	hits := make([]string, 0, len(scores))
+	tags, categories, languages := make(map[string]int), make(map[string]int), make(map[string]int)
+	for key := range scores {
+		entry := idx.docs[key].entry
+		if !matchesFilters(entry, query) {
+			continue
+		}
+
+		hits = append(hits, key)
+		for _, tag := range entry.Tags {
+			tags[tag]++
+		}
+		if entry.Category != "" {
+			categories[entry.Category]++
+		}
+		if entry.Language != "" {
+			languages[entry.Language]++
+		}
+	}
+
+	// Following the requested order, then by best scores and alphabetically to keep pages stable.
+	sort.Slice(hits, func(i, j int) bool {
+		a, b := idx.docs[hits[i]].entry, idx.docs[hits[j]].entry
+		switch query.Sort {
+		case SortPopular:
+			if a.Stars != b.Stars {
+				return a.Stars > b.Stars
+			} else if a.RatingCount != b.RatingCount {
+				return a.RatingCount > b.RatingCount
+			}
+		case SortRating:
+			if a.RatingAverage != b.RatingAverage {
+				return a.RatingAverage > b.RatingAverage
+			} else if a.RatingCount != b.RatingCount {
+				return a.RatingCount > b.RatingCount
+			}
+		}
+
+		if scores[hits[i]] != scores[hits[j]] {
+			return scores[hits[i]] > scores[hits[j]]
+		} else if a.Title != b.Title {
+			return a.Title < b.Title
+		}
+		return hits[i] < hits[j]
+	})
+
+	data := make([]LibraryEntry, 0)
+	for i := (query.Page - 1) * query.PageSize; i < len(hits) && len(data) < query.PageSize; i++ {
+		data = append(data, idx.docs[hits[i]].entry)
+	}
+
+	return SearchResult{
+		Data:     data,
+		Total:    len(hits),
+		Page:     query.Page,
+		PageSize: query.PageSize,
+		Facets: SearchFacets{
+			Tags:       facetsOf(tags),
+			Categories: facetsOf(categories),
+			Languages:  facetsOf(languages),
+		},
+	}, nil
+}
+
+func matchesFilters(entry LibraryEntry, query SearchQuery) bool {
+	if query.Category != "" && entry.Category != query.Category {
+		return false
+	}
+	if query.Language != "" && !strings.EqualFold(entry.Language, query.Language) {
+		return false
+	}
+	for _, tag := range query.Tags {
+		if !slices.Contains(entry.Tags, tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// facetsOf sorts the given counts, most frequent values first.
+func facetsOf(counts map[string]int) []Facet {
+	facets := make([]Facet, 0, len(counts))
+	for value, count := range counts {
+		facets = append(facets, Facet{Value: value, Count: count})
+	}
+
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Value < facets[j].Value
+	})
+
+	return facets
+}
@@ -1,28 +1,171 @@
 package quizzes
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
-var ErrQuizNotReady = errors.New("quiz not ready")
+var (
+	ErrQuizNotReady    = errors.New("quiz not ready")
+	ErrNoCodeAvailable = errors.New("no execution code available")
+)
 
 type QuizService interface {
-	Create(ownerId string, quiz Quiz) error
+	Create(ctx context.Context, ownerId string, quiz Quiz) error
 
-	Get(ownerId, id string) (Quiz, error)
+	Get(ctx context.Context, ownerId, id string) (Quiz, error)
 
-	GetAll(ownerId string) ([]Quiz, error)
+	GetAll(ctx context.Context, ownerId string) ([]Quiz, error)
 
-	Patch(ownerId, quizId string, fields []FieldPatchOp) error
+	// GetAllInFolder returns quizzes of the given user held by the given folder, RootFolder included.
+	// ErrInvalidFolder is returned if the folder doesn't exist.
+	GetAllInFolder(ctx context.Context, ownerId, folderId string) ([]Quiz, error)
 
-	CreateQuestion(ownerId string, quiz Quiz, question Question) error
+	Patch(ctx context.Context, ownerId, quizId string, fields []FieldPatchOp) error
 
-	UpdateQuestion(ownerId, quizId string, question Question) error
+	CreateQuestion(ctx context.Context, ownerId string, quiz Quiz, question Question) error
 
-	// StartQuiz starts the given Quiz. If the quiz doesn't meet
-	// validation requirements, ErrQuizNotReady is returned.
-	StartQuiz(ownerId string, quiz Quiz) error
+	UpdateQuestion(ctx context.Context, ownerId, quizId string, question Question) error
 
-	QuizFromCode(code string) (Quiz, error)
-	IncrRoomPeople(roomId string) error
-	GetRoomPeople(roomId string) (int, error)
-	ResetRoomPeople(roomId string) error
+	// StartQuiz creates a new Execution of the given Quiz, with its own reserved code.
+	// If the quiz doesn't meet validation requirements, ErrQuizNotReady is returned.
+	StartQuiz(ctx context.Context, ownerId string, quiz Quiz) (Execution, error)
+
+	// GetExecution returns the matching execution of the given quiz.
+	GetExecution(ctx context.Context, ownerId, quizId, executionId string) (Execution, error)
+
+	// GetExecutions returns all executions of the given quiz.
+	GetExecutions(ctx context.Context, ownerId, quizId string) ([]Execution, error)
+
+	// UpdateExecution stores state changes (status, cursor) of the given execution.
+	UpdateExecution(ctx context.Context, execution Execution) error
+
+	// EndExecution marks the given execution as finished, releases its code and resets its room.
+	// Results are then recorded in the history of registered participants, and added to quiz stats.
+	EndExecution(ctx context.Context, execution Execution) error
+
+	// ExecutionFromCode returns the running execution bound to the given code, along with its quiz.
+	ExecutionFromCode(ctx context.Context, code string) (Execution, Quiz, error)
+
+	// JoinExecution records the given participant of the given execution, rejoining keeps
+	// the participant as first recorded.
+	JoinExecution(ctx context.Context, execution Execution, participant Participant) error
+
+	// AnswerQuestion records the answer of the given participant to the current question of the
+	// given execution, an empty answerId skips the question. ErrInvalidAnswer is returned if the
+	// question isn't the current one or the answer isn't one of its answers, ErrAlreadyAnswered if
+	// the participant already answered.
+	AnswerQuestion(ctx context.Context, execution Execution, quiz Quiz, playerId, questionId, answerId string) (PlayerAnswer, error)
+
+	// GetExecutionAnalytics aggregates answers to the given execution of the given quiz, per question.
+	GetExecutionAnalytics(ctx context.Context, quiz Quiz, execution Execution) (ExecutionAnalytics, error)
+
+	// GetExecutionResults ranks participants of the given execution of the given quiz, best scores first.
+	GetExecutionResults(ctx context.Context, quiz Quiz, execution Execution) ([]ParticipantResult, error)
+
+	// GetStats returns stats of the given quiz aggregated over all its ended executions.
+	GetStats(ctx context.Context, ownerId string, quiz Quiz) (QuizStatsReport, error)
+
+	// GetHistory returns the given page of executions the given player took part in, most recent first.
+	// Only players who joined with their account have a history.
+	GetHistory(ctx context.Context, uid string, page, pageSize int) (HistoryPage, error)
+
+	// DeleteAll ends running executions of the given user, revokes its accesses to quizzes
+	// shared with it, withdraws its reactions, then removes all their quizzes and its participations
+	// in executions of other users. Removing a large account may fail midway, calling it again resumes it.
+	DeleteAll(ctx context.Context, ownerId string) error
+
+	// GetAccessible returns the given quiz if the user owns it, or if it was shared with it.
+	GetAccessible(ctx context.Context, uid, quizId string) (QuizAccess, error)
+
+	// GetShared returns quizzes shared with the given user.
+	GetShared(ctx context.Context, uid string) ([]QuizAccess, error)
+
+	// AddCollaborator grants the given role on the given quiz to the given user, replacing
+	// any previous role. ErrInvalidCollaborator is returned if the role can't be granted
+	// or if the user is the owner.
+	AddCollaborator(ctx context.Context, ownerId, quizId string, user UserRef, role QuizRole) (Collaborator, error)
+
+	// GetCollaborators returns collaborators of the given quiz.
+	GetCollaborators(ctx context.Context, ownerId, quizId string) ([]Collaborator, error)
+
+	// RemoveCollaborator revokes access of the given user to the given quiz.
+	RemoveCollaborator(ctx context.Context, ownerId, quizId, uid string) error
+
+	// Search returns the page of public quizzes matching the given query.
+	Search(ctx context.Context, query SearchQuery) (SearchResult, error)
+
+	// Star adds the given quiz to favorites of the given user. Only quizzes shared with anyone
+	// (public or unlisted) can be starred, ErrNotFound is returned otherwise.
+	Star(ctx context.Context, uid, ownerId, quizId string) error
+
+	// Unstar removes the given quiz from favorites of the given user.
+	Unstar(ctx context.Context, uid, ownerId, quizId string) error
+
+	// Rate sets the rating of the given user on the given quiz, replacing any previous one.
+	// ErrInvalidRating is returned if the rating is out of bounds, or if the user owns the quiz.
+	Rate(ctx context.Context, uid, ownerId, quizId string, rating int) error
+
+	// Unrate removes the rating of the given user on the given quiz.
+	Unrate(ctx context.Context, uid, ownerId, quizId string) error
+
+	// GetFavorites returns quizzes starred by the given user, most recent reactions first.
+	// Quizzes which were removed or aren't shared anymore are skipped.
+	GetFavorites(ctx context.Context, uid string) ([]LibraryEntry, error)
+
+	// GetReactions returns every reaction of the given user.
+	GetReactions(ctx context.Context, uid string) ([]Reaction, error)
+
+	// Fork copies the given quiz into the quizzes of the given user, as a private quiz
+	// recording its source. Only quizzes shared with anyone (public or unlisted) can be forked,
+	// ErrNotFound is returned otherwise. ErrInvalidFork is returned if the user owns the quiz.
+	Fork(ctx context.Context, uid, ownerId, quizId string) (Quiz, error)
+
+	// GetLibraryOwner returns the owner of the given quiz of the library, ErrNotFound is returned
+	// if the quiz isn't listed. Quizzes are looked up in the search index, which may lag behind the store.
+	GetLibraryOwner(ctx context.Context, quizId string) (string, error)
+
+	// CreateFolder creates a folder in the given parent folder. ErrInvalidFolder is returned if
+	// the name is invalid, the parent doesn't exist or MaxFolderDepth would be exceeded.
+	CreateFolder(ctx context.Context, ownerId, name, parentId string) (Folder, error)
+
+	// GetFolders returns every folder of the given user, as a flat list.
+	GetFolders(ctx context.Context, ownerId string) ([]Folder, error)
+
+	// PatchFolder renames or moves the given folder. ErrInvalidFolder is returned if a folder
+	// would be moved into itself or one of its descendants.
+	PatchFolder(ctx context.Context, ownerId, folderId string, req PatchFolderRequest) (Folder, error)
+
+	// DeleteFolder removes the given folder, ErrFolderNotEmpty is returned if it still holds
+	// quizzes or folders.
+	DeleteFolder(ctx context.Context, ownerId, folderId string) error
+
+	// MoveQuiz moves the given quiz to the given folder, ErrInvalidFolder is returned if it doesn't exist.
+	MoveQuiz(ctx context.Context, ownerId, quizId, folderId string) error
+
+	// CreateCollection creates an empty collection, ErrInvalidCollection is returned if the name is invalid.
+	CreateCollection(ctx context.Context, ownerId, name, description string) (Collection, error)
+
+	// GetCollections returns every collection of the given user.
+	GetCollections(ctx context.Context, ownerId string) ([]Collection, error)
+
+	// GetCollection returns the given collection along with its quizzes.
+	GetCollection(ctx context.Context, ownerId, collectionId string) (CollectionWithQuizzes, error)
+
+	// PatchCollection renames or describes the given collection.
+	PatchCollection(ctx context.Context, ownerId, collectionId string, req PatchCollectionRequest) (Collection, error)
+
+	// DeleteCollection removes the given collection, its quizzes are left untouched.
+	DeleteCollection(ctx context.Context, ownerId, collectionId string) error
+
+	// AddToCollection appends the given quiz to the given collection, if not already part of it.
+	// ErrNotFound is returned if the user doesn't own the quiz.
+	AddToCollection(ctx context.Context, ownerId, collectionId, quizId string) (Collection, error)
+
+	// RemoveFromCollection removes the given quiz from the given collection.
+	RemoveFromCollection(ctx context.Context, ownerId, collectionId, quizId string) (Collection, error)
+
+	IncrRoomPeople(ctx context.Context, executionId string) error
+	GetRoomPeople(ctx context.Context, executionId string) (int, error)
+	ResetRoomPeople(ctx context.Context, executionId string) error
 }
@@ -0,0 +1,248 @@
+package quizzes
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"slices"
+	"time"
+)
+
+// folderMap returns folders of the given user by id.
+func (qs *QuizServiceImpl) folderMap(ctx context.Context, ownerId string) (map[string]Folder, error) {
+	folders, err := qs.store.GetFolders(ctx, ownerId)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]Folder, len(folders))
+	for _, f := range folders {
+		m[f.Id] = f
+	}
+
+	return m, nil
+}
+
+// folderExists reports whether the given folder exists, RootFolder always does.
+func (qs *QuizServiceImpl) folderExists(ctx context.Context, ownerId, folderId string) (bool, error) {
+	if folderId == RootFolder {
+		return true, nil
+	}
+
+	folders, err := qs.folderMap(ctx, ownerId)
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := folders[folderId]
+	return ok, nil
+}
+
+func (qs *QuizServiceImpl) GetAllInFolder(ctx context.Context, ownerId, folderId string) ([]Quiz, error) {
+	if ok, err := qs.folderExists(ctx, ownerId, folderId); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrInvalidFolder
+	}
+
+	return qs.store.GetQuizzesInFolder(ctx, ownerId, folderId)
+}
+
+func (qs *QuizServiceImpl) CreateFolder(ctx context.Context, ownerId, name, parentId string) (Folder, error) {
+	name, ok := normalizeName(name)
+	if !ok {
+		return Folder{}, ErrInvalidFolder
+	}
+
+	folders, err := qs.folderMap(ctx, ownerId)
+	if err != nil {
+		return Folder{}, err
+	}
+
+	if depth, ok2 := folderDepth(folders, parentId); !ok2 || depth >= MaxFolderDepth {
+		return Folder{}, ErrInvalidFolder
+	}
+
+	folder := Folder{Id: uuid.New().String(), Name: name, ParentId: parentId, CreatedAt: time.Now().UTC()}
+	return folder, qs.store.UpsertFolder(ctx, ownerId, folder)
+}
+
+func (qs *QuizServiceImpl) GetFolders(ctx context.Context, ownerId string) ([]Folder, error) {
+	return qs.store.GetFolders(ctx, ownerId)
+}
+
+func (qs *QuizServiceImpl) PatchFolder(ctx context.Context, ownerId, folderId string, req PatchFolderRequest) (Folder, error) {
+	folders, err := qs.folderMap(ctx, ownerId)
+	if err != nil {
+		return Folder{}, err
+	}
+
+	folder, found := folders[folderId]
+	if !found {
+		return Folder{}, ErrNotFound
+	}
+
+	if req.Name != nil {
+		name, ok := normalizeName(*req.Name)
+		if !ok {
+			return Folder{}, ErrInvalidFolder
+		}
+		folder.Name = name
+	}
+
+	if req.ParentId != nil && *req.ParentId != folder.ParentId {
+		if isDescendant(folders, *req.ParentId, folderId) {
+			return Folder{}, ErrInvalidFolder
+		}
+
+		// The moved folder brings its whole subtree along.
+		depth, ok := folderDepth(folders, *req.ParentId)
+		if !ok || depth+subtreeHeight(folders, folderId) > MaxFolderDepth {
+			return Folder{}, ErrInvalidFolder
+		}
+		folder.ParentId = *req.ParentId
+	}
+
+	return folder, qs.store.UpsertFolder(ctx, ownerId, folder)
+}
+
+// subtreeHeight returns the number of levels of the given folder subtree, itself included.
+func subtreeHeight(folders map[string]Folder, id string) int {
+	height := 0
+	for _, f := range folders {
+		if f.ParentId == id && f.Id != id {
+			height = max(height, subtreeHeight(folders, f.Id))
+		}
+	}
+
+	return height + 1
+}
+
+func (qs *QuizServiceImpl) DeleteFolder(ctx context.Context, ownerId, folderId string) error {
+	folders, err := qs.folderMap(ctx, ownerId)
+	if err != nil {
+		return err
+	} else if _, ok := folders[folderId]; !ok {
+		return ErrNotFound
+	}
+
+	for _, f := range folders {
+		if f.ParentId == folderId {
+			return ErrFolderNotEmpty
+		}
+	}
+
+	if held, err2 := qs.store.GetQuizzesInFolder(ctx, ownerId, folderId); err2 != nil {
+		return err2
+	} else if len(held) > 0 {
+		return ErrFolderNotEmpty
+	}
+
+	return qs.store.DeleteFolder(ctx, ownerId, folderId)
+}
+
+func (qs *QuizServiceImpl) MoveQuiz(ctx context.Context, ownerId, quizId, folderId string) error {
+	if ok, err := qs.folderExists(ctx, ownerId, folderId); err != nil {
+		return err
+	} else if !ok {
+		return ErrInvalidFolder
+	}
+
+	// Not a patchable field, folders only make sense to the owner.
+	return qs.store.Patch(ctx, ownerId, quizId, []FieldPatchOp{{Op: "replace", Path: "/folderId", Value: folderId}})
+}
+
+func (qs *QuizServiceImpl) CreateCollection(ctx context.Context, ownerId, name, description string) (Collection, error) {
+	name, ok := normalizeName(name)
+	if !ok {
+		return Collection{}, ErrInvalidCollection
+	}
+
+	collection := Collection{
+		Id:          uuid.New().String(),
+		Name:        name,
+		Description: description,
+		QuizIds:     make([]string, 0),
+		CreatedAt:   time.Now().UTC(),
+	}
+	return collection, qs.store.UpsertCollection(ctx, ownerId, collection)
+}
+
+func (qs *QuizServiceImpl) GetCollections(ctx context.Context, ownerId string) ([]Collection, error) {
+	return qs.store.GetCollections(ctx, ownerId)
+}
+
+func (qs *QuizServiceImpl) GetCollection(ctx context.Context, ownerId, collectionId string) (CollectionWithQuizzes, error) {
+	collection, err := qs.store.GetCollection(ctx, ownerId, collectionId)
+	if err != nil {
+		return CollectionWithQuizzes{}, err
+	}
+
+	quizzes := make([]Quiz, 0, len(collection.QuizIds))
+	for _, id := range collection.QuizIds {
+		quiz, err2 := qs.store.GetUnique(ctx, ownerId, id)
+		if errors.Is(err2, ErrNotFound) {
+			continue
+		} else if err2 != nil {
+			return CollectionWithQuizzes{}, err2
+		}
+
+		quizzes = append(quizzes, quiz)
+	}
+
+	return CollectionWithQuizzes{Collection: collection, Quizzes: quizzes}, nil
+}
+
+func (qs *QuizServiceImpl) PatchCollection(ctx context.Context, ownerId, collectionId string, req PatchCollectionRequest) (Collection, error) {
+	collection, err := qs.store.GetCollection(ctx, ownerId, collectionId)
+	if err != nil {
+		return Collection{}, err
+	}
+
+	if req.Name != nil {
+		name, ok := normalizeName(*req.Name)
+		if !ok {
+			return Collection{}, ErrInvalidCollection
+		}
+		collection.Name = name
+	}
+	if req.Description != nil {
+		collection.Description = *req.Description
+	}
+
+	return collection, qs.store.UpsertCollection(ctx, ownerId, collection)
+}
+
+func (qs *QuizServiceImpl) DeleteCollection(ctx context.Context, ownerId, collectionId string) error {
+	return qs.store.DeleteCollection(ctx, ownerId, collectionId)
+}
+
+func (qs *QuizServiceImpl) AddToCollection(ctx context.Context, ownerId, collectionId, quizId string) (Collection, error) {
+	collection, err := qs.store.GetCollection(ctx, ownerId, collectionId)
+	if err != nil {
+		return Collection{}, err
+	}
+
+	if slices.Contains(collection.QuizIds, quizId) {
+		return collection, nil
+	}
+
+	if _, err2 := qs.store.GetUnique(ctx, ownerId, quizId); err2 != nil {
+		return Collection{}, err2
+	}
+
+	collection.QuizIds = append(collection.QuizIds, quizId)
+	return collection, qs.store.UpsertCollection(ctx, ownerId, collection)
+}
+
+func (qs *QuizServiceImpl) RemoveFromCollection(ctx context.Context, ownerId, collectionId, quizId string) (Collection, error) {
+	collection, err := qs.store.GetCollection(ctx, ownerId, collectionId)
+	if err != nil {
+		return Collection{}, err
+	}
+
+	collection.QuizIds = slices.DeleteFunc(collection.QuizIds, func(id string) bool {
+		return id == quizId
+	})
+	return collection, qs.store.UpsertCollection(ctx, ownerId, collection)
+}
@@ -1,64 +1,599 @@
 package quizzes
 
-import "strings"
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"sort"
+	"strings"
+	"time"
+)
 
 type QuizServiceImpl struct {
 	store    Store
 	resolver QuizCodeResolver
+	// index searches the library, the store must keep it up to date (see NewIndexedStore).
+	index SearchIndex
 }
 
-func (qs *QuizServiceImpl) Create(ownerId string, quiz Quiz) error {
-	return qs.store.Upsert(ownerId, quiz)
+func (qs *QuizServiceImpl) Create(ctx context.Context, ownerId string, quiz Quiz) error {
+	return qs.store.Upsert(ctx, ownerId, quiz)
 }
 
-func (qs *QuizServiceImpl) Get(ownerId, id string) (Quiz, error) {
-	return qs.store.GetUnique(ownerId, id)
+func (qs *QuizServiceImpl) Get(ctx context.Context, ownerId, id string) (Quiz, error) {
+	return qs.store.GetUnique(ctx, ownerId, id)
 }
 
-func (qs *QuizServiceImpl) GetAll(ownerId string) ([]Quiz, error) {
-	return qs.store.GetQuizzes(ownerId)
+func (qs *QuizServiceImpl) GetAll(ctx context.Context, ownerId string) ([]Quiz, error) {
+	return qs.store.GetQuizzes(ctx, ownerId)
 }
 
-func (qs *QuizServiceImpl) Patch(ownerId, quizId string, fields []FieldPatchOp) error {
-	return qs.store.Patch(ownerId, quizId, fields)
+// patchableFields lists quiz fields which can be patched, with their value validation.
+var patchableFields = map[string]func(value any) bool{
+	"/title": func(value any) bool {
+		s, ok := value.(string)
+		return ok && len(s) > 0
+	},
+	"/description": func(value any) bool {
+		_, ok := value.(string)
+		return ok
+	},
+	"/visibility": func(value any) bool {
+		s, ok := value.(string)
+		return ok && Visibility(s).IsValid()
+	},
+	"/tags": func(value any) bool {
+		tags, ok := tagsOf(value)
+		return ok && validTags(NormalizeTags(tags))
+	},
+	"/category": func(value any) bool {
+		s, ok := value.(string)
+		return ok && validCategory(strings.TrimSpace(s))
+	},
+	"/language": func(value any) bool {
+		s, ok := value.(string)
+		return ok && validLanguage(strings.TrimSpace(s))
+	},
 }
 
-func (qs *QuizServiceImpl) CreateQuestion(ownerId string, quiz Quiz, question Question) error {
-	return qs.store.UpsertQuestion(ownerId, quiz.Id, question)
+// patchNormalizers rewrite valid values of patched fields as they are stored.
+var patchNormalizers = map[string]func(value any) any{
+	"/tags": func(value any) any {
+		tags, _ := tagsOf(value)
+		return NormalizeTags(tags)
+	},
+	"/category": func(value any) any {
+		return strings.ToLower(strings.TrimSpace(value.(string)))
+	},
+	"/language": func(value any) any {
+		return strings.TrimSpace(value.(string))
+	},
 }
 
-func (qs *QuizServiceImpl) UpdateQuestion(ownerId, quizId string, question Question) error {
-	return qs.store.UpdateQuestion(ownerId, quizId, question)
+// tagsOf converts a decoded JSON array of strings.
+func tagsOf(value any) ([]string, bool) {
+	if tags, ok := value.([]string); ok {
+		return tags, true
+	}
+
+	list, ok := value.([]any)
+	if !ok {
+		return nil, false
+	}
+
+	tags := make([]string, 0, len(list))
+	for _, v := range list {
+		s, ok2 := v.(string)
+		if !ok2 {
+			return nil, false
+		}
+		tags = append(tags, s)
+	}
+
+	return tags, true
+}
+
+func (qs *QuizServiceImpl) Patch(ctx context.Context, ownerId, quizId string, fields []FieldPatchOp) error {
+	for i, field := range fields {
+		if field.Op != "replace" {
+			return ErrInvalidPatchOperator
+		}
+
+		if valid, ok := patchableFields[field.Path]; !ok || !valid(field.Value) {
+			return ErrInvalidPatchField
+		}
+
+		if normalize, ok := patchNormalizers[field.Path]; ok {
+			fields[i].Value = normalize(field.Value)
+		}
+	}
+
+	return qs.store.Patch(ctx, ownerId, quizId, fields)
 }
 
-func (qs *QuizServiceImpl) StartQuiz(ownerId string, quiz Quiz) error {
+func (qs *QuizServiceImpl) CreateQuestion(ctx context.Context, ownerId string, quiz Quiz, question Question) error {
+	return qs.store.UpsertQuestion(ctx, ownerId, quiz.Id, question)
+}
+
+func (qs *QuizServiceImpl) UpdateQuestion(ctx context.Context, ownerId, quizId string, question Question) error {
+	return qs.store.UpdateQuestion(ctx, ownerId, quizId, question)
+}
+
+func (qs *QuizServiceImpl) StartQuiz(ctx context.Context, ownerId string, quiz Quiz) (Execution, error) {
 	if !quiz.Validate() {
-		return ErrQuizNotReady
+		return Execution{}, ErrQuizNotReady
+	}
+
+	execution := Execution{
+		Id:        uuid.New().String(),
+		QuizId:    quiz.Id,
+		OwnerId:   ownerId,
+		Status:    ExecutionWaiting,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	// Random codes may collide with a running execution, retrying with
+	// a new one until a free code is reserved.
+	for i := 0; i < MaxCodeAttempts; i++ {
+		code, err := GenerateCode()
+		if err != nil {
+			return Execution{}, err
+		}
+
+		if err2 := qs.resolver.BindCode(ctx, code, execution.Ref()); errors.Is(err2, ErrCodeTaken) {
+			continue
+		} else if err2 != nil {
+			return Execution{}, err2
+		}
+
+		execution.Code = code
+		if err3 := qs.store.UpsertExecution(ctx, ownerId, quiz.Id, execution); err3 != nil {
+			_ = qs.resolver.UnbindCode(ctx, code)
+			return Execution{}, err3
+		}
+
+		return execution, nil
+	}
+
+	return Execution{}, ErrNoCodeAvailable
+}
+
+func (qs *QuizServiceImpl) GetExecution(ctx context.Context, ownerId, quizId, executionId string) (Execution, error) {
+	return qs.store.GetExecution(ctx, ownerId, quizId, executionId)
+}
+
+func (qs *QuizServiceImpl) GetExecutions(ctx context.Context, ownerId, quizId string) ([]Execution, error) {
+	return qs.store.GetExecutions(ctx, ownerId, quizId)
+}
+
+func (qs *QuizServiceImpl) UpdateExecution(ctx context.Context, execution Execution) error {
+	return qs.store.UpsertExecution(ctx, execution.OwnerId, execution.QuizId, execution)
+}
+
+func (qs *QuizServiceImpl) EndExecution(ctx context.Context, execution Execution) error {
+	now := time.Now().UTC()
+	execution.Status = ExecutionFinished
+	execution.EndedAt = &now
+
+	if err := qs.store.UpsertExecution(ctx, execution.OwnerId, execution.QuizId, execution); err != nil {
+		return err
+	}
+
+	if err := qs.resolver.UnbindCode(ctx, execution.Code); err != nil {
+		return err
+	}
+
+	if err := qs.resolver.ResetRoomPeople(ctx, execution.Id); err != nil {
+		return err
+	}
+
+	return qs.recordResults(ctx, execution)
+}
+
+// recordResults adds the given ended execution to the history of its registered participants,
+// and to the stats of its quiz. Executions ended before their first question have no results
+// worth keeping.
+// Results are recorded once: the stored execution is flagged along with the stats increment,
+// history entries written before a failure are overwritten when ending the execution again.
+func (qs *QuizServiceImpl) recordResults(ctx context.Context, execution Execution) error {
+	if len(execution.AskedAt) == 0 {
+		return nil
+	}
+
+	stored, err := qs.store.GetExecution(ctx, execution.OwnerId, execution.QuizId, execution.Id)
+	if err != nil {
+		return err
+	} else if stored.ResultsRecorded {
+		return nil
+	}
+
+	quiz, err := qs.store.GetUnique(ctx, execution.OwnerId, execution.QuizId)
+	if err != nil {
+		return err
+	}
+
+	participants, answers, err := qs.executionRecords(ctx, execution)
+	if err != nil {
+		return err
+	}
+
+	results := ComputeResults(quiz, participants, answers)
+	for uid, entry := range NewHistoryEntries(quiz, execution, results) {
+		if err2 := qs.store.UpsertHistory(ctx, uid, entry); err2 != nil {
+			return err2
+		}
+	}
+
+	analytics := ComputeAnalytics(quiz, execution, participants, answers)
+	return qs.store.AddStats(ctx, execution.Ref(), StatsOf(quiz, execution, analytics, results))
+}
+
+func (qs *QuizServiceImpl) ExecutionFromCode(ctx context.Context, code string) (Execution, Quiz, error) {
+	ref, err := qs.resolver.Resolve(ctx, code)
+	if err != nil {
+		return Execution{}, Quiz{}, err
+	}
+
+	execution, err2 := qs.store.GetExecution(ctx, ref.OwnerId, ref.QuizId, ref.ExecutionId)
+	if err2 != nil {
+		return Execution{}, Quiz{}, err2
+	}
+
+	quiz, err3 := qs.store.GetUnique(ctx, ref.OwnerId, ref.QuizId)
+	if err3 != nil {
+		return Execution{}, Quiz{}, err3
+	}
+
+	return execution, quiz, nil
+}
+
+func (qs *QuizServiceImpl) JoinExecution(ctx context.Context, execution Execution, participant Participant) error {
+	return qs.store.AddParticipant(ctx, execution.Ref(), participant)
+}
+
+func (qs *QuizServiceImpl) AnswerQuestion(ctx context.Context, execution Execution, quiz Quiz, playerId, questionId, answerId string) (PlayerAnswer, error) {
+	// The cursor points to the question following the one being asked.
+	index := execution.Cursor - 1
+	if execution.Status != ExecutionStarted || index < 0 || index >= len(quiz.Questions) {
+		return PlayerAnswer{}, ErrInvalidAnswer
+	}
+
+	question := quiz.Questions[index]
+	if question.Id != questionId {
+		return PlayerAnswer{}, ErrInvalidAnswer
+	}
+
+	now := time.Now().UTC()
+	answer := PlayerAnswer{PlayerId: playerId, QuestionId: questionId, AnswerId: answerId, AnsweredAt: now}
+	if askedAt, ok := execution.AskedAt[questionId]; ok {
+		answer.ResponseTime = now.Sub(askedAt).Milliseconds()
+	}
+
+	if answerId != "" {
+		found := false
+		for _, a := range question.Answers {
+			if a.Id == answerId {
+				found, answer.IsCorrect = true, a.IsCorrect
+			}
+		}
+		if !found {
+			return PlayerAnswer{}, ErrInvalidAnswer
+		}
+	}
+
+	return answer, qs.store.AddAnswer(ctx, execution.Ref(), answer)
+}
+
+// executionRecords returns participants and answers stored for the given execution.
+func (qs *QuizServiceImpl) executionRecords(ctx context.Context, execution Execution) ([]Participant, []PlayerAnswer, error) {
+	participants, err := qs.store.GetParticipants(ctx, execution.Ref())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	answers, err := qs.store.GetAnswers(ctx, execution.Ref())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return participants, answers, nil
+}
+
+func (qs *QuizServiceImpl) GetExecutionAnalytics(ctx context.Context, quiz Quiz, execution Execution) (ExecutionAnalytics, error) {
+	participants, answers, err := qs.executionRecords(ctx, execution)
+	if err != nil {
+		return ExecutionAnalytics{}, err
+	}
+
+	return ComputeAnalytics(quiz, execution, participants, answers), nil
+}
+
+func (qs *QuizServiceImpl) GetExecutionResults(ctx context.Context, quiz Quiz, execution Execution) ([]ParticipantResult, error) {
+	participants, answers, err := qs.executionRecords(ctx, execution)
+	if err != nil {
+		return nil, err
+	}
+
+	return ComputeResults(quiz, participants, answers), nil
+}
+
+func (qs *QuizServiceImpl) DeleteAll(ctx context.Context, ownerId string) error {
+	quizzes, err := qs.store.GetQuizzes(ctx, ownerId)
+	if err != nil {
+		return err
+	}
+
+	shared, err := qs.store.GetSharedQuizzes(ctx, ownerId)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range shared {
+		if err2 := qs.store.DeleteCollaborator(ctx, s.OwnerId, s.QuizId, ownerId); err2 != nil && !errors.Is(err2, ErrNotFound) {
+			return err2
+		}
 	}
 
-	if err := qs.resolver.BindCode(ownerId, quiz); err != nil {
+	// Withdrawn reactions are removed from aggregates of quizzes they were given to.
+	reactions, err := qs.store.GetReactions(ctx, ownerId)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	for _, r := range reactions {
+		if err2 := qs.store.UpdateReaction(ctx, ownerId, r.OwnerId, r.QuizId, func(reaction *Reaction) {
+			reaction.Starred = false
+			reaction.Rating = 0
+		}); err2 != nil {
+			return err2
+		}
+	}
+
+	// Codes of running executions must be released, they would otherwise resolve to removed data.
+	for _, quiz := range quizzes {
+		// Reverse indexes of collaborators live outside the owner documents.
+		collaborators, err2 := qs.store.GetCollaborators(ctx, ownerId, quiz.Id)
+		if err2 != nil {
+			return err2
+		}
+
+		for _, c := range collaborators {
+			if err3 := qs.store.DeleteCollaborator(ctx, ownerId, quiz.Id, c.Uid); err3 != nil && !errors.Is(err3, ErrNotFound) {
+				return err3
+			}
+		}
+
+		executions, err2 := qs.store.GetExecutions(ctx, ownerId, quiz.Id)
+		if err2 != nil {
+			return err2
+		}
+
+		for _, execution := range executions {
+			if execution.Status == ExecutionFinished {
+				continue
+			}
+
+			if err3 := qs.resolver.UnbindCode(ctx, execution.Code); err3 != nil {
+				return err3
+			}
+			if err3 := qs.resolver.ResetRoomPeople(ctx, execution.Id); err3 != nil {
+				return err3
+			}
+		}
+	}
+
+	return qs.store.DeleteAll(ctx, ownerId)
+}
+
+func (qs *QuizServiceImpl) GetAccessible(ctx context.Context, uid, quizId string) (QuizAccess, error) {
+	if quiz, err := qs.store.GetUnique(ctx, uid, quizId); err == nil {
+		return QuizAccess{Quiz: quiz, OwnerId: uid, Role: QuizRoleOwner}, nil
+	} else if !isNotFound(err) {
+		return QuizAccess{}, err
+	}
+
+	shared, err := qs.store.GetSharedQuiz(ctx, uid, quizId)
+	if err != nil {
+		return QuizAccess{}, err
+	}
+
+	quiz, err2 := qs.store.GetUnique(ctx, shared.OwnerId, quizId)
+	if err2 != nil {
+		return QuizAccess{}, err2
+	}
+
+	return QuizAccess{Quiz: quiz, OwnerId: shared.OwnerId, Role: shared.Role}, nil
+}
+
+func (qs *QuizServiceImpl) GetShared(ctx context.Context, uid string) ([]QuizAccess, error) {
+	shared, err := qs.store.GetSharedQuizzes(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	arr := make([]QuizAccess, 0, len(shared))
+	for _, s := range shared {
+		quiz, err2 := qs.store.GetUnique(ctx, s.OwnerId, s.QuizId)
+		if errors.Is(err2, ErrNotFound) {
+			// The quiz was removed by its owner, its reverse index is stale.
+			continue
+		} else if err2 != nil {
+			return nil, err2
+		}
+
+		arr = append(arr, QuizAccess{Quiz: quiz, OwnerId: s.OwnerId, Role: s.Role})
+	}
+
+	return arr, nil
 }
 
-func (qs *QuizServiceImpl) QuizFromCode(code string) (Quiz, error) {
-	if str, err := qs.resolver.GetQuiz(code); err != nil {
+func (qs *QuizServiceImpl) AddCollaborator(ctx context.Context, ownerId, quizId string, user UserRef, role QuizRole) (Collaborator, error) {
+	if !role.IsGrantable() || user.Uid == ownerId {
+		return Collaborator{}, ErrInvalidCollaborator
+	}
+
+	collaborator := Collaborator{Uid: user.Uid, Username: user.Username, Role: role, AddedAt: time.Now().UTC()}
+	return collaborator, qs.store.UpsertCollaborator(ctx, ownerId, quizId, collaborator)
+}
+
+func (qs *QuizServiceImpl) GetCollaborators(ctx context.Context, ownerId, quizId string) ([]Collaborator, error) {
+	return qs.store.GetCollaborators(ctx, ownerId, quizId)
+}
+
+func (qs *QuizServiceImpl) RemoveCollaborator(ctx context.Context, ownerId, quizId, uid string) error {
+	return qs.store.DeleteCollaborator(ctx, ownerId, quizId, uid)
+}
+
+func (qs *QuizServiceImpl) Search(ctx context.Context, query SearchQuery) (SearchResult, error) {
+	query.Normalize()
+
+	// Services built without any index have an empty library.
+	if qs.index == nil {
+		return SearchResult{
+			Data:     make([]LibraryEntry, 0),
+			Page:     query.Page,
+			PageSize: query.PageSize,
+			Facets:   SearchFacets{Tags: facetsOf(nil), Categories: facetsOf(nil), Languages: facetsOf(nil)},
+		}, nil
+	}
+
+	return qs.index.Search(ctx, query)
+}
+
+func (qs *QuizServiceImpl) IncrRoomPeople(ctx context.Context, executionId string) error {
+	return qs.resolver.IncrRoomPeople(ctx, executionId)
+}
+
+func (qs *QuizServiceImpl) GetRoomPeople(ctx context.Context, executionId string) (int, error) {
+	return qs.resolver.GetRoomPeople(ctx, executionId)
+}
+
+func (qs *QuizServiceImpl) ResetRoomPeople(ctx context.Context, executionId string) error {
+	return qs.resolver.ResetRoomPeople(ctx, executionId)
+}
+
+func (qs *QuizServiceImpl) Star(ctx context.Context, uid, ownerId, quizId string) error {
+	quiz, err := qs.store.GetUnique(ctx, ownerId, quizId)
+	if err != nil {
+		return err
+	} else if !quiz.IsShared() {
+		return ErrNotFound
+	}
+
+	return qs.store.UpdateReaction(ctx, uid, ownerId, quizId, func(reaction *Reaction) {
+		reaction.Starred = true
+	})
+}
+
+func (qs *QuizServiceImpl) Unstar(ctx context.Context, uid, ownerId, quizId string) error {
+	return qs.store.UpdateReaction(ctx, uid, ownerId, quizId, func(reaction *Reaction) {
+		reaction.Starred = false
+	})
+}
+
+func (qs *QuizServiceImpl) Rate(ctx context.Context, uid, ownerId, quizId string, rating int) error {
+	if rating < MinRating || rating > MaxRating || uid == ownerId {
+		return ErrInvalidRating
+	}
+
+	quiz, err := qs.store.GetUnique(ctx, ownerId, quizId)
+	if err != nil {
+		return err
+	} else if !quiz.IsShared() {
+		return ErrNotFound
+	}
+
+	return qs.store.UpdateReaction(ctx, uid, ownerId, quizId, func(reaction *Reaction) {
+		reaction.Rating = rating
+	})
+}
+
+func (qs *QuizServiceImpl) Unrate(ctx context.Context, uid, ownerId, quizId string) error {
+	return qs.store.UpdateReaction(ctx, uid, ownerId, quizId, func(reaction *Reaction) {
+		reaction.Rating = 0
+	})
+}
+
+func (qs *QuizServiceImpl) GetFavorites(ctx context.Context, uid string) ([]LibraryEntry, error) {
+	reactions, err := qs.store.GetReactions(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(reactions, func(i, j int) bool {
+		return reactions[i].UpdatedAt.After(reactions[j].UpdatedAt)
+	})
+
+	arr := make([]LibraryEntry, 0)
+	for _, r := range reactions {
+		if !r.Starred {
+			continue
+		}
+
+		quiz, err2 := qs.store.GetUnique(ctx, r.OwnerId, r.QuizId)
+		if errors.Is(err2, ErrNotFound) {
+			continue
+		} else if err2 != nil {
+			return nil, err2
+		}
+
+		if quiz.IsShared() {
+			arr = append(arr, NewLibraryEntry(r.OwnerId, quiz))
+		}
+	}
+
+	return arr, nil
+}
+
+func (qs *QuizServiceImpl) GetReactions(ctx context.Context, uid string) ([]Reaction, error) {
+	return qs.store.GetReactions(ctx, uid)
+}
+
+func (qs *QuizServiceImpl) Fork(ctx context.Context, uid, ownerId, quizId string) (Quiz, error) {
+	if uid == ownerId {
+		return Quiz{}, ErrInvalidFork
+	}
+
+	source, err := qs.store.GetUnique(ctx, ownerId, quizId)
+	if err != nil {
 		return Quiz{}, err
-	} else {
-		ids := strings.Split(str, "@")
-		return qs.store.GetUnique(ids[0], ids[1])
+	} else if !source.IsShared() {
+		return Quiz{}, ErrNotFound
 	}
+
+	fork := NewFork(ownerId, source)
+	if err2 := qs.store.InsertFork(ctx, uid, fork); err2 != nil {
+		return Quiz{}, err2
+	}
+
+	return fork, nil
 }
-func (qs *QuizServiceImpl) IncrRoomPeople(roomId string) error {
-	return qs.resolver.IncrRoomPeople(roomId)
+
+func (qs *QuizServiceImpl) GetLibraryOwner(ctx context.Context, quizId string) (string, error) {
+	// Services built without any index have an empty library.
+	if qs.index == nil {
+		return "", ErrNotFound
+	}
+
+	return qs.index.Owner(ctx, quizId)
 }
 
-func (qs *QuizServiceImpl) GetRoomPeople(roomId string) (int, error) {
-	return qs.resolver.GetRoomPeople(roomId)
+func (qs *QuizServiceImpl) GetHistory(ctx context.Context, uid string, page, pageSize int) (HistoryPage, error) {
+	page, pageSize = normalizePage(page, pageSize)
+
+	entries, total, err := qs.store.GetHistory(ctx, uid, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return HistoryPage{}, err
+	}
+
+	return HistoryPage{Data: entries, Total: total, Page: page, PageSize: pageSize}, nil
 }
 
-func (qs *QuizServiceImpl) ResetRoomPeople(roomId string) error {
-	return qs.resolver.ResetRoomPeople(roomId)
+func (qs *QuizServiceImpl) GetStats(ctx context.Context, ownerId string, quiz Quiz) (QuizStatsReport, error) {
+	stats, err := qs.store.GetStats(ctx, ownerId, quiz.Id)
+	if err != nil {
+		return QuizStatsReport{}, err
+	}
+
+	return NewStatsReport(quiz, stats), nil
 }
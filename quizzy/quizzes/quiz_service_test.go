@@ -1,15 +1,17 @@
 package quizzes
 
 import (
+	"context"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
 func _createDummyQuizService() QuizService {
 	return &QuizServiceImpl{
 		store:    &dummyQuizStoreImpl{entries: make([]dummyEntry, 0)},
-		resolver: &dummyCodeResolver{entries: make(map[string]string)},
+		resolver: _newDummyCodeResolver(),
 	}
 }
 
@@ -17,20 +19,14 @@ func TestCreateAndGetQuiz(t *testing.T) {
 	svc := _createDummyQuizService()
 	ownerId := uuid.New().String()
 
-	code, err := GenerateCode()
-	if err != nil {
-		t.Fatalf("failed to generate unique code: %s", err)
-	}
-
 	expected := Quiz{
 		Id:          uuid.New().String(),
 		Title:       "quiz-title",
 		Description: "test-description",
 		Questions:   make([]Question, 0),
-		Code:        code,
 	}
 
-	err2 := svc.Create(ownerId, expected)
+	err2 := svc.Create(context.Background(), ownerId, expected)
 
 	if err2 != nil {
 		t.Fatalf("failed to create quiz: %s", err2)
@@ -38,12 +34,231 @@ func TestCreateAndGetQuiz(t *testing.T) {
 
 	// Try to get newly created quiz.
 
-	if quiz, err3 := svc.Get(ownerId, expected.Id); err3 != nil {
+	if quiz, err3 := svc.Get(context.Background(), ownerId, expected.Id); err3 != nil {
 		t.Fatalf("failed to get quiz: %s", err3)
 	} else {
 		assert.Equal(t, quiz.Title, expected.Title)
 		assert.Equal(t, quiz.Description, expected.Description)
 		assert.Equal(t, len(quiz.Questions), len(expected.Questions))
-		assert.Equal(t, quiz.Code, expected.Code)
 	}
 }
+
+func _readyQuiz() Quiz {
+	return Quiz{
+		Id:    uuid.New().String(),
+		Title: "quiz-title",
+		Questions: []Question{
+			{
+				Id:    uuid.New().String(),
+				Title: "question",
+				Answers: []Answer{
+					{Id: uuid.New().String(), Title: "yes", IsCorrect: true},
+					{Id: uuid.New().String(), Title: "no"},
+				},
+			},
+		},
+	}
+}
+
+func TestPatchQuizVisibility(t *testing.T) {
+	svc := _createDummyQuizService()
+	quiz := _readyQuiz()
+	_ = svc.Create(context.Background(), "owner", quiz)
+
+	err := svc.Patch(context.Background(), "owner", quiz.Id, []FieldPatchOp{{Op: "replace", Path: "/visibility", Value: "public"}})
+	assert.Nil(t, err)
+	patched, _ := svc.Get(context.Background(), "owner", quiz.Id)
+	assert.Equal(t, VisibilityPublic, patched.Visibility)
+
+	err = svc.Patch(context.Background(), "owner", quiz.Id, []FieldPatchOp{{Op: "replace", Path: "/visibility", Value: "everyone"}})
+	assert.ErrorIs(t, err, ErrInvalidPatchField)
+
+	// Only allowed fields may be patched, whatever the store.
+	err = svc.Patch(context.Background(), "owner", quiz.Id, []FieldPatchOp{{Op: "replace", Path: "/ownerId", Value: "someone"}})
+	assert.ErrorIs(t, err, ErrInvalidPatchField)
+}
+
+func TestPublicQuizStripsAnswers(t *testing.T) {
+	quiz := _readyQuiz()
+
+	for _, q := range quiz.Public(false).Questions {
+		for _, a := range q.Answers {
+			assert.Nil(t, a.IsCorrect)
+		}
+	}
+
+	revealed := quiz.Public(true)
+	assert.Equal(t, quiz.Questions[0].Answers[0].IsCorrect, *revealed.Questions[0].Answers[0].IsCorrect)
+}
+
+func TestGenerateCodeAlphabet(t *testing.T) {
+	code, err := GenerateCode()
+
+	assert.Nil(t, err)
+	assert.Len(t, code, CodeLength)
+	for _, c := range code {
+		assert.Contains(t, CodeAlphabet, string(c))
+	}
+}
+
+func TestStartQuizCreatesIndependentExecutions(t *testing.T) {
+	svc := _createDummyQuizService()
+	ownerId := uuid.New().String()
+	quiz := _readyQuiz()
+	_ = svc.Create(context.Background(), ownerId, quiz)
+
+	first, err := svc.StartQuiz(context.Background(), ownerId, quiz)
+	assert.Nil(t, err)
+
+	second, err2 := svc.StartQuiz(context.Background(), ownerId, quiz)
+	assert.Nil(t, err2)
+	assert.NotEqual(t, first.Id, second.Id)
+	assert.NotEqual(t, first.Code, second.Code)
+	assert.Equal(t, ExecutionWaiting, second.Status)
+
+	// Lowercase codes typed by players must still be resolved.
+	execution, resolved, err3 := svc.ExecutionFromCode(context.Background(), strings.ToLower(second.Code))
+	assert.Nil(t, err3)
+	assert.Equal(t, second.Id, execution.Id)
+	assert.Equal(t, quiz.Id, resolved.Id)
+
+	// Each execution has its own participant count.
+	_ = svc.IncrRoomPeople(context.Background(), first.Id)
+	n1, _ := svc.GetRoomPeople(context.Background(), first.Id)
+	n2, _ := svc.GetRoomPeople(context.Background(), second.Id)
+	assert.Equal(t, 1, n1)
+	assert.Equal(t, 0, n2)
+
+	executions, err4 := svc.GetExecutions(context.Background(), ownerId, quiz.Id)
+	assert.Nil(t, err4)
+	assert.Len(t, executions, 2)
+}
+
+func TestStartQuizRetriesOnCollision(t *testing.T) {
+	resolver := &collidingCodeResolver{dummyCodeResolver: _newDummyCodeResolver(), collisions: 3}
+	svc := &QuizServiceImpl{store: _createDummyStore(), resolver: resolver}
+	quiz := _readyQuiz()
+	_ = svc.Create(context.Background(), "owner", quiz)
+
+	execution, err := svc.StartQuiz(context.Background(), "owner", quiz)
+	assert.Nil(t, err)
+	assert.Len(t, execution.Code, CodeLength)
+	assert.Equal(t, 4, resolver.attempts)
+}
+
+func TestStartQuizNoCodeAvailable(t *testing.T) {
+	resolver := &collidingCodeResolver{dummyCodeResolver: _newDummyCodeResolver(), collisions: MaxCodeAttempts}
+	svc := &QuizServiceImpl{store: _createDummyStore(), resolver: resolver}
+
+	_, err := svc.StartQuiz(context.Background(), "owner", _readyQuiz())
+	assert.ErrorIs(t, err, ErrNoCodeAvailable)
+}
+
+func TestEndExecutionReleasesCode(t *testing.T) {
+	svc := _createDummyQuizService()
+	quiz := _readyQuiz()
+	_ = svc.Create(context.Background(), "owner", quiz)
+
+	execution, _ := svc.StartQuiz(context.Background(), "owner", quiz)
+	assert.Nil(t, svc.EndExecution(context.Background(), execution))
+
+	_, _, err := svc.ExecutionFromCode(context.Background(), execution.Code)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	ended, _ := svc.GetExecution(context.Background(), "owner", quiz.Id, execution.Id)
+	assert.Equal(t, ExecutionFinished, ended.Status)
+	assert.NotNil(t, ended.EndedAt)
+}
+
+func TestDeleteAllReleasesCodes(t *testing.T) {
+	svc := _createDummyQuizService()
+	quiz := _readyQuiz()
+	_ = svc.Create(context.Background(), "owner", quiz)
+	_ = svc.Create(context.Background(), "other", _readyQuiz())
+
+	execution, _ := svc.StartQuiz(context.Background(), "owner", quiz)
+	assert.Nil(t, svc.DeleteAll(context.Background(), "owner"))
+
+	_, _, err := svc.ExecutionFromCode(context.Background(), execution.Code)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	owned, _ := svc.GetAll(context.Background(), "owner")
+	assert.Empty(t, owned)
+	others, _ := svc.GetAll(context.Background(), "other")
+	assert.Len(t, others, 1)
+}
+
+// collidingCodeResolver reports the first reservations as already taken.
+type collidingCodeResolver struct {
+	*dummyCodeResolver
+	collisions int
+	attempts   int
+}
+
+func (c *collidingCodeResolver) BindCode(ctx context.Context, code string, ref ExecutionRef) error {
+	c.attempts++
+	if c.attempts <= c.collisions {
+		return ErrCodeTaken
+	}
+
+	return c.dummyCodeResolver.BindCode(ctx, code, ref)
+}
+
+func TestCollaboratorAccess(t *testing.T) {
+	svc := _createDummyQuizService()
+	quiz := _readyQuiz()
+	_ = svc.Create(context.Background(), "owner", quiz)
+
+	_, err := svc.GetAccessible(context.Background(), "editor", quiz.Id)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = svc.AddCollaborator(context.Background(), "owner", quiz.Id, UserRef{Uid: "editor", Username: "ed"}, QuizRoleEditor)
+	assert.Nil(t, err)
+
+	access, err2 := svc.GetAccessible(context.Background(), "editor", quiz.Id)
+	assert.Nil(t, err2)
+	assert.Equal(t, "owner", access.OwnerId)
+	assert.Equal(t, QuizRoleEditor, access.Role)
+	assert.Equal(t, quiz.Title, access.Quiz.Title)
+
+	owned, _ := svc.GetAccessible(context.Background(), "owner", quiz.Id)
+	assert.Equal(t, QuizRoleOwner, owned.Role)
+
+	shared, _ := svc.GetShared(context.Background(), "editor")
+	assert.Len(t, shared, 1)
+
+	assert.Nil(t, svc.RemoveCollaborator(context.Background(), "owner", quiz.Id, "editor"))
+	_, err = svc.GetAccessible(context.Background(), "editor", quiz.Id)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestAddCollaboratorRefusesInvalidGrants(t *testing.T) {
+	svc := _createDummyQuizService()
+	quiz := _readyQuiz()
+	_ = svc.Create(context.Background(), "owner", quiz)
+
+	_, err := svc.AddCollaborator(context.Background(), "owner", quiz.Id, UserRef{Uid: "other"}, QuizRoleOwner)
+	assert.ErrorIs(t, err, ErrInvalidCollaborator)
+
+	_, err = svc.AddCollaborator(context.Background(), "owner", quiz.Id, UserRef{Uid: "owner"}, QuizRoleEditor)
+	assert.ErrorIs(t, err, ErrInvalidCollaborator)
+}
+
+func TestQuizRoleGrants(t *testing.T) {
+	assert.True(t, QuizRoleOwner.Grants(QuizRoleEditor))
+	assert.True(t, QuizRoleEditor.Grants(QuizRoleViewer))
+	assert.False(t, QuizRoleViewer.Grants(QuizRoleEditor))
+	assert.False(t, QuizRole("unknown").Grants(QuizRoleViewer))
+}
+
+func TestDeleteAllRevokesCollaborators(t *testing.T) {
+	svc := _createDummyQuizService()
+	quiz := _readyQuiz()
+	_ = svc.Create(context.Background(), "owner", quiz)
+	_, _ = svc.AddCollaborator(context.Background(), "owner", quiz.Id, UserRef{Uid: "viewer"}, QuizRoleViewer)
+
+	assert.Nil(t, svc.DeleteAll(context.Background(), "owner"))
+
+	shared, _ := svc.GetShared(context.Background(), "viewer")
+	assert.Empty(t, shared)
+}
@@ -0,0 +1,202 @@
+package quizzes
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// ScoreBucketCount is the number of buckets of the score distribution, each covering
+	// 10% of correct answers (the last one includes 100%).
+	ScoreBucketCount = 10
+	// TopQuestionCount is how many hardest and easiest questions are reported.
+	TopQuestionCount = 3
+)
+
+// QuestionTotals aggregates answers to a question over every execution.
+type QuestionTotals struct {
+	Participants int `firestore:"participants" json:"participants"`
+	Answered     int `firestore:"answered" json:"answered"`
+	Correct      int `firestore:"correct" json:"correct"`
+}
+
+// QuizStats aggregates every ended execution of a quiz, stored under
+// "users/{ownerId}/quizzes/{quizId}/stats/totals". Each execution is added once ended
+// (see Store.AddStats), so stats are read without scanning executions.
+type QuizStats struct {
+	Sessions int `firestore:"sessions" json:"sessions"`
+	Players  int `firestore:"players" json:"players"`
+	ScoreSum int `firestore:"scoreSum" json:"scoreSum"`
+	// ScoreRateSum sums the percentage of correct answers of every player.
+	ScoreRateSum float64 `firestore:"scoreRateSum" json:"scoreRateSum"`
+	// ScoreBuckets counts players by bucket index of their percentage of correct answers.
+	ScoreBuckets map[string]int `firestore:"scoreBuckets" json:"scoreBuckets"`
+	// DurationSum sums durations of executions, from their first question to their end, in milliseconds.
+	DurationSum int64                     `firestore:"durationSum" json:"durationSum"`
+	Questions   map[string]QuestionTotals `firestore:"questions" json:"questions"`
+}
+
+func scoreBucket(rate float64) int {
+	return min(int(rate)/(100/ScoreBucketCount), ScoreBucketCount-1)
+}
+
+// StatsOf returns the stats of a single ended execution, to be added to quiz stats.
+func StatsOf(quiz Quiz, execution Execution, analytics ExecutionAnalytics, results []ParticipantResult) QuizStats {
+	stats := QuizStats{
+		Sessions:     1,
+		Players:      len(results),
+		ScoreBuckets: make(map[string]int),
+		Questions:    make(map[string]QuestionTotals),
+	}
+
+	for _, result := range results {
+		rate := 0.0
+		if len(quiz.Questions) > 0 {
+			rate = float64(result.Score) * 100 / float64(len(quiz.Questions))
+		}
+
+		stats.ScoreSum += result.Score
+		stats.ScoreRateSum += rate
+		stats.ScoreBuckets[strconv.Itoa(scoreBucket(rate))]++
+	}
+
+	for _, question := range analytics.Questions {
+		if question.Asked {
+			stats.Questions[question.QuestionId] = QuestionTotals{
+				Participants: question.Participants,
+				Answered:     question.Answered,
+				Correct:      question.Correct,
+			}
+		}
+	}
+
+	var start time.Time
+	for _, askedAt := range execution.AskedAt {
+		if start.IsZero() || askedAt.Before(start) {
+			start = askedAt
+		}
+	}
+	if execution.EndedAt != nil && !start.IsZero() {
+		stats.DurationSum = execution.EndedAt.Sub(start).Milliseconds()
+	}
+
+	return stats
+}
+
+// Add adds the given stats to these ones.
+func (s *QuizStats) Add(delta QuizStats) {
+	s.Sessions += delta.Sessions
+	s.Players += delta.Players
+	s.ScoreSum += delta.ScoreSum
+	s.ScoreRateSum += delta.ScoreRateSum
+	s.DurationSum += delta.DurationSum
+
+	if s.ScoreBuckets == nil {
+		s.ScoreBuckets = make(map[string]int)
+	}
+	for bucket, count := range delta.ScoreBuckets {
+		s.ScoreBuckets[bucket] += count
+	}
+
+	if s.Questions == nil {
+		s.Questions = make(map[string]QuestionTotals)
+	}
+	for id, totals := range delta.Questions {
+		current := s.Questions[id]
+		current.Participants += totals.Participants
+		current.Answered += totals.Answered
+		current.Correct += totals.Correct
+		s.Questions[id] = current
+	}
+}
+
+// ScoreRange counts players whose percentage of correct answers is in [From, To].
+type ScoreRange struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Count int `json:"count"`
+}
+
+// QuestionRate is how often a question was answered correctly.
+type QuestionRate struct {
+	QuestionId string `json:"questionId"`
+	Title      string `json:"title"`
+	// CorrectRate is the percentage of participants who answered correctly, over every execution.
+	CorrectRate  float64 `json:"correctRate"`
+	Participants int     `json:"participants"`
+}
+
+// QuizStatsReport is how stats of a quiz are served.
+type QuizStatsReport struct {
+	Sessions int `json:"sessions"`
+	Players  int `json:"players"`
+	// AverageScore is the average number of correct answers per player.
+	AverageScore float64 `json:"averageScore"`
+	// AverageScoreRate is the average percentage of correct answers per player.
+	AverageScoreRate  float64        `json:"averageScoreRate"`
+	ScoreDistribution []ScoreRange   `json:"scoreDistribution"`
+	HardestQuestions  []QuestionRate `json:"hardestQuestions"`
+	EasiestQuestions  []QuestionRate `json:"easiestQuestions"`
+	// AverageDuration of executions, from their first question to their end, in milliseconds.
+	AverageDuration int64 `json:"averageDuration"`
+}
+
+// NewStatsReport returns the report of the given stats of the given quiz. Questions removed
+// from the quiz since, or never asked, aren't ranked.
+func NewStatsReport(quiz Quiz, stats QuizStats) QuizStatsReport {
+	report := QuizStatsReport{
+		Sessions:          stats.Sessions,
+		Players:           stats.Players,
+		ScoreDistribution: make([]ScoreRange, 0, ScoreBucketCount),
+	}
+
+	if stats.Players > 0 {
+		report.AverageScore = float64(stats.ScoreSum) / float64(stats.Players)
+		report.AverageScoreRate = stats.ScoreRateSum / float64(stats.Players)
+	}
+	if stats.Sessions > 0 {
+		report.AverageDuration = stats.DurationSum / int64(stats.Sessions)
+	}
+
+	width := 100 / ScoreBucketCount
+	for i := 0; i < ScoreBucketCount; i++ {
+		to := (i+1)*width - 1
+		if i == ScoreBucketCount-1 {
+			to = 100
+		}
+		report.ScoreDistribution = append(report.ScoreDistribution, ScoreRange{
+			From:  i * width,
+			To:    to,
+			Count: stats.ScoreBuckets[strconv.Itoa(i)],
+		})
+	}
+
+	rates := make([]QuestionRate, 0, len(quiz.Questions))
+	for _, question := range quiz.Questions {
+		totals, ok := stats.Questions[question.Id]
+		if !ok || totals.Participants == 0 {
+			continue
+		}
+
+		rates = append(rates, QuestionRate{
+			QuestionId:   question.Id,
+			Title:        question.Title,
+			CorrectRate:  float64(totals.Correct) * 100 / float64(totals.Participants),
+			Participants: totals.Participants,
+		})
+	}
+
+	// Stable, so questions with the same rate keep the quiz order.
+	sort.SliceStable(rates, func(i, j int) bool {
+		return rates[i].CorrectRate < rates[j].CorrectRate
+	})
+
+	report.HardestQuestions = rates[:min(TopQuestionCount, len(rates))]
+	report.EasiestQuestions = make([]QuestionRate, 0, TopQuestionCount)
+	for i := len(rates) - 1; i >= 0 && len(report.EasiestQuestions) < TopQuestionCount; i-- {
+		report.EasiestQuestions = append(report.EasiestQuestions, rates[i])
+	}
+
+	return report
+}
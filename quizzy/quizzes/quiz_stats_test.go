@@ -0,0 +1,178 @@
+package quizzes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsOf(t *testing.T) {
+	quiz := _twoQuestionsQuiz()
+	first := quiz.Questions[0]
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Second)
+	execution := Execution{
+		AskedAt: map[string]time.Time{first.Id: start},
+		EndedAt: &end,
+	}
+	analytics := ExecutionAnalytics{Questions: []QuestionAnalytics{
+		{QuestionId: first.Id, Asked: true, Participants: 2, Answered: 2, Correct: 1},
+		{QuestionId: quiz.Questions[1].Id},
+	}}
+	results := []ParticipantResult{
+		{Participant: Participant{Id: "alice"}, Score: 2},
+		{Participant: Participant{Id: "bob"}, Score: 1},
+	}
+
+	stats := StatsOf(quiz, execution, analytics, results)
+	assert.Equal(t, QuizStats{
+		Sessions:     1,
+		Players:      2,
+		ScoreSum:     3,
+		ScoreRateSum: 150,
+		ScoreBuckets: map[string]int{"9": 1, "5": 1},
+		DurationSum:  90000,
+		Questions: map[string]QuestionTotals{
+			first.Id: {Participants: 2, Answered: 2, Correct: 1},
+		},
+	}, stats)
+}
+
+func TestNewStatsReport(t *testing.T) {
+	quiz := _twoQuestionsQuiz()
+	first, second := quiz.Questions[0], quiz.Questions[1]
+
+	var stats QuizStats
+	stats.Add(QuizStats{
+		Sessions:     1,
+		Players:      2,
+		ScoreSum:     3,
+		ScoreRateSum: 150,
+		ScoreBuckets: map[string]int{"9": 1, "5": 1},
+		DurationSum:  60000,
+		Questions: map[string]QuestionTotals{
+			first.Id:  {Participants: 2, Answered: 2, Correct: 2},
+			second.Id: {Participants: 2, Answered: 1},
+		},
+	})
+	stats.Add(QuizStats{
+		Sessions:     1,
+		Players:      2,
+		ScoreSum:     0,
+		ScoreBuckets: map[string]int{"0": 2},
+		DurationSum:  30000,
+		Questions: map[string]QuestionTotals{
+			first.Id:  {Participants: 2, Answered: 2},
+			"removed": {Participants: 2},
+		},
+	})
+
+	report := NewStatsReport(quiz, stats)
+	assert.Equal(t, 2, report.Sessions)
+	assert.Equal(t, 4, report.Players)
+	assert.Equal(t, 0.75, report.AverageScore)
+	assert.Equal(t, 37.5, report.AverageScoreRate)
+	assert.Equal(t, int64(45000), report.AverageDuration)
+
+	assert.Len(t, report.ScoreDistribution, ScoreBucketCount)
+	assert.Equal(t, ScoreRange{From: 0, To: 9, Count: 2}, report.ScoreDistribution[0])
+	assert.Equal(t, ScoreRange{From: 50, To: 59, Count: 1}, report.ScoreDistribution[5])
+	assert.Equal(t, ScoreRange{From: 90, To: 100, Count: 1}, report.ScoreDistribution[9])
+
+	assert.Equal(t, []QuestionRate{
+		{QuestionId: second.Id, Title: second.Title, CorrectRate: 0, Participants: 2},
+		{QuestionId: first.Id, Title: first.Title, CorrectRate: 50, Participants: 4},
+	}, report.HardestQuestions)
+	assert.Equal(t, []string{first.Id, second.Id}, []string{
+		report.EasiestQuestions[0].QuestionId,
+		report.EasiestQuestions[1].QuestionId,
+	})
+}
+
+func TestNewStatsReportWithoutExecution(t *testing.T) {
+	report := NewStatsReport(_readyQuiz(), QuizStats{})
+	assert.Zero(t, report.Sessions)
+	assert.Zero(t, report.AverageScore)
+	assert.Len(t, report.ScoreDistribution, ScoreBucketCount)
+	assert.Empty(t, report.HardestQuestions)
+	assert.Empty(t, report.EasiestQuestions)
+}
+
+func TestEndExecutionAddsStatsOnce(t *testing.T) {
+	svc := _createDummyQuizService()
+	ctx := context.Background()
+	quiz := _readyQuiz()
+	_ = svc.Create(ctx, "owner", quiz)
+
+	execution := _playExecution(t, svc, quiz)
+	ended, err := svc.GetExecution(ctx, "owner", quiz.Id, execution.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, svc.EndExecution(ctx, ended))
+	// Even from a copy read before the execution ended.
+	assert.Nil(t, svc.EndExecution(ctx, execution))
+
+	report, err := svc.GetStats(ctx, "owner", quiz)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, report.Sessions)
+	assert.Equal(t, 2, report.Players)
+	assert.Equal(t, 0.5, report.AverageScore)
+	assert.Equal(t, 1, report.ScoreDistribution[0].Count)
+	assert.Equal(t, 1, report.ScoreDistribution[ScoreBucketCount-1].Count)
+	assert.Equal(t, []QuestionRate{{
+		QuestionId:   quiz.Questions[0].Id,
+		Title:        quiz.Questions[0].Title,
+		CorrectRate:  50,
+		Participants: 2,
+	}}, report.HardestQuestions)
+}
+
+type failingStatsStore struct {
+	Store
+	failures int
+}
+
+func (s *failingStatsStore) AddStats(ctx context.Context, ref ExecutionRef, delta QuizStats) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("unavailable")
+	}
+
+	return s.Store.AddStats(ctx, ref, delta)
+}
+
+func TestEndExecutionRecordsStatsUntilSuccess(t *testing.T) {
+	store := &failingStatsStore{Store: &dummyQuizStoreImpl{entries: make([]dummyEntry, 0)}, failures: 1}
+	svc := &QuizServiceImpl{store: store, resolver: _newDummyCodeResolver()}
+	ctx := context.Background()
+	quiz := _readyQuiz()
+	_ = svc.Create(ctx, "owner", quiz)
+
+	execution, err := svc.StartQuiz(ctx, "owner", quiz)
+	assert.Nil(t, err)
+	question := quiz.Questions[0]
+	execution.Status = ExecutionStarted
+	execution.Cursor = 1
+	execution.AskedAt = map[string]time.Time{question.Id: time.Now().UTC()}
+	assert.Nil(t, svc.UpdateExecution(ctx, execution))
+	_ = svc.JoinExecution(ctx, execution, Participant{Id: "alice", Nickname: "Alice", Registered: true})
+	_ = svc.JoinExecution(ctx, execution, Participant{Id: "bob", Nickname: "Bob", Registered: true})
+
+	// The execution is finished even though its results failed to be recorded.
+	assert.NotNil(t, svc.EndExecution(ctx, execution))
+	ended, err := svc.GetExecution(ctx, "owner", quiz.Id, execution.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, ExecutionFinished, ended.Status)
+	assert.False(t, ended.ResultsRecorded)
+
+	// Ending it again records them, once.
+	assert.Nil(t, svc.EndExecution(ctx, ended))
+	assert.Nil(t, svc.EndExecution(ctx, ended))
+
+	report, err := svc.GetStats(ctx, "owner", quiz)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, report.Sessions)
+	assert.Equal(t, 2, report.Players)
+}
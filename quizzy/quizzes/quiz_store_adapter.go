@@ -1,15 +1,30 @@
 package quizzes
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"time"
 )
 
 var (
 	ErrNotFound             = errors.New("quiz not found")
 	ErrInvalidPatchOperator = errors.New("invalid patch operator")
 	ErrInvalidPatchField    = errors.New("invalid patch field")
+	ErrCodeTaken            = errors.New("execution code already taken")
+	ErrInvalidExecutionRef  = errors.New("invalid execution reference")
 )
 
+// isNotFound reports whether the given error tells a quiz (or one of its documents) doesn't
+// exist. Stores return ErrNotFound, a raw gRPC NotFound leaking from a backend is accepted too,
+// so it's never mistaken for an unexpected failure.
+func isNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound) || status.Code(err) == codes.NotFound
+}
+
 type FieldPatchOp struct {
 	Op    string `json:"op"`
 	Path  string `json:"path"`
@@ -20,13 +35,56 @@ type Links struct {
 	Start  string `json:"start,omitempty"`
 }
 
+// Visibility tells who may see a quiz besides its owner.
+type Visibility string
+
+const (
+	// VisibilityPrivate quizzes are only visible by their owner, it is the default.
+	VisibilityPrivate Visibility = "private"
+	// VisibilityUnlisted quizzes are visible by anyone knowing them, but never listed.
+	VisibilityUnlisted Visibility = "unlisted"
+	// VisibilityPublic quizzes are listed on their owner public profile.
+	VisibilityPublic Visibility = "public"
+)
+
+// IsValid reports whether the visibility is a known one.
+func (v Visibility) IsValid() bool {
+	return v == VisibilityPrivate || v == VisibilityUnlisted || v == VisibilityPublic
+}
+
 // Quiz describe available data for a quizzes.
 type Quiz struct {
 	Id          string     `firestore:"-" json:"id"`
 	Title       string     `firestore:"title" json:"title"`
 	Description string     `firestore:"description" json:"description"`
-	Questions   []Question `firestore:"-" json:"questions"`
-	Code        string     `firestore:"code" json:"code,omitempty"`
+	Visibility  Visibility `firestore:"visibility" json:"visibility"`
+	// Tags are lower-cased keywords used to search the library.
+	Tags []string `firestore:"tags" json:"tags"`
+	// Category is a single lower-cased subject (e.g. "history").
+	Category string `firestore:"category" json:"category"`
+	// Language of the quiz content, as a language tag (e.g. "fr", "en-US").
+	Language string `firestore:"language" json:"language"`
+	// FolderId is the folder holding the quiz, RootFolder if it wasn't moved to any.
+	FolderId string `firestore:"folderId" json:"folderId"`
+	// Aggregates of reactions, only updated through Store.UpdateReaction.
+	Stars       int `firestore:"stars" json:"stars"`
+	RatingCount int `firestore:"ratingCount" json:"ratingCount"`
+	RatingSum   int `firestore:"ratingSum" json:"ratingSum"`
+	// Forks counts copies of the quiz made from the library, only updated through Store.InsertFork.
+	Forks int `firestore:"forks" json:"forks"`
+	// ForkedFrom is nil unless the quiz was copied from another one.
+	ForkedFrom *ForkSource `firestore:"forkedFrom,omitempty" json:"forkedFrom,omitempty"`
+	Questions  []Question  `firestore:"-" json:"questions"`
+}
+
+// IsListed reports whether the quiz may be listed publicly.
+func (q *Quiz) IsListed() bool {
+	return q.Visibility == VisibilityPublic
+}
+
+// IsShared reports whether the quiz may be seen by anyone knowing it.
+func (q *Quiz) IsShared() bool {
+	return q.Visibility == VisibilityPublic || q.Visibility == VisibilityUnlisted
 }
 
 func (q *Quiz) Validate() bool {
@@ -79,31 +137,191 @@ type Answer struct {
 type Store interface {
 	// Upsert Store or update the given user, if no user with the given id exists,
 	// it will be created, otherwise it will be updated.
-	Upsert(ownerId string, quiz Quiz) error
+	Upsert(ctx context.Context, ownerId string, quiz Quiz) error
 
 	// GetUnique returns the user matching to the given uid,
 	// otherwise ErrNotFound is returned.
-	GetUnique(ownerId, uid string) (Quiz, error)
+	GetUnique(ctx context.Context, ownerId, uid string) (Quiz, error)
 
 	// GetQuizzes returns all quizzes owned by the given user.
-	GetQuizzes(ownerId string) ([]Quiz, error)
+	GetQuizzes(ctx context.Context, ownerId string) ([]Quiz, error)
+
+	// GetQuizzesInFolder returns quizzes owned by the given user held by the given folder.
+	GetQuizzesInFolder(ctx context.Context, ownerId, folderId string) ([]Quiz, error)
 
 	// Patch update the given quizzes.
-	Patch(ownerId, uid string, fields []FieldPatchOp) error
+	Patch(ctx context.Context, ownerId, uid string, fields []FieldPatchOp) error
 
-	GetUniqueQuestion(ownerId, quizId, questionId string) (Question, error)
+	GetUniqueQuestion(ctx context.Context, ownerId, quizId, questionId string) (Question, error)
 
-	UpsertQuestion(ownerId, quizId string, question Question) error
+	UpsertQuestion(ctx context.Context, ownerId, quizId string, question Question) error
 
 	// UpdateQuestion patch the given
-	UpdateQuestion(ownerId, quizId string, question Question) error
+	UpdateQuestion(ctx context.Context, ownerId, quizId string, question Question) error
+
+	// UpsertExecution store or update the given execution of the given quiz, but its ResultsRecorded flag.
+	UpsertExecution(ctx context.Context, ownerId, quizId string, execution Execution) error
+
+	// GetExecution returns the matching execution, otherwise ErrNotFound is returned.
+	GetExecution(ctx context.Context, ownerId, quizId, executionId string) (Execution, error)
+
+	// GetExecutions returns all executions of the given quiz.
+	GetExecutions(ctx context.Context, ownerId, quizId string) ([]Execution, error)
+
+	// AddParticipant stores the given participant of the given execution,
+	// participants who already joined are kept as is.
+	AddParticipant(ctx context.Context, ref ExecutionRef, participant Participant) error
+
+	// GetParticipants returns every participant of the given execution.
+	GetParticipants(ctx context.Context, ref ExecutionRef) ([]Participant, error)
+
+	// AddAnswer stores the given answer to the given execution, ErrAlreadyAnswered is returned
+	// if the participant already answered the question.
+	AddAnswer(ctx context.Context, ref ExecutionRef, answer PlayerAnswer) error
+
+	// GetAnswers returns every answer to the given execution.
+	GetAnswers(ctx context.Context, ref ExecutionRef) ([]PlayerAnswer, error)
+
+	// AddStats adds the given stats of an ended execution to the stats of its quiz, and flags
+	// the execution as recorded at once. Stats of an execution already flagged are ignored.
+	AddStats(ctx context.Context, ref ExecutionRef, delta QuizStats) error
+
+	// GetStats returns stats of the given quiz, zero stats if none of its executions ended yet.
+	GetStats(ctx context.Context, ownerId, quizId string) (QuizStats, error)
+
+	// UpsertHistory stores or replaces the given history entry of the given player.
+	UpsertHistory(ctx context.Context, uid string, entry HistoryEntry) error
+
+	// GetHistory returns limit history entries of the given player from offset, most recent
+	// first, along with the total number of entries.
+	GetHistory(ctx context.Context, uid string, offset, limit int) ([]HistoryEntry, int, error)
+
+	// DeleteAll removes every quiz owned by the given user, along with their questions,
+	// answers, executions and collaborators, then its folders, collections and history.
+	// Its participations (participant and answers) in executions of other users are removed too.
+	DeleteAll(ctx context.Context, ownerId string) error
+
+	// UpsertCollaborator grants the given collaborator its role on the given quiz,
+	// along with its SharedQuiz reverse index.
+	UpsertCollaborator(ctx context.Context, ownerId, quizId string, collaborator Collaborator) error
+
+	// GetCollaborators returns collaborators of the given quiz.
+	GetCollaborators(ctx context.Context, ownerId, quizId string) ([]Collaborator, error)
+
+	// DeleteCollaborator revokes access of the given user to the given quiz,
+	// otherwise ErrNotFound is returned.
+	DeleteCollaborator(ctx context.Context, ownerId, quizId, uid string) error
+
+	// GetSharedQuiz returns how the given quiz was shared with the given user,
+	// otherwise ErrNotFound is returned.
+	GetSharedQuiz(ctx context.Context, uid, quizId string) (SharedQuiz, error)
+
+	// GetSharedQuizzes returns every quiz shared with the given user.
+	GetSharedQuizzes(ctx context.Context, uid string) ([]SharedQuiz, error)
+
+	// GetListed returns every public quiz of every user, along with its owner.
+	GetListed(ctx context.Context) ([]OwnedQuiz, error)
+
+	// UpdateReaction applies the given update to the reaction of the given user on the given quiz,
+	// then updates aggregates of the quiz accordingly. Empty reactions are removed.
+	UpdateReaction(ctx context.Context, uid, ownerId, quizId string, update func(reaction *Reaction)) error
+
+	// GetReactions returns every reaction of the given user.
+	GetReactions(ctx context.Context, uid string) ([]Reaction, error)
+
+	// InsertFork stores the given fork, with its questions, as a quiz of the given user and
+	// increments the fork count of its source at once. ErrNotFound is returned if the source doesn't exist.
+	InsertFork(ctx context.Context, uid string, fork Quiz) error
+
+	// UpsertFolder stores or updates the given folder of the given user.
+	UpsertFolder(ctx context.Context, ownerId string, folder Folder) error
+
+	// GetFolders returns every folder of the given user.
+	GetFolders(ctx context.Context, ownerId string) ([]Folder, error)
+
+	// DeleteFolder removes the given folder, otherwise ErrNotFound is returned.
+	// Quizzes and folders it holds are left untouched.
+	DeleteFolder(ctx context.Context, ownerId, folderId string) error
+
+	// UpsertCollection stores or updates the given collection of the given user.
+	UpsertCollection(ctx context.Context, ownerId string, collection Collection) error
+
+	// GetCollection returns the matching collection, otherwise ErrNotFound is returned.
+	GetCollection(ctx context.Context, ownerId, collectionId string) (Collection, error)
+
+	// GetCollections returns every collection of the given user.
+	GetCollections(ctx context.Context, ownerId string) ([]Collection, error)
+
+	// DeleteCollection removes the given collection, otherwise ErrNotFound is returned.
+	DeleteCollection(ctx context.Context, ownerId, collectionId string) error
+}
+
+// OwnedQuiz is a quiz along with the id of its owner.
+type OwnedQuiz struct {
+	OwnerId string
+	Quiz    Quiz
+}
+
+type ExecutionStatus string
+
+const (
+	ExecutionWaiting  ExecutionStatus = "waiting"
+	ExecutionStarted  ExecutionStatus = "started"
+	ExecutionFinished ExecutionStatus = "finished"
+)
+
+// Execution describe a single run of a quiz. Players join an execution through its code,
+// a quiz may have many executions running at the same time.
+type Execution struct {
+	Id        string          `firestore:"-" json:"id"`
+	QuizId    string          `firestore:"quizId" json:"quizId"`
+	OwnerId   string          `firestore:"ownerId" json:"-"`
+	Code      string          `firestore:"code" json:"code"`
+	Status    ExecutionStatus `firestore:"status" json:"status"`
+	Cursor    int             `firestore:"cursor" json:"cursor"`
+	CreatedAt time.Time       `firestore:"createdAt" json:"createdAt"`
+	EndedAt   *time.Time      `firestore:"endedAt" json:"endedAt,omitempty"`
+	// AskedAt is when each question was asked, by question id.
+	AskedAt map[string]time.Time `firestore:"askedAt" json:"askedAt,omitempty"`
+	// ResultsRecorded is only set by Store.AddStats, UpsertExecution keeps the stored value.
+	ResultsRecorded bool `firestore:"resultsRecorded" json:"-"`
+}
+
+// Ref returns the reference of this execution, as bound to its code.
+func (e *Execution) Ref() ExecutionRef {
+	return ExecutionRef{OwnerId: e.OwnerId, QuizId: e.QuizId, ExecutionId: e.Id}
+}
+
+// ExecutionRef locates an execution in our store.
+type ExecutionRef struct {
+	OwnerId     string
+	QuizId      string
+	ExecutionId string
+}
+
+func (r ExecutionRef) String() string {
+	return fmt.Sprintf("%s@%s@%s", r.OwnerId, r.QuizId, r.ExecutionId)
+}
+
+// ParseExecutionRef parse the given string generated by ExecutionRef.String.
+func ParseExecutionRef(str string) (ExecutionRef, error) {
+	ids := strings.Split(str, "@")
+	if len(ids) != 3 {
+		return ExecutionRef{}, ErrInvalidExecutionRef
+	}
+
+	return ExecutionRef{OwnerId: ids[0], QuizId: ids[1], ExecutionId: ids[2]}, nil
 }
 
 type QuizCodeResolver interface {
-	BindCode(ownerId string, quiz Quiz) error
-	UnbindCode(code string) error
-	GetQuiz(code string) (string, error)
-	IncrRoomPeople(roomId string) error
-	GetRoomPeople(roomId string) (int, error)
-	ResetRoomPeople(roomId string) error
+	// BindCode reserves the given code for the given execution, if the code is already
+	// bound, ErrCodeTaken is returned.
+	BindCode(ctx context.Context, code string, ref ExecutionRef) error
+	UnbindCode(ctx context.Context, code string) error
+	// Resolve returns the execution reference bound to the given code,
+	// otherwise ErrNotFound is returned.
+	Resolve(ctx context.Context, code string) (ExecutionRef, error)
+	IncrRoomPeople(ctx context.Context, executionId string) error
+	GetRoomPeople(ctx context.Context, executionId string) (int, error)
+	ResetRoomPeople(ctx context.Context, executionId string) error
 }
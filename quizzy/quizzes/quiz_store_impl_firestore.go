@@ -2,36 +2,48 @@ package quizzes
 
 import (
 	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
 	"context"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"quizzy.app/backend/quizzy/services"
 	"strings"
+	"time"
 )
 
 type quizFirestore struct {
-	client *firestore.Client
+	client  *firestore.Client
+	timeout time.Duration
 }
 
-func ConfigureStore(client *firestore.Client) Store {
-	return &quizFirestore{client: client}
+func ConfigureStore(client *firestore.Client, timeout time.Duration) Store {
+	return &quizFirestore{client: client, timeout: timeout}
 }
 
-func (fs *quizFirestore) Upsert(ownerId string, quiz Quiz) error {
+func (fs *quizFirestore) Upsert(ctx context.Context, ownerId string, quiz Quiz) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
 	_, err := fs.client.
 		Doc(strings.Join([]string{"users", ownerId, "quizzes", quiz.Id}, "/")).
-		Set(context.Background(), quiz)
+		Set(ctx, quiz)
 	return err
 }
 
-func (fs *quizFirestore) GetUnique(ownerId, uid string) (Quiz, error) {
+func (fs *quizFirestore) GetUnique(ctx context.Context, ownerId, uid string) (Quiz, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
 	doc, err := fs.client.
 		Doc(strings.Join([]string{"users", ownerId, "quizzes", uid}, "/")).
-		Get(context.Background())
-
-	if err != nil {
-		return Quiz{}, err
-	}
+		Get(ctx)
 
-	if !doc.Exists() {
+	// Missing documents are reported as a gRPC error, not as a snapshot.
+	if status.Code(err) == codes.NotFound {
 		return Quiz{}, ErrNotFound
+	} else if err != nil {
+		return Quiz{}, err
 	}
 
 	var quiz Quiz
@@ -40,8 +52,9 @@ func (fs *quizFirestore) GetUnique(ownerId, uid string) (Quiz, error) {
 	}
 
 	quiz.Id = doc.Ref.ID
+	quiz.normalize()
 
-	if qs, err2 := fs.getQuestions(ownerId, quiz.Id); err2 != nil {
+	if qs, err2 := fs.getQuestions(ctx, ownerId, quiz.Id); err2 != nil {
 		return quiz, err2
 	} else {
 		quiz.Questions = qs
@@ -50,10 +63,43 @@ func (fs *quizFirestore) GetUnique(ownerId, uid string) (Quiz, error) {
 	return quiz, nil
 }
 
-func (fs *quizFirestore) GetQuizzes(ownerId string) ([]Quiz, error) {
-	docsIter, err := fs.client.
+func (fs *quizFirestore) GetQuizzes(ctx context.Context, ownerId string) ([]Quiz, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	return fs.readQuizzes(ctx, ownerId, fs.client.Collection(strings.Join([]string{"users", ownerId, "quizzes"}, "/")).Query)
+}
+
+// GetQuizzesInFolder filters quizzes of the root folder once read, as quizzes stored before folders
+// existed don't hold any "folderId" field, which Firestore queries never match.
+func (fs *quizFirestore) GetQuizzesInFolder(ctx context.Context, ownerId, folderId string) ([]Quiz, error) {
+	if folderId == RootFolder {
+		all, err := fs.GetQuizzes(ctx, ownerId)
+		if err != nil {
+			return nil, err
+		}
+
+		arr := make([]Quiz, 0)
+		for _, quiz := range all {
+			if quiz.FolderId == RootFolder {
+				arr = append(arr, quiz)
+			}
+		}
+		return arr, nil
+	}
+
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	return fs.readQuizzes(ctx, ownerId, fs.client.
 		Collection(strings.Join([]string{"users", ownerId, "quizzes"}, "/")).
-		Documents(context.Background()).
+		Where("folderId", "==", folderId))
+}
+
+// readQuizzes returns quizzes of the given user matching the given query, along with their questions.
+func (fs *quizFirestore) readQuizzes(ctx context.Context, ownerId string, query firestore.Query) ([]Quiz, error) {
+	docsIter, err := query.
+		Documents(ctx).
 		GetAll()
 
 	if err != nil {
@@ -69,8 +115,9 @@ func (fs *quizFirestore) GetQuizzes(ownerId string) ([]Quiz, error) {
 		}
 
 		quiz.Id = doc.Ref.ID
+		quiz.normalize()
 
-		if questions, err3 := fs.getQuestions(ownerId, quiz.Id); err3 != nil {
+		if questions, err3 := fs.getQuestions(ctx, ownerId, quiz.Id); err3 != nil {
 			return nil, err3
 		} else {
 			quiz.Questions = questions
@@ -82,7 +129,51 @@ func (fs *quizFirestore) GetQuizzes(ownerId string) ([]Quiz, error) {
 	return arr, nil
 }
 
-func (fs *quizFirestore) Patch(ownerId, uid string, fields []FieldPatchOp) error {
+// GetListed queries public quizzes of every user through the "quizzes" collection group,
+// owners are found from document paths ("users/{ownerId}/quizzes/{quizId}").
+// It requires a collection group scoped index on "visibility", which Firestore doesn't create by default
+// (see firestore.indexes.json).
+func (fs *quizFirestore) GetListed(ctx context.Context) ([]OwnedQuiz, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	docs, err := fs.client.
+		CollectionGroup("quizzes").
+		Where("visibility", "==", string(VisibilityPublic)).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// Must always be initialized to avoid nil pointer.
+	arr := make([]OwnedQuiz, 0)
+	for _, doc := range docs {
+		var quiz Quiz
+		if err2 := doc.DataTo(&quiz); err2 != nil {
+			return nil, err2
+		}
+
+		quiz.Id = doc.Ref.ID
+		quiz.normalize()
+		ownerId := doc.Ref.Parent.Parent.ID
+
+		if questions, err3 := fs.getQuestions(ctx, ownerId, quiz.Id); err3 != nil {
+			return nil, err3
+		} else {
+			quiz.Questions = questions
+		}
+
+		arr = append(arr, OwnedQuiz{OwnerId: ownerId, Quiz: quiz})
+	}
+
+	return arr, nil
+}
+
+func (fs *quizFirestore) Patch(ctx context.Context, ownerId, uid string, fields []FieldPatchOp) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
 	var updates []firestore.Update
 	for _, op := range fields {
 		if op.Op != "replace" {
@@ -108,14 +199,14 @@ func (fs *quizFirestore) Patch(ownerId, uid string, fields []FieldPatchOp) error
 		Doc(ownerId).
 		Collection("quizzes").
 		Doc(uid).
-		Update(context.Background(), updates)
+		Update(ctx, updates)
 	return err
 }
 
-func (fs *quizFirestore) getQuestions(ownerId, quizId string) ([]Question, error) {
+func (fs *quizFirestore) getQuestions(ctx context.Context, ownerId, quizId string) ([]Question, error) {
 	docsIter, err := fs.client.
 		Collection(strings.Join([]string{"users", ownerId, "quizzes", quizId, "questions"}, "/")).
-		Documents(context.Background()).
+		Documents(ctx).
 		GetAll()
 
 	if err != nil {
@@ -132,7 +223,7 @@ func (fs *quizFirestore) getQuestions(ownerId, quizId string) ([]Question, error
 
 		question.Id = doc.Ref.ID
 
-		if answers, err3 := fs.getAnswers(ownerId, quizId, doc.Ref.ID); err3 != nil {
+		if answers, err3 := fs.getAnswers(ctx, ownerId, quizId, doc.Ref.ID); err3 != nil {
 			return nil, err3
 		} else {
 			question.Answers = answers
@@ -144,10 +235,10 @@ func (fs *quizFirestore) getQuestions(ownerId, quizId string) ([]Question, error
 	return arr, nil
 }
 
-func (fs *quizFirestore) getAnswers(ownerId, quizId, questionId string) ([]Answer, error) {
+func (fs *quizFirestore) getAnswers(ctx context.Context, ownerId, quizId, questionId string) ([]Answer, error) {
 	docsIter, err := fs.client.
 		Collection(strings.Join([]string{"users", ownerId, "quizzes", quizId, "questions", questionId, "answers"}, "/")).
-		Documents(context.Background()).
+		Documents(ctx).
 		GetAll()
 
 	if err != nil {
@@ -169,25 +260,26 @@ func (fs *quizFirestore) getAnswers(ownerId, quizId, questionId string) ([]Answe
 	return arr, nil
 }
 
-func (fs *quizFirestore) UpsertQuestion(ownerId, quizId string, question Question) error {
-	if err := fs.UpdateQuestion(ownerId, quizId, question); err != nil {
+func (fs *quizFirestore) UpsertQuestion(ctx context.Context, ownerId, quizId string, question Question) error {
+	if err := fs.UpdateQuestion(ctx, ownerId, quizId, question); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (fs *quizFirestore) GetUniqueQuestion(ownerId, quizId, questionId string) (Question, error) {
+func (fs *quizFirestore) GetUniqueQuestion(ctx context.Context, ownerId, quizId, questionId string) (Question, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
 	doc, err := fs.client.
 		Doc(strings.Join([]string{"users", ownerId, "quizzes", quizId, "questions", questionId}, "/")).
-		Get(context.Background())
+		Get(ctx)
 
-	if err != nil {
-		return Question{}, err
-	}
-
-	if !doc.Exists() {
+	if status.Code(err) == codes.NotFound {
 		return Question{}, ErrNotFound
+	} else if err != nil {
+		return Question{}, err
 	}
 
 	var question Question
@@ -199,10 +291,13 @@ func (fs *quizFirestore) GetUniqueQuestion(ownerId, quizId, questionId string) (
 	return question, nil
 }
 
-func (fs *quizFirestore) UpdateQuestion(ownerId, quizId string, question Question) error {
+func (fs *quizFirestore) UpdateQuestion(ctx context.Context, ownerId, quizId string, question Question) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
 	_, err := fs.client.
 		Doc(strings.Join([]string{"users", ownerId, "quizzes", quizId, "questions", question.Id}, "/")).
-		Set(context.Background(), question)
+		Set(ctx, question)
 
 	if err != nil {
 		return err
@@ -210,7 +305,7 @@ func (fs *quizFirestore) UpdateQuestion(ownerId, quizId string, question Questio
 
 	iter, err2 := fs.client.
 		Collection(strings.Join([]string{"users", ownerId, "quizzes", quizId, "questions", question.Id, "answers"}, "/")).
-		Documents(context.Background()).
+		Documents(ctx).
 		GetAll()
 
 	if err2 != nil {
@@ -218,7 +313,7 @@ func (fs *quizFirestore) UpdateQuestion(ownerId, quizId string, question Questio
 	}
 
 	for _, doc := range iter {
-		if _, err3 := doc.Ref.Delete(context.Background()); err3 != nil {
+		if _, err3 := doc.Ref.Delete(ctx); err3 != nil {
 			return err3
 		}
 	}
@@ -226,7 +321,7 @@ func (fs *quizFirestore) UpdateQuestion(ownerId, quizId string, question Questio
 	for _, answer := range question.Answers {
 		_, err4 := fs.client.
 			Doc(strings.Join([]string{"users", ownerId, "quizzes", quizId, "questions", question.Id, "answers", answer.Id}, "/")).
-			Set(context.Background(), answer)
+			Set(ctx, answer)
 
 		if err4 != nil {
 			return err4
@@ -235,3 +330,777 @@ func (fs *quizFirestore) UpdateQuestion(ownerId, quizId string, question Questio
 
 	return nil
 }
+
+// executionFields are written by UpsertExecution. The resultsRecorded flag isn't, as callers
+// may hold a copy of the execution read before its results were recorded.
+var executionFields = []firestore.FieldPath{
+	{"quizId"}, {"ownerId"}, {"code"}, {"status"}, {"cursor"}, {"createdAt"}, {"endedAt"}, {"askedAt"},
+}
+
+func (fs *quizFirestore) UpsertExecution(ctx context.Context, ownerId, quizId string, execution Execution) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	ref := ExecutionRef{OwnerId: ownerId, QuizId: quizId, ExecutionId: execution.Id}
+	_, err := fs.executionDoc(ref).Set(ctx, execution, firestore.Merge(executionFields...))
+	return err
+}
+
+func (fs *quizFirestore) GetExecution(ctx context.Context, ownerId, quizId, executionId string) (Execution, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	doc, err := fs.client.
+		Doc(strings.Join([]string{"users", ownerId, "quizzes", quizId, "executions", executionId}, "/")).
+		Get(ctx)
+
+	if status.Code(err) == codes.NotFound {
+		return Execution{}, ErrNotFound
+	} else if err != nil {
+		return Execution{}, err
+	}
+
+	var execution Execution
+	if err2 := doc.DataTo(&execution); err2 != nil {
+		return execution, err2
+	}
+
+	execution.Id = doc.Ref.ID
+	return execution, nil
+}
+
+func (fs *quizFirestore) GetExecutions(ctx context.Context, ownerId, quizId string) ([]Execution, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	docs, err := fs.client.
+		Collection(strings.Join([]string{"users", ownerId, "quizzes", quizId, "executions"}, "/")).
+		OrderBy("createdAt", firestore.Desc).
+		Documents(ctx).
+		GetAll()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Must always be initialized to avoid nil pointer.
+	arr := make([]Execution, 0)
+	for _, doc := range docs {
+		var execution Execution
+		if err2 := doc.DataTo(&execution); err2 != nil {
+			return nil, err2
+		}
+
+		execution.Id = doc.Ref.ID
+		arr = append(arr, execution)
+	}
+
+	return arr, nil
+}
+
+// deleteBatchSize bounds documents listed, then deleted, at once when removing user data.
+const deleteBatchSize = 200
+
+// DeleteAll removes data of the given user batch by batch, each bounded by the store timeout
+// rather than the whole removal: children are removed before their parent, so a failed removal
+// leaves every remaining document reachable and is resumed by calling it again.
+func (fs *quizFirestore) DeleteAll(ctx context.Context, ownerId string) error {
+	// Participations of the user in executions of other owners, still holding its uid and nickname.
+	if err := fs.deleteQuery(ctx, fs.client.CollectionGroup("answers").Where("playerId", "==", ownerId), fs.participantOfAnswer); err != nil {
+		return err
+	}
+	if err := fs.deleteQuery(ctx, fs.client.CollectionGroup("participants").Where("uid", "==", ownerId), nil); err != nil {
+		return err
+	}
+
+	for _, col := range []string{"quizzes", "folders", "collections", "history"} {
+		if err := fs.deleteCollection(ctx, fs.client.Collection(strings.Join([]string{"users", ownerId, col}, "/"))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// participantOfAnswer returns the participant who gave the given answer, participants joined before
+// being stored along with their uid are only found this way.
+func (fs *quizFirestore) participantOfAnswer(answer *firestore.DocumentSnapshot) *firestore.DocumentRef {
+	var a PlayerAnswer
+	if err := answer.DataTo(&a); err != nil || a.PlayerId == "" {
+		return nil
+	}
+
+	return answer.Ref.Parent.Parent.Collection("participants").Doc(a.PlayerId)
+}
+
+// deleteQuery removes every document matching the given query, which mustn't match documents holding
+// nested collections. Documents related to each matching one (if any) are removed along with it.
+func (fs *quizFirestore) deleteQuery(ctx context.Context, query firestore.Query, related func(*firestore.DocumentSnapshot) *firestore.DocumentRef) error {
+	for {
+		listCtx, cancel := services.WithTimeout(ctx, fs.timeout)
+		docs, err := query.Limit(deleteBatchSize).Documents(listCtx).GetAll()
+		cancel()
+		if err != nil {
+			return err
+		} else if len(docs) == 0 {
+			return nil
+		}
+
+		// Related documents go first, they would be missed by a retry once the matching one is gone.
+		// A same document may be related to several matching ones, it must only be deleted once.
+		refs := make([]*firestore.DocumentRef, 0, len(docs))
+		seen := make(map[string]bool)
+		for _, doc := range docs {
+			if related == nil {
+				break
+			} else if ref := related(doc); ref != nil && !seen[ref.Path] {
+				seen[ref.Path] = true
+				refs = append(refs, ref)
+			}
+		}
+
+		if err2 := fs.deleteDocs(ctx, refs); err2 != nil {
+			return err2
+		}
+
+		refs = refs[:0]
+		for _, doc := range docs {
+			refs = append(refs, doc.Ref)
+		}
+
+		if err2 := fs.deleteDocs(ctx, refs); err2 != nil {
+			return err2
+		}
+	}
+}
+
+// deleteCollection removes every document of the given collection, along with their nested
+// collections (questions, answers, executions, participants), a page at a time.
+func (fs *quizFirestore) deleteCollection(ctx context.Context, col *firestore.CollectionRef) error {
+	for {
+		// DocumentRefs also lists missing documents which only hold nested collections.
+		refs := make([]*firestore.DocumentRef, 0, deleteBatchSize)
+		listCtx, cancel := services.WithTimeout(ctx, fs.timeout)
+		_, err := iterator.NewPager(col.DocumentRefs(listCtx), deleteBatchSize, "").NextPage(&refs)
+		cancel()
+		if err != nil {
+			return err
+		} else if len(refs) == 0 {
+			return nil
+		}
+
+		for _, ref := range refs {
+			listCtx, cancel = services.WithTimeout(ctx, fs.timeout)
+			cols, err2 := ref.Collections(listCtx).GetAll()
+			cancel()
+			if err2 != nil {
+				return err2
+			}
+
+			for _, nested := range cols {
+				if err3 := fs.deleteCollection(ctx, nested); err3 != nil {
+					return err3
+				}
+			}
+		}
+
+		if err2 := fs.deleteDocs(ctx, refs); err2 != nil {
+			return err2
+		}
+	}
+}
+
+// deleteDocs removes the given documents at once, documents which are already gone are ignored.
+func (fs *quizFirestore) deleteDocs(ctx context.Context, refs []*firestore.DocumentRef) error {
+	if len(refs) == 0 {
+		return nil
+	}
+
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	bw := fs.client.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, 0, len(refs))
+	for _, ref := range refs {
+		job, err := bw.Delete(ref)
+		if err != nil {
+			bw.End()
+			return err
+		}
+		jobs = append(jobs, job)
+	}
+	bw.End()
+
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (fs *quizFirestore) collaboratorRef(ownerId, quizId, uid string) *firestore.DocumentRef {
+	return fs.client.Doc(strings.Join([]string{"users", ownerId, "quizzes", quizId, "collaborators", uid}, "/"))
+}
+
+func (fs *quizFirestore) sharedRef(uid, quizId string) *firestore.DocumentRef {
+	return fs.client.Doc(strings.Join([]string{"users", uid, "shared", quizId}, "/"))
+}
+
+func (fs *quizFirestore) UpsertCollaborator(ctx context.Context, ownerId, quizId string, collaborator Collaborator) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	return fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := tx.Set(fs.collaboratorRef(ownerId, quizId, collaborator.Uid), collaborator); err != nil {
+			return err
+		}
+
+		return tx.Set(fs.sharedRef(collaborator.Uid, quizId), SharedQuiz{OwnerId: ownerId, Role: collaborator.Role})
+	})
+}
+
+func (fs *quizFirestore) GetCollaborators(ctx context.Context, ownerId, quizId string) ([]Collaborator, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	docs, err := fs.client.
+		Collection(strings.Join([]string{"users", ownerId, "quizzes", quizId, "collaborators"}, "/")).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// Must always be initialized to avoid nil pointer.
+	arr := make([]Collaborator, 0)
+	for _, doc := range docs {
+		var collaborator Collaborator
+		if err2 := doc.DataTo(&collaborator); err2 != nil {
+			return nil, err2
+		}
+
+		collaborator.Uid = doc.Ref.ID
+		arr = append(arr, collaborator)
+	}
+
+	return arr, nil
+}
+
+func (fs *quizFirestore) DeleteCollaborator(ctx context.Context, ownerId, quizId, uid string) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	ref := fs.collaboratorRef(ownerId, quizId, uid)
+	return fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if _, err := tx.Get(ref); status.Code(err) == codes.NotFound {
+			return ErrNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if err := tx.Delete(ref); err != nil {
+			return err
+		}
+
+		return tx.Delete(fs.sharedRef(uid, quizId))
+	})
+}
+
+func (fs *quizFirestore) GetSharedQuiz(ctx context.Context, uid, quizId string) (SharedQuiz, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	doc, err := fs.sharedRef(uid, quizId).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return SharedQuiz{}, ErrNotFound
+	} else if err != nil {
+		return SharedQuiz{}, err
+	}
+
+	var shared SharedQuiz
+	if err2 := doc.DataTo(&shared); err2 != nil {
+		return shared, err2
+	}
+
+	shared.QuizId = doc.Ref.ID
+	return shared, nil
+}
+
+func (fs *quizFirestore) GetSharedQuizzes(ctx context.Context, uid string) ([]SharedQuiz, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	docs, err := fs.client.
+		Collection(strings.Join([]string{"users", uid, "shared"}, "/")).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// Must always be initialized to avoid nil pointer.
+	arr := make([]SharedQuiz, 0)
+	for _, doc := range docs {
+		var shared SharedQuiz
+		if err2 := doc.DataTo(&shared); err2 != nil {
+			return nil, err2
+		}
+
+		shared.QuizId = doc.Ref.ID
+		arr = append(arr, shared)
+	}
+
+	return arr, nil
+}
+
+func (fs *quizFirestore) reactionRef(uid, quizId string) *firestore.DocumentRef {
+	return fs.client.Doc(strings.Join([]string{"users", uid, "reactions", quizId}, "/"))
+}
+
+// UpdateReaction reads the current reaction and the quiz in a transaction, so concurrent
+// reactions never lose an increment. Reactions to removed quizzes are still updated.
+func (fs *quizFirestore) UpdateReaction(ctx context.Context, uid, ownerId, quizId string, update func(reaction *Reaction)) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	reactionRef := fs.reactionRef(uid, quizId)
+	quizRef := fs.client.Doc(strings.Join([]string{"users", ownerId, "quizzes", quizId}, "/"))
+	return fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		old := Reaction{QuizId: quizId, OwnerId: ownerId}
+		if doc, err := tx.Get(reactionRef); err == nil {
+			if err2 := doc.DataTo(&old); err2 != nil {
+				return err2
+			}
+		} else if status.Code(err) != codes.NotFound {
+			return err
+		}
+
+		quizExists := true
+		if _, err := tx.Get(quizRef); status.Code(err) == codes.NotFound {
+			quizExists = false
+		} else if err != nil {
+			return err
+		}
+
+		reaction := old
+		update(&reaction)
+		reaction.UpdatedAt = time.Now().UTC()
+
+		if reaction.IsEmpty() {
+			if err := tx.Delete(reactionRef); err != nil {
+				return err
+			}
+		} else if err := tx.Set(reactionRef, reaction); err != nil {
+			return err
+		}
+
+		delta := DeltaOf(old, reaction)
+		if !quizExists || delta.IsZero() {
+			return nil
+		}
+
+		return tx.Update(quizRef, []firestore.Update{
+			{Path: "stars", Value: firestore.Increment(delta.Stars)},
+			{Path: "ratingCount", Value: firestore.Increment(delta.RatingCount)},
+			{Path: "ratingSum", Value: firestore.Increment(delta.RatingSum)},
+		})
+	})
+}
+
+func (fs *quizFirestore) GetReactions(ctx context.Context, uid string) ([]Reaction, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	docs, err := fs.client.
+		Collection(strings.Join([]string{"users", uid, "reactions"}, "/")).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// Must always be initialized to avoid nil pointer.
+	arr := make([]Reaction, 0)
+	for _, doc := range docs {
+		var reaction Reaction
+		if err2 := doc.DataTo(&reaction); err2 != nil {
+			return nil, err2
+		}
+
+		reaction.QuizId = doc.Ref.ID
+		arr = append(arr, reaction)
+	}
+
+	return arr, nil
+}
+
+func (fs *quizFirestore) executionDoc(ref ExecutionRef) *firestore.DocumentRef {
+	return fs.client.Doc(strings.Join([]string{"users", ref.OwnerId, "quizzes", ref.QuizId, "executions", ref.ExecutionId}, "/"))
+}
+
+func (fs *quizFirestore) AddParticipant(ctx context.Context, ref ExecutionRef, participant Participant) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	_, err := fs.executionDoc(ref).Collection("participants").Doc(participant.Id).Create(ctx, participant)
+	if status.Code(err) == codes.AlreadyExists {
+		return nil
+	}
+	return err
+}
+
+func (fs *quizFirestore) GetParticipants(ctx context.Context, ref ExecutionRef) ([]Participant, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	docs, err := fs.executionDoc(ref).Collection("participants").Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// Must always be initialized to avoid nil pointer.
+	arr := make([]Participant, 0)
+	for _, doc := range docs {
+		var participant Participant
+		if err2 := doc.DataTo(&participant); err2 != nil {
+			return nil, err2
+		}
+
+		participant.Id = doc.Ref.ID
+		arr = append(arr, participant)
+	}
+
+	return arr, nil
+}
+
+func (fs *quizFirestore) AddAnswer(ctx context.Context, ref ExecutionRef, answer PlayerAnswer) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	// Creating the document fails if it exists, so concurrent answers can't replace the first one.
+	_, err := fs.executionDoc(ref).
+		Collection("answers").
+		Doc(answer.QuestionId+"_"+answer.PlayerId).
+		Create(ctx, answer)
+	if status.Code(err) == codes.AlreadyExists {
+		return ErrAlreadyAnswered
+	}
+	return err
+}
+
+func (fs *quizFirestore) GetAnswers(ctx context.Context, ref ExecutionRef) ([]PlayerAnswer, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	docs, err := fs.executionDoc(ref).Collection("answers").Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// Must always be initialized to avoid nil pointer.
+	arr := make([]PlayerAnswer, 0)
+	for _, doc := range docs {
+		var answer PlayerAnswer
+		if err2 := doc.DataTo(&answer); err2 != nil {
+			return nil, err2
+		}
+
+		arr = append(arr, answer)
+	}
+
+	return arr, nil
+}
+
+func (fs *quizFirestore) statsDoc(ownerId, quizId string) *firestore.DocumentRef {
+	return fs.client.Doc(strings.Join([]string{"users", ownerId, "quizzes", quizId, "stats", "totals"}, "/"))
+}
+
+func (fs *quizFirestore) AddStats(ctx context.Context, ref ExecutionRef, delta QuizStats) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	buckets := make(map[string]any, len(delta.ScoreBuckets))
+	for bucket, count := range delta.ScoreBuckets {
+		buckets[bucket] = firestore.Increment(count)
+	}
+
+	questions := make(map[string]any, len(delta.Questions))
+	for id, totals := range delta.Questions {
+		questions[id] = map[string]any{
+			"participants": firestore.Increment(totals.Participants),
+			"answered":     firestore.Increment(totals.Answered),
+			"correct":      firestore.Increment(totals.Correct),
+		}
+	}
+
+	// Increments are applied server side, concurrent executions ending never lose updates.
+	data := map[string]any{
+		"sessions":     firestore.Increment(delta.Sessions),
+		"players":      firestore.Increment(delta.Players),
+		"scoreSum":     firestore.Increment(delta.ScoreSum),
+		"scoreRateSum": firestore.Increment(delta.ScoreRateSum),
+		"durationSum":  firestore.Increment(delta.DurationSum),
+	}
+	// Merging an empty map would replace the stored one.
+	if len(buckets) > 0 {
+		data["scoreBuckets"] = buckets
+	}
+	if len(questions) > 0 {
+		data["questions"] = questions
+	}
+
+	execDoc := fs.executionDoc(ref)
+	return fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(execDoc)
+		if status.Code(err) == codes.NotFound {
+			return ErrNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if recorded, err2 := doc.DataAt("resultsRecorded"); err2 == nil && recorded == true {
+			return nil
+		}
+
+		if err3 := tx.Set(fs.statsDoc(ref.OwnerId, ref.QuizId), data, firestore.MergeAll); err3 != nil {
+			return err3
+		}
+
+		return tx.Update(execDoc, []firestore.Update{{Path: "resultsRecorded", Value: true}})
+	})
+}
+
+func (fs *quizFirestore) GetStats(ctx context.Context, ownerId, quizId string) (QuizStats, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	doc, err := fs.statsDoc(ownerId, quizId).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return QuizStats{}, nil
+	} else if err != nil {
+		return QuizStats{}, err
+	}
+
+	var stats QuizStats
+	if err2 := doc.DataTo(&stats); err2 != nil {
+		return QuizStats{}, err2
+	}
+
+	return stats, nil
+}
+
+func (fs *quizFirestore) UpsertHistory(ctx context.Context, uid string, entry HistoryEntry) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	_, err := fs.client.
+		Doc(strings.Join([]string{"users", uid, "history", entry.ExecutionId}, "/")).
+		Set(ctx, entry)
+	return err
+}
+
+func (fs *quizFirestore) GetHistory(ctx context.Context, uid string, offset, limit int) ([]HistoryEntry, int, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	col := fs.client.Collection(strings.Join([]string{"users", uid, "history"}, "/"))
+
+	// Counting is billed as a single read per thousand entries, pages don't need to be all read.
+	counts, err := col.NewAggregationQuery().WithCount("total").Get(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := 0
+	if v, ok := counts["total"].(*firestorepb.Value); ok {
+		total = int(v.GetIntegerValue())
+	}
+
+	docs, err := col.
+		OrderBy("playedAt", firestore.Desc).
+		Offset(offset).
+		Limit(limit).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Must always be initialized to avoid nil pointer.
+	arr := make([]HistoryEntry, 0)
+	for _, doc := range docs {
+		var entry HistoryEntry
+		if err2 := doc.DataTo(&entry); err2 != nil {
+			return nil, 0, err2
+		}
+
+		entry.ExecutionId = doc.Ref.ID
+		arr = append(arr, entry)
+	}
+
+	return arr, total, nil
+}
+
+func (fs *quizFirestore) InsertFork(ctx context.Context, uid string, fork Quiz) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	source := fs.client.Doc(strings.Join([]string{"users", fork.ForkedFrom.OwnerId, "quizzes", fork.ForkedFrom.QuizId}, "/"))
+	quizDoc := fs.client.Doc(strings.Join([]string{"users", uid, "quizzes", fork.Id}, "/"))
+
+	// Either the whole copy is stored and counted, or nothing is.
+	return fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if _, err := tx.Get(source); status.Code(err) == codes.NotFound {
+			return ErrNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if err2 := tx.Create(quizDoc, fork); err2 != nil {
+			return err2
+		}
+
+		for _, question := range fork.Questions {
+			questionDoc := quizDoc.Collection("questions").Doc(question.Id)
+			if err3 := tx.Set(questionDoc, question); err3 != nil {
+				return err3
+			}
+
+			for _, answer := range question.Answers {
+				if err4 := tx.Set(questionDoc.Collection("answers").Doc(answer.Id), answer); err4 != nil {
+					return err4
+				}
+			}
+		}
+
+		return tx.Update(source, []firestore.Update{{Path: "forks", Value: firestore.Increment(1)}})
+	})
+}
+
+func (fs *quizFirestore) UpsertFolder(ctx context.Context, ownerId string, folder Folder) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	_, err := fs.client.
+		Doc(strings.Join([]string{"users", ownerId, "folders", folder.Id}, "/")).
+		Set(ctx, folder)
+	return err
+}
+
+func (fs *quizFirestore) GetFolders(ctx context.Context, ownerId string) ([]Folder, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	docs, err := fs.client.
+		Collection(strings.Join([]string{"users", ownerId, "folders"}, "/")).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// Must always be initialized to avoid nil pointer.
+	arr := make([]Folder, 0)
+	for _, doc := range docs {
+		var folder Folder
+		if err2 := doc.DataTo(&folder); err2 != nil {
+			return nil, err2
+		}
+
+		folder.Id = doc.Ref.ID
+		arr = append(arr, folder)
+	}
+
+	return arr, nil
+}
+
+// deleteDocument removes the given document in a transaction, ErrNotFound is returned if it doesn't exist.
+func (fs *quizFirestore) deleteDocument(ctx context.Context, ref *firestore.DocumentRef) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	return fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if _, err := tx.Get(ref); status.Code(err) == codes.NotFound {
+			return ErrNotFound
+		} else if err != nil {
+			return err
+		}
+
+		return tx.Delete(ref)
+	})
+}
+
+func (fs *quizFirestore) DeleteFolder(ctx context.Context, ownerId, folderId string) error {
+	return fs.deleteDocument(ctx, fs.client.Doc(strings.Join([]string{"users", ownerId, "folders", folderId}, "/")))
+}
+
+func (fs *quizFirestore) UpsertCollection(ctx context.Context, ownerId string, collection Collection) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	_, err := fs.client.
+		Doc(strings.Join([]string{"users", ownerId, "collections", collection.Id}, "/")).
+		Set(ctx, collection)
+	return err
+}
+
+func (fs *quizFirestore) GetCollection(ctx context.Context, ownerId, collectionId string) (Collection, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	doc, err := fs.client.
+		Doc(strings.Join([]string{"users", ownerId, "collections", collectionId}, "/")).
+		Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return Collection{}, ErrNotFound
+	} else if err != nil {
+		return Collection{}, err
+	}
+
+	var collection Collection
+	if err2 := doc.DataTo(&collection); err2 != nil {
+		return collection, err2
+	}
+
+	collection.Id = doc.Ref.ID
+	if collection.QuizIds == nil {
+		collection.QuizIds = make([]string, 0)
+	}
+	return collection, nil
+}
+
+func (fs *quizFirestore) GetCollections(ctx context.Context, ownerId string) ([]Collection, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	docs, err := fs.client.
+		Collection(strings.Join([]string{"users", ownerId, "collections"}, "/")).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// Must always be initialized to avoid nil pointer.
+	arr := make([]Collection, 0)
+	for _, doc := range docs {
+		var collection Collection
+		if err2 := doc.DataTo(&collection); err2 != nil {
+			return nil, err2
+		}
+
+		collection.Id = doc.Ref.ID
+		if collection.QuizIds == nil {
+			collection.QuizIds = make([]string, 0)
+		}
+		arr = append(arr, collection)
+	}
+
+	return arr, nil
+}
+
+func (fs *quizFirestore) DeleteCollection(ctx context.Context, ownerId, collectionId string) error {
+	return fs.deleteDocument(ctx, fs.client.Doc(strings.Join([]string{"users", ownerId, "collections", collectionId}, "/")))
+}
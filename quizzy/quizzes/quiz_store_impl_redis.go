@@ -3,40 +3,326 @@ package quizzes
 import (
 	"context"
 	"errors"
-	"fmt"
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"quizzy.app/backend/quizzy/cfg"
+	"quizzy.app/backend/quizzy/services"
+	"strings"
+	"time"
 )
 
+// RedisKeys describe Quizzy keyspace in Redis. Every key is namespaced under a common
+// prefix, so Quizzy can share a Redis instance with other applications:
+//
+//	<prefix>:code:<code>          -> execution reference bound to the code
+//	<prefix>:room:<execution-id>  -> number of participants in the execution room
+//	<prefix>:auth:<token-hash>    -> verified identity (see auth.RedisTokenCache)
+type RedisKeys struct {
+	Prefix string
+}
+
+func (k RedisKeys) Code(code string) string {
+	return strings.Join([]string{k.Prefix, "code", code}, ":")
+}
+
+func (k RedisKeys) Room(executionId string) string {
+	return strings.Join([]string{k.Prefix, "room", executionId}, ":")
+}
+
+// Pattern returns the SCAN pattern matching every key of the given kind ("code", "room").
+func (k RedisKeys) Pattern(kind string) string {
+	return strings.Join([]string{k.Prefix, kind, "*"}, ":")
+}
+
 type RedisCodeResolver struct {
-	client *redis.Client
+	client  *redis.Client
+	keys    RedisKeys
+	timeout time.Duration
+	// ttl is the lifetime of a bound code, an execution not ended
+	// before this delay will have its code released anyway.
+	ttl time.Duration
 }
 
-func (re *RedisCodeResolver) BindCode(ownerId string, quiz Quiz) error {
-	return re.client.Set(context.Background(), quiz.Code, fmt.Sprintf("%store@%store", ownerId, quiz.Id), 0).Err()
+func NewRedisCodeResolver(client *redis.Client, conf cfg.AppConfig) *RedisCodeResolver {
+	return &RedisCodeResolver{
+		client:  client,
+		keys:    RedisKeys{Prefix: conf.RedisKeyPrefix},
+		timeout: conf.Timeouts.Redis,
+		ttl:     conf.CodeTTL,
+	}
 }
 
-func (re *RedisCodeResolver) UnbindCode(code string) error {
-	return re.client.Del(context.Background(), code).Err()
+// BindCode atomically reserves the given code (SET NX), if the code is already bound
+// to any execution, ErrCodeTaken is returned and the existing binding is left untouched.
+func (re *RedisCodeResolver) BindCode(ctx context.Context, code string, ref ExecutionRef) error {
+	ctx, cancel := services.WithTimeout(ctx, re.timeout)
+	defer cancel()
+
+	ok, err := re.client.SetNX(ctx, re.keys.Code(code), ref.String(), re.ttl).Result()
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrCodeTaken
+	}
+
+	return nil
 }
 
-func (re *RedisCodeResolver) GetQuiz(code string) (string, error) {
-	return re.client.Get(context.Background(), code).Result()
+func (re *RedisCodeResolver) UnbindCode(ctx context.Context, code string) error {
+	ctx, cancel := services.WithTimeout(ctx, re.timeout)
+	defer cancel()
+
+	return re.client.Del(ctx, re.keys.Code(code)).Err()
 }
 
-func (re *RedisCodeResolver) IncrRoomPeople(roomId string) error {
-	key := fmt.Sprintf("room:%s", roomId)
-	
-	if err := re.client.Incr(context.Background(), key).Err(); errors.Is(err, redis.Nil) {
-		return re.client.Set(context.Background(), key, 1, 0).Err()
+func (re *RedisCodeResolver) Resolve(ctx context.Context, code string) (ExecutionRef, error) {
+	ctx, cancel := services.WithTimeout(ctx, re.timeout)
+	defer cancel()
+
+	if v, err := re.client.Get(ctx, re.keys.Code(NormalizeCode(code))).Result(); errors.Is(err, redis.Nil) {
+		return ExecutionRef{}, ErrNotFound
+	} else if err != nil {
+		return ExecutionRef{}, err
+	} else {
+		return ParseExecutionRef(v)
+	}
+}
+
+func (re *RedisCodeResolver) IncrRoomPeople(ctx context.Context, executionId string) error {
+	ctx, cancel := services.WithTimeout(ctx, re.timeout)
+	defer cancel()
+
+	key := re.keys.Room(executionId)
+
+	if err := re.client.Incr(ctx, key).Err(); errors.Is(err, redis.Nil) {
+		return re.client.Set(ctx, key, 1, 0).Err()
 	} else {
 		return err
 	}
 }
 
-func (re *RedisCodeResolver) GetRoomPeople(roomId string) (int, error) {
-	return re.client.Get(context.Background(), fmt.Sprintf("room:%s", roomId)).Int()
+func (re *RedisCodeResolver) GetRoomPeople(ctx context.Context, executionId string) (int, error) {
+	ctx, cancel := services.WithTimeout(ctx, re.timeout)
+	defer cancel()
+
+	return re.client.Get(ctx, re.keys.Room(executionId)).Int()
+}
+
+func (re *RedisCodeResolver) ResetRoomPeople(ctx context.Context, executionId string) error {
+	ctx, cancel := services.WithTimeout(ctx, re.timeout)
+	defer cancel()
+
+	return re.client.Del(ctx, re.keys.Room(executionId)).Err()
+}
+
+// ActiveCode is a code currently bound in Redis.
+type ActiveCode struct {
+	Code string        `json:"code"`
+	Ref  ExecutionRef  `json:"ref"`
+	TTL  time.Duration `json:"ttl" swaggertype:"integer"`
+}
+
+// ActiveRoom is an execution room currently tracked in Redis.
+type ActiveRoom struct {
+	ExecutionId  string `json:"executionId"`
+	Participants int    `json:"participants"`
+}
+
+// Keyspace lists every Quizzy entry found in Redis.
+type Keyspace struct {
+	Codes []ActiveCode `json:"codes"`
+	Rooms []ActiveRoom `json:"rooms"`
+}
+
+// scan iterates (SCAN) over every key matching the given pattern. Unlike KEYS, SCAN
+// doesn't block the Redis instance, so it can safely be used against production.
+func (re *RedisCodeResolver) scan(ctx context.Context, pattern string, fn func(key string) error) error {
+	iter := re.client.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		if err := fn(iter.Val()); err != nil {
+			return err
+		}
+	}
+
+	return iter.Err()
+}
+
+// Keyspace returns active codes and rooms, as found under the configured prefix.
+func (re *RedisCodeResolver) Keyspace(ctx context.Context) (Keyspace, error) {
+	ks := Keyspace{Codes: make([]ActiveCode, 0), Rooms: make([]ActiveRoom, 0)}
+
+	codePrefix := re.keys.Code("")
+	err := re.scan(ctx, re.keys.Pattern("code"), func(key string) error {
+		v, err := re.client.Get(ctx, key).Result()
+		if errors.Is(err, redis.Nil) {
+			// Expired between SCAN and GET.
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		ref, _ := ParseExecutionRef(v)
+		ttl, _ := re.client.TTL(ctx, key).Result()
+		ks.Codes = append(ks.Codes, ActiveCode{Code: strings.TrimPrefix(key, codePrefix), Ref: ref, TTL: ttl})
+		return nil
+	})
+	if err != nil {
+		return ks, err
+	}
+
+	roomPrefix := re.keys.Room("")
+	err = re.scan(ctx, re.keys.Pattern("room"), func(key string) error {
+		n, err := re.client.Get(ctx, key).Int()
+		if errors.Is(err, redis.Nil) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		ks.Rooms = append(ks.Rooms, ActiveRoom{ExecutionId: strings.TrimPrefix(key, roomPrefix), Participants: n})
+		return nil
+	})
+
+	return ks, err
 }
 
-func (re *RedisCodeResolver) ResetRoomPeople(roomId string) error {
-	return re.client.Del(context.Background(), fmt.Sprintf("room:%s", roomId)).Err()
+// legacyRefSuffix was appended to both ids of references bound to codes before executions existed,
+// by a "%store@%store" format ("%s" followed by "tore").
+const legacyRefSuffix = "tore"
+
+// parseLegacyQuizRef parses a reference bound to a code before executions existed, as written by
+// the original resolver: "<ownerId>tore@<quizId>tore".
+func parseLegacyQuizRef(str string) (ownerId, quizId string, ok bool) {
+	ids := strings.Split(str, "@")
+	if len(ids) != 2 || !strings.HasSuffix(ids[0], legacyRefSuffix) || !strings.HasSuffix(ids[1], legacyRefSuffix) {
+		return "", "", false
+	}
+
+	ownerId, quizId = strings.TrimSuffix(ids[0], legacyRefSuffix), strings.TrimSuffix(ids[1], legacyRefSuffix)
+	return ownerId, quizId, len(ownerId) > 0 && len(quizId) > 0
+}
+
+// MigrateLegacyKeys moves keys written before the keyspace was namespaced (bare "<code>" and
+// "room:<id>" keys) under the configured prefix. A key already existing under the prefix is
+// never overwritten. Only bare keys that look like an execution code are considered, others
+// belong to someone else:
+//   - codes bound to an execution reference are renamed, so their TTL is kept;
+//   - codes bound to a quiz, before executions existed, get a new waiting execution of this quiz
+//     (created in the given store) and are bound to it, codes of removed quizzes are left as is;
+//   - rooms are only renamed if their id matches an execution bound to a code, or a code of
+//     a quiz (rooms were named after codes before executions existed).
+//
+// It returns the number of migrated keys.
+func (re *RedisCodeResolver) MigrateLegacyKeys(ctx context.Context, store Store) (int, error) {
+	migrated := 0
+	// Execution ids, by legacy room id.
+	rooms := make(map[string]string)
+
+	err := re.scan(ctx, re.keys.Pattern("code"), func(key string) error {
+		// Codes migrated by a previous run.
+		if v, err := re.client.Get(ctx, key).Result(); err == nil {
+			if ref, err2 := ParseExecutionRef(v); err2 == nil {
+				rooms[ref.ExecutionId] = ref.ExecutionId
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return migrated, err
+	}
+
+	err = re.scan(ctx, strings.Repeat("?", CodeLength), func(key string) error {
+		if key != NormalizeCode(key) {
+			return nil
+		}
+
+		v, err := re.client.Get(ctx, key).Result()
+		if err != nil {
+			// Not a string, or already gone: not ours.
+			return nil
+		}
+
+		if ref, err2 := ParseExecutionRef(v); err2 == nil {
+			rooms[ref.ExecutionId] = ref.ExecutionId
+			if ok, err3 := re.client.RenameNX(ctx, key, re.keys.Code(key)).Result(); err3 != nil {
+				return err3
+			} else if ok {
+				migrated++
+			}
+			return nil
+		}
+
+		ownerId, quizId, ok := parseLegacyQuizRef(v)
+		if !ok {
+			return nil
+		}
+
+		ref, err4 := re.migrateLegacyCode(ctx, store, key, ownerId, quizId)
+		if err4 != nil {
+			return err4
+		} else if len(ref.ExecutionId) > 0 {
+			rooms[key] = ref.ExecutionId
+			migrated++
+		}
+		return nil
+	})
+	if err != nil {
+		return migrated, err
+	}
+
+	err = re.scan(ctx, "room:*", func(key string) error {
+		executionId, ok := rooms[strings.TrimPrefix(key, "room:")]
+		if !ok {
+			return nil
+		}
+
+		if ok2, err2 := re.client.RenameNX(ctx, key, re.keys.Room(executionId)).Result(); err2 != nil {
+			return err2
+		} else if ok2 {
+			migrated++
+		}
+		return nil
+	})
+
+	return migrated, err
+}
+
+// migrateLegacyCode binds the given legacy code of a quiz to a new waiting execution of this quiz.
+// A zero reference is returned if the code wasn't migrated.
+func (re *RedisCodeResolver) migrateLegacyCode(ctx context.Context, store Store, code, ownerId, quizId string) (ExecutionRef, error) {
+	if n, err := re.client.Exists(ctx, re.keys.Code(code)).Result(); err != nil || n > 0 {
+		return ExecutionRef{}, err
+	}
+
+	if _, err := store.GetUnique(ctx, ownerId, quizId); errors.Is(err, ErrNotFound) {
+		return ExecutionRef{}, nil
+	} else if err != nil {
+		return ExecutionRef{}, err
+	}
+
+	execution := Execution{
+		Id:        uuid.New().String(),
+		QuizId:    quizId,
+		OwnerId:   ownerId,
+		Code:      code,
+		Status:    ExecutionWaiting,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := store.UpsertExecution(ctx, ownerId, quizId, execution); err != nil {
+		return ExecutionRef{}, err
+	}
+
+	// Legacy codes never expired, they get the lifetime of new ones.
+	ttl := re.ttl
+	if current, err := re.client.TTL(ctx, code).Result(); err == nil && current > 0 {
+		ttl = current
+	}
+
+	if ok, err := re.client.SetNX(ctx, re.keys.Code(code), execution.Ref().String(), ttl).Result(); err != nil || !ok {
+		return ExecutionRef{}, err
+	}
+
+	return execution.Ref(), re.client.Del(ctx, code).Err()
 }
@@ -0,0 +1,157 @@
+package quizzes
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrIndexNotReady = errors.New("library index not built yet")
+)
+
+// indexedStore decorates a Store, keeping the given SearchIndex up to date on every quiz write.
+// The store stays the source of truth, indexing failures are only logged.
+type indexedStore struct {
+	Store
+	index SearchIndex
+}
+
+func NewIndexedStore(store Store, index SearchIndex) Store {
+	return &indexedStore{Store: store, index: index}
+}
+
+// reindex reads the quiz back from the store, so the index always holds its stored state.
+func (s *indexedStore) reindex(ctx context.Context, ownerId, quizId string) {
+	quiz, err := s.Store.GetUnique(ctx, ownerId, quizId)
+	if errors.Is(err, ErrNotFound) {
+		err = s.index.Remove(ctx, ownerId, quizId)
+	} else if err == nil {
+		err = s.index.Index(ctx, ownerId, quiz)
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to index quiz", "quiz", quizId, "owner", ownerId, "error", err)
+	}
+}
+
+func (s *indexedStore) Upsert(ctx context.Context, ownerId string, quiz Quiz) error {
+	if err := s.Store.Upsert(ctx, ownerId, quiz); err != nil {
+		return err
+	}
+
+	s.reindex(ctx, ownerId, quiz.Id)
+	return nil
+}
+
+func (s *indexedStore) Patch(ctx context.Context, ownerId, uid string, fields []FieldPatchOp) error {
+	if err := s.Store.Patch(ctx, ownerId, uid, fields); err != nil {
+		return err
+	}
+
+	s.reindex(ctx, ownerId, uid)
+	return nil
+}
+
+func (s *indexedStore) UpsertQuestion(ctx context.Context, ownerId, quizId string, question Question) error {
+	if err := s.Store.UpsertQuestion(ctx, ownerId, quizId, question); err != nil {
+		return err
+	}
+
+	s.reindex(ctx, ownerId, quizId)
+	return nil
+}
+
+func (s *indexedStore) UpdateQuestion(ctx context.Context, ownerId, quizId string, question Question) error {
+	if err := s.Store.UpdateQuestion(ctx, ownerId, quizId, question); err != nil {
+		return err
+	}
+
+	s.reindex(ctx, ownerId, quizId)
+	return nil
+}
+
+func (s *indexedStore) UpdateReaction(ctx context.Context, uid, ownerId, quizId string, update func(reaction *Reaction)) error {
+	if err := s.Store.UpdateReaction(ctx, uid, ownerId, quizId, update); err != nil {
+		return err
+	}
+
+	s.reindex(ctx, ownerId, quizId)
+	return nil
+}
+
+// InsertFork only reindexes the source, forks are private.
+func (s *indexedStore) InsertFork(ctx context.Context, uid string, fork Quiz) error {
+	if err := s.Store.InsertFork(ctx, uid, fork); err != nil {
+		return err
+	}
+
+	s.reindex(ctx, fork.ForkedFrom.OwnerId, fork.ForkedFrom.QuizId)
+	return nil
+}
+
+func (s *indexedStore) DeleteAll(ctx context.Context, ownerId string) error {
+	if err := s.Store.DeleteAll(ctx, ownerId); err != nil {
+		return err
+	}
+
+	if err := s.index.RemoveOwner(ctx, ownerId); err != nil {
+		slog.ErrorContext(ctx, "failed to unindex quizzes", "owner", ownerId, "error", err)
+	}
+	return nil
+}
+
+// RebuildIndex replaces the content of the given index by every public quiz of the given store.
+func RebuildIndex(ctx context.Context, store Store, index SearchIndex) error {
+	listed, err := store.GetListed(ctx)
+	if err != nil {
+		return err
+	}
+
+	return index.Replace(ctx, listed)
+}
+
+// indexRetryDelay is how long IndexRebuilder waits before retrying a failed rebuild.
+const indexRetryDelay = 10 * time.Second
+
+// IndexRebuilder keeps an in-process index up to date. Writes of the local instance are indexed
+// as they happen (see NewIndexedStore), but writes of other instances are only caught up with by
+// rebuilding the whole index, on startup then every Interval. A write made on this instance
+// during a rebuild may be missing until the next one.
+type IndexRebuilder struct {
+	Store    Store
+	Index    SearchIndex
+	Interval time.Duration
+	ready    atomic.Bool
+}
+
+// Run rebuilds the index until the given context is done, it must be called on startup.
+func (r *IndexRebuilder) Run(ctx context.Context) {
+	for {
+		delay := r.Interval
+		if err := RebuildIndex(ctx, r.Store, r.Index); err != nil {
+			slog.ErrorContext(ctx, "failed to build the library index", "error", err)
+			delay = min(delay, indexRetryDelay)
+		} else {
+			r.ready.Store(true)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// Ready returns ErrIndexNotReady until the index was built once. Until then the library
+// looks empty, so instances shouldn't receive traffic.
+func (r *IndexRebuilder) Ready(ctx context.Context) error {
+	if !r.ready.Load() {
+		return ErrIndexNotReady
+	}
+
+	return nil
+}
@@ -1,61 +1,172 @@
 package quizzes
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"log/slog"
 	"net/http"
 	"quizzy.app/backend/quizzy/auth"
 	"quizzy.app/backend/quizzy/cfg"
 	"quizzy.app/backend/quizzy/services"
+	"strings"
+	"time"
 )
 
 type Controller struct {
 	Resolver QuizCodeResolver
 	Service  QuizService
+	Guests   *auth.GuestIssuer
+	// Users resolves users invited as collaborators.
+	Users UserResolver
+	// Indexer rebuilds the library index, instances aren't ready before its first build.
+	Indexer *IndexRebuilder
 }
 
-func Configure(fbs *services.FirebaseServices, rc *redis.Client, conf cfg.AppConfig) *Controller {
+func Configure(fbs *services.FirebaseServices, rc *redis.Client, conf cfg.AppConfig, guests *auth.GuestIssuer) *Controller {
+	resolver := NewRedisCodeResolver(rc, conf)
+	index := NewMemorySearchIndex()
+	store := NewIndexedStore(ConfigureStore(fbs.Store, conf.Timeouts.Firestore), index)
+
+	// The in-process index starts empty, public quizzes are indexed in the background.
+	indexer := &IndexRebuilder{Store: store, Index: index, Interval: conf.LibraryRebuildInterval}
+	go indexer.Run(context.Background())
+
 	return &Controller{
+		Resolver: resolver,
 		Service: &QuizServiceImpl{
-			store:    &quizFirestore{client: fbs.Store},
-			resolver: &RedisCodeResolver{client: rc},
+			store:    store,
+			resolver: resolver,
+			index:    index,
 		},
+		Guests:  guests,
+		Indexer: indexer,
 	}
 }
 
 func (qc *Controller) ConfigureRouting(rt *gin.RouterGroup) {
 	NewSocketController(qc.Service).Configure(rt)
-	
-	secured := rt.Group("/quiz", auth.RequireAuthenticated)
+
+	rt.POST("/execution/:code/guest", qc.handlePostGuest)
+	rt.GET("/library", qc.handleGetLibrary)
+
+	// Reactions to quizzes of the library, by any registered user.
+	library := rt.Group("/library/:quiz-id", auth.RequireAuthenticated,
+		auth.RequireScope(auth.ScopeQuizzesRead, auth.ScopeQuizzesWrite), qc.ProvideLibraryOwner)
+	library.PUT("/star", qc.handlePutStar)
+	library.DELETE("/star", qc.handleDeleteStar)
+	library.PUT("/rating", qc.handlePutRating)
+	library.DELETE("/rating", qc.handleDeleteRating)
+	// Forks become quizzes of the caller, which must be allowed to manage quizzes.
+	library.POST("/fork", auth.RequireRole(auth.RoleTeacher, auth.RoleAdmin), qc.handlePostFork)
+
+	// Quizzes, and how they are organized, are only managed by teachers.
+	managers := []gin.HandlerFunc{auth.RequireAuthenticated,
+		auth.RequireRole(auth.RoleTeacher, auth.RoleAdmin),
+		auth.RequireScope(auth.ScopeQuizzesRead, auth.ScopeQuizzesWrite)}
+
+	folders := rt.Group("/folders", managers...)
+	folders.GET("", qc.handleGetFolders)
+	folders.POST("", qc.handlePostFolder)
+	folders.PATCH("/:folder-id", qc.handlePatchFolder)
+	folders.DELETE("/:folder-id", qc.handleDeleteFolder)
+
+	collections := rt.Group("/collections", managers...)
+	collections.GET("", qc.handleGetCollections)
+	collections.POST("", qc.handlePostCollection)
+	collections.GET("/:collection-id", qc.handleGetCollection)
+	collections.PATCH("/:collection-id", qc.handlePatchCollection)
+	collections.DELETE("/:collection-id", qc.handleDeleteCollection)
+	collections.PUT("/:collection-id/quizzes/:quiz-id", qc.handlePutCollectionQuiz)
+	collections.DELETE("/:collection-id/quizzes/:quiz-id", qc.handleDeleteCollectionQuiz)
+
+	secured := rt.Group("/quiz", managers...)
 	secured.GET("", qc.handleGetAllUserQuiz)
 	secured.POST("", qc.handlePostQuiz)
+	secured.GET("/shared", qc.handleGetSharedQuizzes)
+
+	// Any collaborator may read the quiz, editing requires at least the editor role.
 	quiz := secured.Group("/:quiz-id", qc.ProvideQuiz)
 	quiz.GET("", handleGetQuiz)
-	quiz.PATCH("", qc.handlePatchQuiz)
+	quiz.PATCH("", RequireQuizRole(QuizRoleEditor), qc.handlePatchQuiz)
 	quiz.GET("/questions", handleGetQuestions)
-	quiz.POST("/questions", qc.handlePostQuestion)
+	quiz.POST("/questions", RequireQuizRole(QuizRoleEditor), qc.handlePostQuestion)
+
+	quiz.PUT("/questions/:question-id", RequireQuizRole(QuizRoleEditor), ProvideQuestion, qc.handlePutQuestion)
+	quiz.POST("/start", RequireQuizRole(QuizRoleEditor), qc.handleStartQuiz)
+	quiz.PUT("/folder", RequireQuizRole(QuizRoleOwner), qc.handlePutQuizFolder)
+	quiz.GET("/executions", qc.handleGetExecutions)
+	quiz.GET("/stats", qc.handleGetQuizStats)
+	quiz.GET("/executions/:execution-id", qc.ProvideExecution, handleGetExecution)
+	quiz.GET("/executions/:execution-id/analytics", qc.ProvideExecution, qc.handleGetExecutionAnalytics)
+	quiz.GET("/executions/:execution-id/results", qc.ProvideExecution, qc.handleGetExecutionResults)
+
+	quiz.GET("/collaborators", qc.handleGetCollaborators)
+	quiz.POST("/collaborators", RequireQuizRole(QuizRoleOwner), qc.handlePostCollaborator)
+	quiz.DELETE("/collaborators/:user-id", qc.handleDeleteCollaborator)
+}
 
-	quiz.PUT("/questions/:question-id", ProvideQuestion, qc.handlePutQuestion)
-	quiz.POST("/start", qc.handleStartQuiz)
+// ConfigureAdminRouting registers routes inspecting any user quizzes, the given group must be
+// restricted to administrators.
+func (qc *Controller) ConfigureAdminRouting(admin *gin.RouterGroup) {
+	admin.GET("/users/:user-id/quizzes", qc.handleAdminGetQuizzes)
+	admin.GET("/users/:user-id/quizzes/:quiz-id", qc.handleAdminGetQuiz)
+	admin.GET("/keyspace", qc.handleAdminGetKeyspace)
 }
 
 func UseQuiz(ctx *gin.Context) Quiz {
 	return ctx.MustGet("current-quiz").(Quiz)
 }
 
+// UseQuizOwner returns the id of the user owning the current quiz, which may not be the caller.
+func UseQuizOwner(ctx *gin.Context) string {
+	return ctx.MustGet("current-quiz-owner").(string)
+}
+
+// UseQuizRole returns the role granted to the caller on the current quiz.
+func UseQuizRole(ctx *gin.Context) QuizRole {
+	return ctx.MustGet("current-quiz-role").(QuizRole)
+}
+
+// ProvideQuiz resolves the quiz matching the "quiz-id" parameter, either owned by the caller
+// or shared with it.
 func (qc *Controller) ProvideQuiz(ctx *gin.Context) {
 	id := auth.UseIdentity(ctx)
 	qid := ctx.Param("quiz-id")
 
-	if quiz, err := qc.Service.Get(id.Uid, qid); err == nil {
-		ctx.Set("current-quiz", quiz)
+	if access, err := qc.Service.GetAccessible(ctx.Request.Context(), id.Uid, qid); err == nil {
+		ctx.Set("current-quiz", access.Quiz)
+		ctx.Set("current-quiz-owner", access.OwnerId)
+		ctx.Set("current-quiz-role", access.Role)
+	} else if isNotFound(err) {
+		ctx.AbortWithStatus(http.StatusNotFound)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+// ProvideLibraryOwner resolves the owner of the library quiz matching the "quiz-id" parameter,
+// exposed with UseQuizOwner. Quizzes which aren't listed in the library are reported as not found.
+func (qc *Controller) ProvideLibraryOwner(ctx *gin.Context) {
+	if ownerId, err := qc.Service.GetLibraryOwner(ctx.Request.Context(), ctx.Param("quiz-id")); err == nil {
+		ctx.Set("current-quiz-owner", ownerId)
 	} else if errors.Is(err, ErrNotFound) {
 		ctx.AbortWithStatus(http.StatusNotFound)
 	} else {
-		ctx.AbortWithStatus(http.StatusInternalServerError)
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+// RequireQuizRole middleware refuses with 403 callers which weren't granted at least the given
+// role on the current quiz. It must be placed after ProvideQuiz.
+func RequireQuizRole(role QuizRole) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !UseQuizRole(ctx).Grants(role) {
+			ctx.AbortWithStatus(http.StatusForbidden)
+		}
 	}
 }
 
@@ -77,9 +188,26 @@ func UseQuestion(ctx *gin.Context) Question {
 	return ctx.MustGet("current-question").(Question)
 }
 
+func (qc *Controller) ProvideExecution(ctx *gin.Context) {
+	quiz := UseQuiz(ctx)
+	eid := ctx.Param("execution-id")
+
+	if execution, err := qc.Service.GetExecution(ctx.Request.Context(), UseQuizOwner(ctx), quiz.Id, eid); err == nil {
+		ctx.Set("current-execution", execution)
+	} else if errors.Is(err, ErrNotFound) {
+		ctx.AbortWithStatus(http.StatusNotFound)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+func UseExecution(ctx *gin.Context) Execution {
+	return ctx.MustGet("current-execution").(Execution)
+}
+
 // handleGetQuiz retourne les détails d'un quiz spécifique
 // @Summary Récupérer un quiz
-// @Description Retourne les informations d'un quiz appartenant à l'utilisateur authentifié
+// @Description Retourne les informations d'un quiz appartenant à l'utilisateur authentifié, ou partagé avec lui
 // @Tags Quizzes
 // @Produce json
 // @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
@@ -88,6 +216,7 @@ func UseQuestion(ctx *gin.Context) Question {
 // @Failure 401 {string} string "Utilisateur non authentifié"
 // @Failure 404 {string} string "Quiz non trouvé"
 // @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
 // @Router /quiz/{quiz-id} [get]
 // @Security BearerAuth
 func handleGetQuiz(ctx *gin.Context) {
@@ -133,34 +262,54 @@ func mapQuizWithLinks(quiz Quiz) QuizWithLinks {
 
 // handleGetAllUserQuiz retourne tous les quiz de l'utilisateur connecté
 // @Summary Récupérer tous mes quiz
-// @Description Retourne la liste des quiz créés par l'utilisateur authentifié
+// @Description Retourne la liste des quiz créés par l'utilisateur authentifié, éventuellement limitée à un dossier
 // @Tags Quizzes
 // @Produce json
 // @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param folder query string false "ID du dossier, ou root pour les quiz hors de tout dossier"
 // @Success 200 {object} UserQuizzesResponse "Liste des quiz de l'utilisateur"
+// @Failure 400 {string} string "Dossier inconnu"
 // @Failure 401 {string} string "Utilisateur non authentifié"
 // @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
 // @Router /quiz [get]
 // @Security BearerAuth
 func (qc *Controller) handleGetAllUserQuiz(ctx *gin.Context) {
 	id := auth.UseIdentity(ctx)
 
-	if quizzes, err := qc.Service.GetAll(id.Uid); err == nil {
+	var quizzes []Quiz
+	var err error
+	if folder, ok := ctx.GetQuery("folder"); !ok {
+		quizzes, err = qc.Service.GetAll(ctx.Request.Context(), id.Uid)
+	} else {
+		if folder == RootFolderParam {
+			folder = RootFolder
+		}
+		quizzes, err = qc.Service.GetAllInFolder(ctx.Request.Context(), id.Uid, folder)
+	}
+
+	if errors.Is(err, ErrInvalidFolder) {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+	} else if err == nil {
 		ctx.JSON(http.StatusOK, UserQuizzesResponse{
 			Data: mapMultipleQuizWithLinks(quizzes),
 			Links: Links{
 				Create: "http://localhost:8000/quiz",
 			},
 		})
-		return
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
 	}
-
-	ctx.AbortWithStatus(http.StatusInternalServerError)
 }
 
 type CreateQuizRequest struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
+	// Visibility defaults to private.
+	Visibility Visibility `json:"visibility,omitempty"`
+	Tags       []string   `json:"tags,omitempty"`
+	Category   string     `json:"category,omitempty"`
+	Language   string     `json:"language,omitempty"`
 }
 
 // handlePostQuiz crée un nouveau quiz
@@ -175,6 +324,7 @@ type CreateQuizRequest struct {
 // @Failure 400 {string} string "Requête invalide"
 // @Failure 401 {string} string "Utilisateur non authentifié"
 // @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
 // @Router /quiz [post]
 // @Security BearerAuth
 func (qc *Controller) handlePostQuiz(ctx *gin.Context) {
@@ -187,38 +337,41 @@ func (qc *Controller) handlePostQuiz(ctx *gin.Context) {
 		return
 	}
 
-	if code, err := GenerateCode(); err == nil {
-		quiz := Quiz{
-			Id:          uuid.New().String(),
-			Title:       req.Title,
-			Description: req.Description,
-			Code:        code,
-		}
-
-		if err2 := qc.Service.Create(id.Uid, quiz); err2 == nil {
-			ctx.Header("Location", fmt.Sprintf("http://localhost:8000/quiz/%s", quiz.Id))
-			ctx.JSON(http.StatusCreated, quiz)
-			return
-		}
+	if req.Visibility == "" {
+		req.Visibility = VisibilityPrivate
+	} else if !req.Visibility.IsValid() {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
 	}
 
-	// WARNING / WARNING / WARNING //
-	// If this code-path is reached, it means that the requested user was never
-	// registered in our firestore.
-	// WARNING / WARNING / WARNING //
+	quiz := Quiz{
+		Id:          uuid.New().String(),
+		Title:       req.Title,
+		Description: req.Description,
+		Visibility:  req.Visibility,
+		Tags:        req.Tags,
+		Category:    req.Category,
+		Language:    req.Language,
+	}
+	if !quiz.NormalizeMetadata() {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
 
-	// This may happen if client does some weird things...
-	// We should never let the client decide about this process,
-	// user registration must be done in single request (Client->Server), or we must use pub/sub (or something similar)
-	// from firebase to service user automatically to avoid data consistency issues.
-	ctx.AbortWithStatus(http.StatusInternalServerError)
+	// Users are provisioned by RequireAuthenticated, the owner document always exists here.
+	if err := qc.Service.Create(ctx.Request.Context(), id.Uid, quiz); err == nil {
+		ctx.Header("Location", fmt.Sprintf("http://localhost:8000/quiz/%s", quiz.Id))
+		ctx.JSON(http.StatusCreated, quiz)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
 }
 
 type PatchQuizRequest []FieldPatchOp
 
 // handlePatchQuiz met à jour un quiz existant
 // @Summary Modifier un quiz
-// @Description Met à jour un quiz existant en fonction des champs envoyés
+// @Description Met à jour un quiz existant en fonction des champs envoyés (opération replace sur /title, /description, /visibility : private, unlisted, public, /tags, /category ou /language)
 // @Tags Quizzes
 // @Accept json
 // @Produce json
@@ -229,10 +382,10 @@ type PatchQuizRequest []FieldPatchOp
 // @Failure 400 {string} string "Requête invalide"
 // @Failure 401 {string} string "Utilisateur non authentifié"
 // @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
 // @Router /quiz/{quiz-id} [patch]
 // @Security BearerAuth
 func (qc *Controller) handlePatchQuiz(ctx *gin.Context) {
-	id := auth.UseIdentity(ctx)
 	quiz := UseQuiz(ctx)
 
 	var req PatchQuizRequest
@@ -241,12 +394,12 @@ func (qc *Controller) handlePatchQuiz(ctx *gin.Context) {
 		return
 	}
 
-	if err := qc.Service.Patch(id.Uid, quiz.Id, req); err == nil {
+	if err := qc.Service.Patch(ctx.Request.Context(), UseQuizOwner(ctx), quiz.Id, req); err == nil {
 		ctx.Status(http.StatusNoContent)
 	} else if errors.Is(err, ErrInvalidPatchOperator) || errors.Is(err, ErrInvalidPatchField) {
 		ctx.AbortWithStatus(http.StatusBadRequest)
 	} else {
-		ctx.AbortWithStatus(http.StatusInternalServerError)
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
 	}
 }
 
@@ -268,10 +421,10 @@ type CreateQuestionRequest struct {
 // @Failure 400 {string} string "Requête invalide"
 // @Failure 401 {string} string "Utilisateur non authentifié"
 // @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
 // @Router /quiz/{quiz-id}/questions [post]
 // @Security BearerAuth
 func (qc *Controller) handlePostQuestion(ctx *gin.Context) {
-	id := auth.UseIdentity(ctx)
 	quiz := UseQuiz(ctx)
 
 	var req CreateQuestionRequest
@@ -285,10 +438,10 @@ func (qc *Controller) handlePostQuestion(ctx *gin.Context) {
 		Title:   req.Title,
 		Answers: req.Answers,
 	}
-	err := qc.Service.CreateQuestion(id.Uid, quiz, question)
+	err := qc.Service.CreateQuestion(ctx.Request.Context(), UseQuizOwner(ctx), quiz, question)
 
 	if err != nil {
-		ctx.AbortWithStatus(http.StatusInternalServerError)
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
 		return
 	}
 
@@ -307,6 +460,7 @@ func (qc *Controller) handlePostQuestion(ctx *gin.Context) {
 // @Failure 401 {string} string "Utilisateur non authentifié"
 // @Failure 404 {string} string "Quiz non trouvé"
 // @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
 // @Router /quiz/{quiz-id}/questions [get]
 // @Security BearerAuth
 func handleGetQuestions(ctx *gin.Context) {
@@ -339,10 +493,10 @@ type UpdateQuestionRequest struct {
 // @Failure 401 {string} string "Utilisateur non authentifié"
 // @Failure 404 {string} string "Quiz ou question non trouvée"
 // @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
 // @Router /quiz/{quiz-id}/questions/{question-id} [put]
 // @Security BearerAuth
 func (qc *Controller) handlePutQuestion(ctx *gin.Context) {
-	id := auth.UseIdentity(ctx)
 	quiz := UseQuiz(ctx)
 	question := UseQuestion(ctx)
 
@@ -362,8 +516,8 @@ func (qc *Controller) handlePutQuestion(ctx *gin.Context) {
 		})
 	}
 
-	if err := qc.Service.UpdateQuestion(id.Uid, quiz.Id, question); err != nil {
-		ctx.AbortWithStatus(http.StatusInternalServerError)
+	if err := qc.Service.UpdateQuestion(ctx.Request.Context(), UseQuizOwner(ctx), quiz.Id, question); err != nil {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
 		return
 	}
 
@@ -372,30 +526,968 @@ func (qc *Controller) handlePutQuestion(ctx *gin.Context) {
 
 // handleStartQuiz démarre un quiz
 // @Summary Démarrer un quiz
-// @Description Démarre un quiz et retourne son code d'exécution
+// @Description Crée une nouvelle exécution du quiz, avec son propre code, et la retourne. Un même quiz peut avoir plusieurs exécutions en parallèle.
 // @Tags Quizzes
 // @Produce json
 // @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
 // @Param quiz-id path string true "ID du quiz"
-// @Success 201 {string} string "Quiz démarré avec succès"
+// @Success 201 {object} Execution "Exécution créée avec succès"
 // @Failure 400 {string} string "Quiz non prêt à être démarré"
 // @Failure 401 {string} string "Utilisateur non authentifié"
 // @Failure 404 {string} string "Quiz non trouvé"
 // @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 503 {string} string "Aucun code d'exécution disponible"
+// @Failure 504 {string} string "Délai d'attente dépassé"
 // @Router /quiz/{quiz-id}/start [post]
 // @Security BearerAuth
 func (qc *Controller) handleStartQuiz(ctx *gin.Context) {
-	identity := auth.UseIdentity(ctx)
 	quiz := UseQuiz(ctx)
 
-	if err := qc.Service.StartQuiz(identity.Uid, quiz); errors.Is(err, ErrQuizNotReady) {
+	// Executions are always stored under the quiz owner, whoever starts them.
+	execution, err := qc.Service.StartQuiz(ctx.Request.Context(), UseQuizOwner(ctx), quiz)
+	if errors.Is(err, ErrQuizNotReady) {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	} else if errors.Is(err, ErrNoCodeAvailable) {
+		ctx.AbortWithStatus(http.StatusServiceUnavailable)
+		return
+	} else if err != nil {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+		return
+	}
+
+	ctx.Header("Location", fmt.Sprintf("http://localhost:8000/execution/%s", execution.Code))
+	ctx.JSON(http.StatusCreated, execution)
+}
+
+// handleGetExecutions retourne les exécutions d'un quiz
+// @Summary Récupérer les exécutions d'un quiz
+// @Description Retourne toutes les exécutions (en cours ou terminées) du quiz spécifié, des plus récentes aux plus anciennes
+// @Tags Quizzes
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param quiz-id path string true "ID du quiz"
+// @Success 200 {array} Execution "Liste des exécutions du quiz"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Quiz non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /quiz/{quiz-id}/executions [get]
+// @Security BearerAuth
+func (qc *Controller) handleGetExecutions(ctx *gin.Context) {
+	quiz := UseQuiz(ctx)
+
+	if executions, err := qc.Service.GetExecutions(ctx.Request.Context(), UseQuizOwner(ctx), quiz.Id); err == nil {
+		ctx.JSON(http.StatusOK, executions)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+// handleGetExecution retourne une exécution d'un quiz
+// @Summary Récupérer une exécution
+// @Description Retourne l'état (code, statut, question courante) d'une exécution du quiz
+// @Tags Quizzes
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param quiz-id path string true "ID du quiz"
+// @Param execution-id path string true "ID de l'exécution"
+// @Success 200 {object} Execution "Détails de l'exécution"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Quiz ou exécution non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /quiz/{quiz-id}/executions/{execution-id} [get]
+// @Security BearerAuth
+func handleGetExecution(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, UseExecution(ctx))
+}
+
+// handleGetExecutionAnalytics retourne les statistiques par question d'une exécution
+// @Summary Statistiques d'une exécution
+// @Description Calcule, pour chaque question du quiz, le pourcentage de bonnes réponses, la répartition des réponses, le temps de réponse médian (en millisecondes) et le nombre de participants ayant passé la question, à partir des réponses enregistrées de l'exécution.
+// @Tags Quizzes
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param quiz-id path string true "ID du quiz"
+// @Param execution-id path string true "ID de l'exécution"
+// @Success 200 {object} ExecutionAnalytics "Statistiques par question"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Quiz ou exécution non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /quiz/{quiz-id}/executions/{execution-id}/analytics [get]
+// @Security BearerAuth
+func (qc *Controller) handleGetExecutionAnalytics(ctx *gin.Context) {
+	if analytics, err := qc.Service.GetExecutionAnalytics(ctx.Request.Context(), UseQuiz(ctx), UseExecution(ctx)); err == nil {
+		ctx.JSON(http.StatusOK, analytics)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+// handleGetSharedQuizzes retourne les quiz partagés avec l'utilisateur connecté
+// @Summary Récupérer les quiz partagés avec moi
+// @Description Retourne les quiz d'autres utilisateurs sur lesquels l'utilisateur authentifié est collaborateur, avec le rôle accordé (editor ou viewer)
+// @Tags Quizzes
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Success 200 {array} QuizAccess "Liste des quiz partagés"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /quiz/shared [get]
+// @Security BearerAuth
+func (qc *Controller) handleGetSharedQuizzes(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	if shared, err := qc.Service.GetShared(ctx.Request.Context(), id.Uid); err == nil {
+		ctx.JSON(http.StatusOK, shared)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+// handleGetCollaborators retourne les collaborateurs d'un quiz
+// @Summary Récupérer les collaborateurs d'un quiz
+// @Description Retourne les utilisateurs ayant accès au quiz, avec leur rôle
+// @Tags Quizzes
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param quiz-id path string true "ID du quiz"
+// @Success 200 {array} Collaborator "Liste des collaborateurs"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Quiz non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /quiz/{quiz-id}/collaborators [get]
+// @Security BearerAuth
+func (qc *Controller) handleGetCollaborators(ctx *gin.Context) {
+	quiz := UseQuiz(ctx)
+
+	if collaborators, err := qc.Service.GetCollaborators(ctx.Request.Context(), UseQuizOwner(ctx), quiz.Id); err == nil {
+		ctx.JSON(http.StatusOK, collaborators)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+type InviteCollaboratorRequest struct {
+	// User is the email or username of the invited user.
+	User string   `json:"user" binding:"required"`
+	Role QuizRole `json:"role" binding:"required"`
+}
+
+// handlePostCollaborator invite un collaborateur sur un quiz
+// @Summary Inviter un collaborateur
+// @Description Accorde un rôle (editor ou viewer) sur le quiz à un utilisateur, désigné par son email ou son nom d'utilisateur. Réservé au propriétaire du quiz. Seuls les enseignants (et administrateurs) peuvent être invités. Un collaborateur déjà invité voit son rôle remplacé.
+// @Tags Quizzes
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param quiz-id path string true "ID du quiz"
+// @Param body body InviteCollaboratorRequest true "Utilisateur invité et rôle accordé"
+// @Success 201 {object} Collaborator "Collaborateur ajouté"
+// @Failure 400 {string} string "Requête invalide, ou utilisateur invité qui n'est pas enseignant"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 403 {string} string "Réservé au propriétaire du quiz"
+// @Failure 404 {string} string "Quiz ou utilisateur non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /quiz/{quiz-id}/collaborators [post]
+// @Security BearerAuth
+func (qc *Controller) handlePostCollaborator(ctx *gin.Context) {
+	quiz := UseQuiz(ctx)
+
+	var req InviteCollaboratorRequest
+	if ctx.ShouldBindJSON(&req) != nil {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	user, ok, err := qc.Users.FindUser(ctx.Request.Context(), req.User)
+	if err != nil {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+		return
+	} else if !ok {
+		ctx.AbortWithStatus(http.StatusNotFound)
+		return
+	} else if !user.ManagesQuizzes() {
+		// Players couldn't reach the quiz anyway.
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	collaborator, err2 := qc.Service.AddCollaborator(ctx.Request.Context(), UseQuizOwner(ctx), quiz.Id, user, req.Role)
+	if err2 == nil {
+		ctx.JSON(http.StatusCreated, collaborator)
+	} else if errors.Is(err2, ErrInvalidCollaborator) {
 		ctx.AbortWithStatus(http.StatusBadRequest)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err2))
+	}
+}
+
+// handleDeleteCollaborator retire un collaborateur d'un quiz
+// @Summary Retirer un collaborateur
+// @Description Révoque l'accès d'un collaborateur au quiz. Réservé au propriétaire, un collaborateur peut toutefois se retirer lui-même.
+// @Tags Quizzes
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param quiz-id path string true "ID du quiz"
+// @Param user-id path string true "ID du collaborateur"
+// @Success 204 {string} string "Collaborateur retiré"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 403 {string} string "Réservé au propriétaire du quiz"
+// @Failure 404 {string} string "Quiz ou collaborateur non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /quiz/{quiz-id}/collaborators/{user-id} [delete]
+// @Security BearerAuth
+func (qc *Controller) handleDeleteCollaborator(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+	quiz := UseQuiz(ctx)
+	uid := ctx.Param("user-id")
+
+	if !UseQuizRole(ctx).Grants(QuizRoleOwner) && uid != id.Uid {
+		ctx.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
+	if err := qc.Service.RemoveCollaborator(ctx.Request.Context(), UseQuizOwner(ctx), quiz.Id, uid); err == nil {
+		ctx.Status(http.StatusNoContent)
+	} else if errors.Is(err, ErrNotFound) {
+		ctx.AbortWithStatus(http.StatusNotFound)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+type CreateGuestResponse struct {
+	Token     string    `json:"token"`
+	Uid       string    `json:"uid"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// handlePostGuest délivre une identité invitée pour rejoindre une exécution
+// @Summary Obtenir une identité invitée
+// @Description Délivre un jeton invité de courte durée, valable uniquement pour rejoindre l'exécution correspondant au code. Ce jeton n'est jamais accepté sur les routes de gestion des quiz.
+// @Tags Executions
+// @Produce json
+// @Param code path string true "Code de l'exécution"
+// @Success 201 {object} CreateGuestResponse "Identité invitée"
+// @Failure 404 {string} string "Exécution non trouvée"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /execution/{code}/guest [post]
+func (qc *Controller) handlePostGuest(ctx *gin.Context) {
+	execution, _, err := qc.Service.ExecutionFromCode(ctx.Request.Context(), ctx.Param("code"))
+	if errors.Is(err, ErrNotFound) {
+		ctx.AbortWithStatus(http.StatusNotFound)
 		return
 	} else if err != nil {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+		return
+	}
+
+	token, id, expiresAt, err2 := qc.Guests.Issue(execution.Code)
+	if err2 != nil {
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, CreateGuestResponse{Token: token, Uid: id.Uid, ExpiresAt: expiresAt})
+}
+
+// handleAdminGetQuizzes retourne les quiz d'un utilisateur
+// @Summary Récupérer les quiz d'un utilisateur
+// @Description Retourne tous les quiz de n'importe quel utilisateur. Réservé aux administrateurs.
+// @Tags Admin
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param user-id path string true "ID de l'utilisateur"
+// @Success 200 {array} Quiz "Liste des quiz de l'utilisateur"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 403 {string} string "Utilisateur non administrateur"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /admin/users/{user-id}/quizzes [get]
+// @Security BearerAuth
+func (qc *Controller) handleAdminGetQuizzes(ctx *gin.Context) {
+	if quizzes, err := qc.Service.GetAll(ctx.Request.Context(), ctx.Param("user-id")); err == nil {
+		ctx.JSON(http.StatusOK, quizzes)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+// handleAdminGetQuiz retourne un quiz de n'importe quel utilisateur
+// @Summary Inspecter un quiz
+// @Description Retourne un quiz (questions et réponses comprises) de n'importe quel utilisateur. Réservé aux administrateurs.
+// @Tags Admin
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param user-id path string true "ID du propriétaire"
+// @Param quiz-id path string true "ID du quiz"
+// @Success 200 {object} Quiz "Détails du quiz"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 403 {string} string "Utilisateur non administrateur"
+// @Failure 404 {string} string "Quiz non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /admin/users/{user-id}/quizzes/{quiz-id} [get]
+// @Security BearerAuth
+func (qc *Controller) handleAdminGetQuiz(ctx *gin.Context) {
+	if quiz, err := qc.Service.Get(ctx.Request.Context(), ctx.Param("user-id"), ctx.Param("quiz-id")); err == nil {
+		ctx.JSON(http.StatusOK, quiz)
+	} else if errors.Is(err, ErrNotFound) {
+		ctx.AbortWithStatus(http.StatusNotFound)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+// keyspaceLister is implemented by resolvers able to list their entries.
+type keyspaceLister interface {
+	Keyspace(ctx context.Context) (Keyspace, error)
+}
+
+// handleAdminGetKeyspace liste les codes et salles actifs
+// @Summary Lister les codes et salles actifs
+// @Description Retourne les codes d'exécution et salles présents dans Redis (SCAN). Réservé aux administrateurs.
+// @Tags Admin
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Success 200 {object} Keyspace "Codes et salles actifs"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 403 {string} string "Utilisateur non administrateur"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 501 {string} string "Non supporté par le résolveur configuré"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /admin/keyspace [get]
+// @Security BearerAuth
+func (qc *Controller) handleAdminGetKeyspace(ctx *gin.Context) {
+	lister, ok := qc.Resolver.(keyspaceLister)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusNotImplemented)
+		return
+	}
+
+	if ks, err := lister.Keyspace(ctx.Request.Context()); err == nil {
+		ctx.JSON(http.StatusOK, ks)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+type LibraryRequest struct {
+	// Q is searched in titles, descriptions, tags and questions.
+	Q string `form:"q"`
+	// Tags may be repeated, or separated by commas.
+	Tags     []string `form:"tags"`
+	Category string   `form:"category"`
+	Language string   `form:"language"`
+	// Sort is relevance by default, popular or rating.
+	Sort     SearchSort `form:"sort"`
+	Page     int        `form:"page"`
+	PageSize int        `form:"pageSize"`
+}
+
+// handleGetLibrary recherche dans la bibliothèque des quiz publics
+// @Summary Rechercher dans la bibliothèque
+// @Description Recherche plein texte (titre, description, tags et questions) parmi les quiz publics, avec filtres par tags, catégorie et langue. Les facettes comptent tous les résultats, pas seulement la page retournée.
+// @Tags Library
+// @Produce json
+// @Param q query string false "Texte recherché"
+// @Param tags query []string false "Tags requis" collectionFormat(multi)
+// @Param category query string false "Catégorie"
+// @Param language query string false "Langue (ex. fr, en-US)"
+// @Param sort query string false "Tri : relevance (pertinence), popular (nombre d'étoiles) ou rating (note moyenne)" Enums(relevance, popular, rating) default(relevance)
+// @Param page query int false "Numéro de page, à partir de 1" default(1)
+// @Param pageSize query int false "Taille de page, 100 au maximum" default(20)
+// @Success 200 {object} SearchResult "Page de résultats"
+// @Failure 400 {string} string "Requête invalide"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Router /library [get]
+func (qc *Controller) handleGetLibrary(ctx *gin.Context) {
+	var req LibraryRequest
+	if ctx.ShouldBindQuery(&req) != nil || (req.Sort != "" && !req.Sort.IsValid()) {
 		ctx.AbortWithStatus(http.StatusBadRequest)
 		return
 	}
 
-	ctx.Header("Location", fmt.Sprintf("http://localhost:8000/execution/%s", quiz.Code))
-	ctx.Status(http.StatusCreated)
+	tags := make([]string, 0, len(req.Tags))
+	for _, t := range req.Tags {
+		tags = append(tags, strings.Split(t, ",")...)
+	}
+
+	result, err := qc.Service.Search(ctx.Request.Context(), SearchQuery{
+		Text:     req.Q,
+		Tags:     tags,
+		Category: req.Category,
+		Language: req.Language,
+		Sort:     req.Sort,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	})
+	if err != nil {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// respondReaction answers a reaction request according to the given error.
+func respondReaction(ctx *gin.Context, err error) {
+	if err == nil {
+		ctx.Status(http.StatusNoContent)
+	} else if errors.Is(err, ErrNotFound) {
+		ctx.AbortWithStatus(http.StatusNotFound)
+	} else if errors.Is(err, ErrInvalidRating) {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+// handlePutStar ajoute un quiz aux favoris
+// @Summary Ajouter un quiz aux favoris
+// @Description Ajoute un quiz de la bibliothèque aux favoris de l'utilisateur authentifié. Sans effet si le quiz est déjà en favori.
+// @Tags Library
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param quiz-id path string true "ID du quiz"
+// @Success 204 {string} string "Quiz ajouté aux favoris"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Quiz non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /library/{quiz-id}/star [put]
+// @Security BearerAuth
+func (qc *Controller) handlePutStar(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+	respondReaction(ctx, qc.Service.Star(ctx.Request.Context(), id.Uid, UseQuizOwner(ctx), ctx.Param("quiz-id")))
+}
+
+// handleDeleteStar retire un quiz des favoris
+// @Summary Retirer un quiz des favoris
+// @Description Retire un quiz des favoris de l'utilisateur authentifié. Sans effet si le quiz n'était pas en favori.
+// @Tags Library
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param quiz-id path string true "ID du quiz"
+// @Success 204 {string} string "Quiz retiré des favoris"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Quiz absent de la bibliothèque"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /library/{quiz-id}/star [delete]
+// @Security BearerAuth
+func (qc *Controller) handleDeleteStar(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+	respondReaction(ctx, qc.Service.Unstar(ctx.Request.Context(), id.Uid, UseQuizOwner(ctx), ctx.Param("quiz-id")))
+}
+
+type RateQuizRequest struct {
+	// Rating from 1 to 5.
+	Rating int `json:"rating" binding:"required"`
+}
+
+// handlePutRating note un quiz
+// @Summary Noter un quiz
+// @Description Attribue une note de 1 à 5 à un quiz de la bibliothèque, en remplaçant la note précédente de l'utilisateur authentifié. Un propriétaire ne peut pas noter ses propres quiz.
+// @Tags Library
+// @Accept json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param quiz-id path string true "ID du quiz"
+// @Param body body RateQuizRequest true "Note attribuée"
+// @Success 204 {string} string "Note enregistrée"
+// @Failure 400 {string} string "Note invalide"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Quiz non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /library/{quiz-id}/rating [put]
+// @Security BearerAuth
+func (qc *Controller) handlePutRating(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	var req RateQuizRequest
+	if ctx.ShouldBindJSON(&req) != nil {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	respondReaction(ctx, qc.Service.Rate(ctx.Request.Context(), id.Uid, UseQuizOwner(ctx), ctx.Param("quiz-id"), req.Rating))
+}
+
+// handleDeleteRating retire la note d'un quiz
+// @Summary Retirer sa note
+// @Description Retire la note attribuée à un quiz par l'utilisateur authentifié. Sans effet s'il ne l'avait pas noté.
+// @Tags Library
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param quiz-id path string true "ID du quiz"
+// @Success 204 {string} string "Note retirée"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Quiz absent de la bibliothèque"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /library/{quiz-id}/rating [delete]
+// @Security BearerAuth
+func (qc *Controller) handleDeleteRating(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+	respondReaction(ctx, qc.Service.Unrate(ctx.Request.Context(), id.Uid, UseQuizOwner(ctx), ctx.Param("quiz-id")))
+}
+
+// handlePostFork copie un quiz de la bibliothèque
+// @Summary Copier un quiz de la bibliothèque
+// @Description Copie un quiz de la bibliothèque d'un autre utilisateur dans les quiz de l'utilisateur authentifié. La copie est privée, ses questions et réponses reçoivent de nouveaux identifiants et forkedFrom indique le quiz d'origine, dont le compteur forks est incrémenté.
+// @Tags Library
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param quiz-id path string true "ID du quiz"
+// @Success 201 {object} Quiz "Copie créée"
+// @Failure 400 {string} string "Le quiz appartient déjà à l'utilisateur"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 403 {string} string "Réservé aux enseignants"
+// @Failure 404 {string} string "Quiz absent de la bibliothèque"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /library/{quiz-id}/fork [post]
+// @Security BearerAuth
+func (qc *Controller) handlePostFork(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	fork, err := qc.Service.Fork(ctx.Request.Context(), id.Uid, UseQuizOwner(ctx), ctx.Param("quiz-id"))
+	if err == nil {
+		ctx.Header("Location", fmt.Sprintf("http://localhost:8000/quiz/%s", fork.Id))
+		ctx.JSON(http.StatusCreated, fork)
+	} else if errors.Is(err, ErrNotFound) {
+		ctx.AbortWithStatus(http.StatusNotFound)
+	} else if errors.Is(err, ErrInvalidFork) {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+// handleGetQuizStats retourne les statistiques d'un quiz
+// @Summary Statistiques d'un quiz
+// @Description Agrège toutes les exécutions terminées du quiz : nombre de sessions et de joueurs, score moyen et répartition des scores (en pourcentage de bonnes réponses, par tranches de 10 %), questions les plus difficiles et les plus faciles, durée moyenne d'une exécution (en millisecondes). Les statistiques sont mises à jour à la fin de chaque exécution.
+// @Tags Quizzes
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param quiz-id path string true "ID du quiz"
+// @Success 200 {object} QuizStatsReport "Statistiques du quiz"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Quiz non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /quiz/{quiz-id}/stats [get]
+// @Security BearerAuth
+func (qc *Controller) handleGetQuizStats(ctx *gin.Context) {
+	if stats, err := qc.Service.GetStats(ctx.Request.Context(), UseQuizOwner(ctx), UseQuiz(ctx)); err == nil {
+		ctx.JSON(http.StatusOK, stats)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+// handleGetExecutionResults exporte les résultats d'une exécution
+// @Summary Exporter les résultats d'une exécution
+// @Description Exporte une ligne par participant : pseudo, score (nombre de bonnes réponses), rang, puis pour chaque question 1 si la réponse est correcte, 0 si elle est fausse, vide si la question a été passée. Le fichier est généré au fil de l'eau.
+// @Tags Quizzes
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param quiz-id path string true "ID du quiz"
+// @Param execution-id path string true "ID de l'exécution"
+// @Param format query string false "Format du fichier : csv (par défaut) ou xlsx"
+// @Success 200 {file} file "Résultats de l'exécution"
+// @Failure 400 {string} string "Format inconnu"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Quiz ou exécution non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /quiz/{quiz-id}/executions/{execution-id}/results [get]
+// @Security BearerAuth
+func (qc *Controller) handleGetExecutionResults(ctx *gin.Context) {
+	quiz, execution := UseQuiz(ctx), UseExecution(ctx)
+
+	format := ResultsFormat(ctx.DefaultQuery("format", string(FormatCSV)))
+	if !format.IsValid() {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	results, err := qc.Service.GetExecutionResults(ctx.Request.Context(), quiz, execution)
+	if err != nil {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+		return
+	}
+
+	ctx.Header("Content-Type", format.ContentType())
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="results-%s.%s"`, execution.Code, format))
+	ctx.Status(http.StatusOK)
+
+	// Headers are already sent, a failure can only be logged.
+	if err2 := WriteResults(ctx.Writer, format, quiz, results); err2 != nil {
+		slog.ErrorContext(ctx.Request.Context(), "failed to export execution results", "execution", execution.Code, "error", err2)
+	}
+}
+
+// RootFolderParam designates RootFolder in query parameters.
+const RootFolderParam = "root"
+
+// folderStatusOf maps errors of folders and collections management to HTTP statuses.
+func folderStatusOf(err error) int {
+	if errors.Is(err, ErrNotFound) {
+		return http.StatusNotFound
+	} else if errors.Is(err, ErrInvalidFolder) || errors.Is(err, ErrInvalidCollection) {
+		return http.StatusBadRequest
+	} else if errors.Is(err, ErrFolderNotEmpty) {
+		return http.StatusConflict
+	}
+
+	return services.HttpStatusOf(err)
+}
+
+// handleGetFolders retourne les dossiers de l'utilisateur connecté
+// @Summary Récupérer mes dossiers
+// @Description Retourne tous les dossiers de l'utilisateur authentifié, sous forme de liste à plat. L'arborescence se reconstruit à partir de parentId (vide pour les dossiers de premier niveau).
+// @Tags Folders
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Success 200 {array} Folder "Liste des dossiers"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /folders [get]
+// @Security BearerAuth
+func (qc *Controller) handleGetFolders(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	if folders, err := qc.Service.GetFolders(ctx.Request.Context(), id.Uid); err == nil {
+		ctx.JSON(http.StatusOK, folders)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+type CreateFolderRequest struct {
+	Name string `json:"name" binding:"required"`
+	// ParentId is empty for top-level folders.
+	ParentId string `json:"parentId"`
+}
+
+// handlePostFolder crée un dossier
+// @Summary Créer un dossier
+// @Description Crée un dossier, éventuellement dans un dossier parent. Les dossiers peuvent être imbriqués sur 8 niveaux au plus.
+// @Tags Folders
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param body body CreateFolderRequest true "Nom et dossier parent"
+// @Success 201 {object} Folder "Dossier créé"
+// @Failure 400 {string} string "Nom invalide, dossier parent inconnu ou imbrication trop profonde"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /folders [post]
+// @Security BearerAuth
+func (qc *Controller) handlePostFolder(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	var req CreateFolderRequest
+	if ctx.ShouldBindJSON(&req) != nil {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	if folder, err := qc.Service.CreateFolder(ctx.Request.Context(), id.Uid, req.Name, req.ParentId); err == nil {
+		ctx.JSON(http.StatusCreated, folder)
+	} else {
+		ctx.AbortWithStatus(folderStatusOf(err))
+	}
+}
+
+// handlePatchFolder renomme ou déplace un dossier
+// @Summary Modifier un dossier
+// @Description Renomme un dossier, ou le déplace avec tout son contenu dans un autre dossier (parentId vide pour le premier niveau). Un dossier ne peut pas être déplacé dans l'un de ses sous-dossiers.
+// @Tags Folders
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param folder-id path string true "ID du dossier"
+// @Param body body PatchFolderRequest true "Champs à modifier"
+// @Success 200 {object} Folder "Dossier modifié"
+// @Failure 400 {string} string "Nom invalide ou déplacement impossible"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Dossier non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /folders/{folder-id} [patch]
+// @Security BearerAuth
+func (qc *Controller) handlePatchFolder(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	var req PatchFolderRequest
+	if ctx.ShouldBindJSON(&req) != nil {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	if folder, err := qc.Service.PatchFolder(ctx.Request.Context(), id.Uid, ctx.Param("folder-id"), req); err == nil {
+		ctx.JSON(http.StatusOK, folder)
+	} else {
+		ctx.AbortWithStatus(folderStatusOf(err))
+	}
+}
+
+// handleDeleteFolder supprime un dossier vide
+// @Summary Supprimer un dossier
+// @Description Supprime un dossier, qui doit au préalable être vidé de ses quiz et sous-dossiers.
+// @Tags Folders
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param folder-id path string true "ID du dossier"
+// @Success 204 {string} string "Dossier supprimé"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Dossier non trouvé"
+// @Failure 409 {string} string "Dossier non vide"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /folders/{folder-id} [delete]
+// @Security BearerAuth
+func (qc *Controller) handleDeleteFolder(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	if err := qc.Service.DeleteFolder(ctx.Request.Context(), id.Uid, ctx.Param("folder-id")); err == nil {
+		ctx.Status(http.StatusNoContent)
+	} else {
+		ctx.AbortWithStatus(folderStatusOf(err))
+	}
+}
+
+type MoveQuizRequest struct {
+	// FolderId is empty to move the quiz out of any folder.
+	FolderId string `json:"folderId"`
+}
+
+// handlePutQuizFolder déplace un quiz dans un dossier
+// @Summary Déplacer un quiz
+// @Description Déplace un quiz dans un dossier (folderId vide pour le sortir de tout dossier). Réservé au propriétaire du quiz.
+// @Tags Folders
+// @Accept json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param quiz-id path string true "ID du quiz"
+// @Param body body MoveQuizRequest true "Dossier de destination"
+// @Success 204 {string} string "Quiz déplacé"
+// @Failure 400 {string} string "Dossier inconnu"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 403 {string} string "Réservé au propriétaire du quiz"
+// @Failure 404 {string} string "Quiz non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /quiz/{quiz-id}/folder [put]
+// @Security BearerAuth
+func (qc *Controller) handlePutQuizFolder(ctx *gin.Context) {
+	quiz := UseQuiz(ctx)
+
+	var req MoveQuizRequest
+	if ctx.ShouldBindJSON(&req) != nil {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	if err := qc.Service.MoveQuiz(ctx.Request.Context(), UseQuizOwner(ctx), quiz.Id, req.FolderId); err == nil {
+		ctx.Status(http.StatusNoContent)
+	} else {
+		ctx.AbortWithStatus(folderStatusOf(err))
+	}
+}
+
+// handleGetCollections retourne les collections de l'utilisateur connecté
+// @Summary Récupérer mes collections
+// @Description Retourne toutes les collections de l'utilisateur authentifié, avec les identifiants de leurs quiz
+// @Tags Collections
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Success 200 {array} Collection "Liste des collections"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /collections [get]
+// @Security BearerAuth
+func (qc *Controller) handleGetCollections(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	if collections, err := qc.Service.GetCollections(ctx.Request.Context(), id.Uid); err == nil {
+		ctx.JSON(http.StatusOK, collections)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+type CreateCollectionRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// handlePostCollection crée une collection
+// @Summary Créer une collection
+// @Description Crée une collection vide. Contrairement aux dossiers, un même quiz peut faire partie de plusieurs collections.
+// @Tags Collections
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param body body CreateCollectionRequest true "Nom et description"
+// @Success 201 {object} Collection "Collection créée"
+// @Failure 400 {string} string "Nom invalide"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /collections [post]
+// @Security BearerAuth
+func (qc *Controller) handlePostCollection(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	var req CreateCollectionRequest
+	if ctx.ShouldBindJSON(&req) != nil {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	if collection, err := qc.Service.CreateCollection(ctx.Request.Context(), id.Uid, req.Name, req.Description); err == nil {
+		ctx.JSON(http.StatusCreated, collection)
+	} else {
+		ctx.AbortWithStatus(folderStatusOf(err))
+	}
+}
+
+// handleGetCollection retourne une collection avec ses quiz
+// @Summary Récupérer une collection
+// @Description Retourne une collection avec ses quiz, dans l'ordre où ils ont été ajoutés
+// @Tags Collections
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param collection-id path string true "ID de la collection"
+// @Success 200 {object} CollectionWithQuizzes "Collection et ses quiz"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Collection non trouvée"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /collections/{collection-id} [get]
+// @Security BearerAuth
+func (qc *Controller) handleGetCollection(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	if collection, err := qc.Service.GetCollection(ctx.Request.Context(), id.Uid, ctx.Param("collection-id")); err == nil {
+		ctx.JSON(http.StatusOK, collection)
+	} else {
+		ctx.AbortWithStatus(folderStatusOf(err))
+	}
+}
+
+// handlePatchCollection modifie une collection
+// @Summary Modifier une collection
+// @Description Renomme une collection ou modifie sa description
+// @Tags Collections
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param collection-id path string true "ID de la collection"
+// @Param body body PatchCollectionRequest true "Champs à modifier"
+// @Success 200 {object} Collection "Collection modifiée"
+// @Failure 400 {string} string "Nom invalide"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Collection non trouvée"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /collections/{collection-id} [patch]
+// @Security BearerAuth
+func (qc *Controller) handlePatchCollection(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	var req PatchCollectionRequest
+	if ctx.ShouldBindJSON(&req) != nil {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	if collection, err := qc.Service.PatchCollection(ctx.Request.Context(), id.Uid, ctx.Param("collection-id"), req); err == nil {
+		ctx.JSON(http.StatusOK, collection)
+	} else {
+		ctx.AbortWithStatus(folderStatusOf(err))
+	}
+}
+
+// handleDeleteCollection supprime une collection
+// @Summary Supprimer une collection
+// @Description Supprime une collection, ses quiz sont conservés
+// @Tags Collections
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param collection-id path string true "ID de la collection"
+// @Success 204 {string} string "Collection supprimée"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Collection non trouvée"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /collections/{collection-id} [delete]
+// @Security BearerAuth
+func (qc *Controller) handleDeleteCollection(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	if err := qc.Service.DeleteCollection(ctx.Request.Context(), id.Uid, ctx.Param("collection-id")); err == nil {
+		ctx.Status(http.StatusNoContent)
+	} else {
+		ctx.AbortWithStatus(folderStatusOf(err))
+	}
+}
+
+// handlePutCollectionQuiz ajoute un quiz à une collection
+// @Summary Ajouter un quiz à une collection
+// @Description Ajoute un quiz de l'utilisateur authentifié à la fin d'une collection. Sans effet si le quiz en fait déjà partie.
+// @Tags Collections
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param collection-id path string true "ID de la collection"
+// @Param quiz-id path string true "ID du quiz"
+// @Success 200 {object} Collection "Collection modifiée"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Collection ou quiz non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /collections/{collection-id}/quizzes/{quiz-id} [put]
+// @Security BearerAuth
+func (qc *Controller) handlePutCollectionQuiz(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	if collection, err := qc.Service.AddToCollection(ctx.Request.Context(), id.Uid, ctx.Param("collection-id"), ctx.Param("quiz-id")); err == nil {
+		ctx.JSON(http.StatusOK, collection)
+	} else {
+		ctx.AbortWithStatus(folderStatusOf(err))
+	}
+}
+
+// handleDeleteCollectionQuiz retire un quiz d'une collection
+// @Summary Retirer un quiz d'une collection
+// @Description Retire un quiz d'une collection, le quiz est conservé
+// @Tags Collections
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param collection-id path string true "ID de la collection"
+// @Param quiz-id path string true "ID du quiz"
+// @Success 200 {object} Collection "Collection modifiée"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Collection non trouvée"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /collections/{collection-id}/quizzes/{quiz-id} [delete]
+// @Security BearerAuth
+func (qc *Controller) handleDeleteCollectionQuiz(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	if collection, err := qc.Service.RemoveFromCollection(ctx.Request.Context(), id.Uid, ctx.Param("collection-id"), ctx.Param("quiz-id")); err == nil {
+		ctx.JSON(http.StatusOK, collection)
+	} else {
+		ctx.AbortWithStatus(folderStatusOf(err))
+	}
 }
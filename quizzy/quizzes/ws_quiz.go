@@ -1,19 +1,33 @@
 package quizzes
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
-	"log"
+	"log/slog"
 	"net/http"
+	"quizzy.app/backend/quizzy/auth"
+	"quizzy.app/backend/quizzy/logging"
+	"quizzy.app/backend/quizzy/metrics"
 	"sync"
+	"time"
 )
 
+// SocketController drives running executions. Every state (room, host, cursor)
+// is keyed by execution id, so many executions of a same quiz never share them.
 type SocketController struct {
-	Service       QuizService
-	upgrade       websocket.Upgrader
-	rooms         map[string][]*websocket.Conn
-	hosts         map[string]*websocket.Conn
+	Service    QuizService
+	upgrade    websocket.Upgrader
+	rooms      map[string][]*websocket.Conn
+	hosts      map[string]*websocket.Conn
+	executions map[string]Execution
+	// players are the connections of participants currently connected, by uid.
+	players map[string]map[string]*websocket.Conn
+	// joined lists uids of participants who ever joined, so reconnecting ones aren't counted twice.
+	joined        map[string]map[string]bool
 	roomsMu       sync.Mutex
 	questionIdx   map[string]int
 	questionIdxMu sync.Mutex
@@ -31,6 +45,9 @@ func NewSocketController(service QuizService) *SocketController {
 		},
 		rooms:       make(map[string][]*websocket.Conn),
 		hosts:       make(map[string]*websocket.Conn),
+		executions:  make(map[string]Execution),
+		players:     make(map[string]map[string]*websocket.Conn),
+		joined:      make(map[string]map[string]bool),
 		questionIdx: make(map[string]int),
 	}
 }
@@ -48,44 +65,79 @@ func NewSocketController(service QuizService) *SocketController {
 // @Security BearerAuth
 func (sc *SocketController) Configure(router *gin.RouterGroup) {
 	router.GET("/", func(c *gin.Context) {
-		sc.handleWebSocket(c.Writer, c.Request)
+		sc.handleWebSocket(c.Writer, c.Request, auth.UseAuthenticator(c), auth.TryUseProvisioner(c))
 	})
 }
 
-func (sc *SocketController) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+// identifyFunc resolves the identity of a token sent along with an event.
+type identifyFunc func(ctx context.Context, token string) (auth.Identity, error)
+
+func (sc *SocketController) handleWebSocket(w http.ResponseWriter, r *http.Request, authenticator auth.Authenticator, provisioner auth.Provisioner) {
 	conn, err := sc.upgrade.Upgrade(w, r, nil)
 	if err != nil {
-		log.Println("Failed to set websocket upgrade: ", err)
+		slog.WarnContext(r.Context(), "failed to upgrade websocket", "error", err)
 		return
 	}
 
+	metrics.WsConnections.Inc()
+	defer metrics.WsConnections.Dec()
+	// Request context may already be done once the socket is closed,
+	// cleaning up must still reach our backends.
+	defer sc.releaseConn(context.WithoutCancel(r.Context()), conn)
+
+	// Socket lifetime is bound to the upgraded request, each event derives its own context
+	// from it. Logs of the connection carry the code of the last execution it targeted.
+	connCtx := r.Context()
+	identify := func(ctx context.Context, token string) (auth.Identity, error) {
+		return auth.Identify(ctx, authenticator, provisioner, token)
+	}
 	for {
 		_, msg, err := conn.ReadMessage()
-		if err != nil {
-			log.Println("Error reading message:", err)
+		if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+			slog.DebugContext(connCtx, "websocket closed")
+			break
+		} else if err != nil {
+			slog.WarnContext(connCtx, "failed to read websocket message", "error", err)
 			break
 		}
 
 		var event map[string]interface{}
 		if err := json.Unmarshal(msg, &event); err != nil {
-			log.Println("Error unmarshalling message:", err)
+			slog.WarnContext(connCtx, "failed to unmarshal websocket message", "error", err)
 			break
 		}
 
-		switch event["name"] {
+		name, _ := event["name"].(string)
+		metrics.CountWsEvent(name)
+
+		// Every event targets an execution by its code.
+		data, _ := event["data"].(map[string]any)
+		code, ok := data["executionId"].(string)
+		if !ok {
+			slog.DebugContext(connCtx, "websocket event without execution", "event", name)
+			sc.sendError(conn, "invalidEvent")
+			continue
+		}
+		connCtx = logging.With(r.Context(), slog.String(logging.AttrExecution, code))
+		ctx := connCtx
+		slog.DebugContext(ctx, "websocket event received", "event", name)
+
+		switch name {
 		case "host":
-			sc.handleHostEvent(conn, event["data"].(map[string]any))
+			sc.handleHostEvent(ctx, conn, code, data, identify)
 		case "join":
-			sc.handleJoinEvent(conn, event["data"].(map[string]any))
+			sc.handleJoinEvent(ctx, conn, code, data, identify)
 		case "nextQuestion":
-			sc.handleNextQuestionEvent(event["data"].(map[string]any))
+			sc.handleNextQuestionEvent(ctx, conn, code, data, identify)
+		case "answer":
+			sc.handleAnswerEvent(ctx, conn, code, data)
 		}
 	}
 }
 
 // handleHostEvent permet d'héberger un quiz via WebSocket
 // @Summary Héberger un quiz
-// @Description L'utilisateur devient l'hôte d'un quiz et reçoit les détails du quiz
+// @Description L'utilisateur devient l'hôte d'une exécution et reçoit les détails du quiz. Seuls le propriétaire du quiz et ses éditeurs, identifiés par leur token, peuvent héberger une exécution, qui ne peut avoir qu'un hôte connecté à la fois. Sinon l'hôte reçoit un événement 'error' avec la raison unauthorized ou alreadyHosted.
 // @Tags WebSocket
 // @Accept json
 // @Produce json
@@ -94,19 +146,39 @@ func (sc *SocketController) handleWebSocket(w http.ResponseWriter, r *http.Reque
 // @Router /quiz/ws [post]
 // @Security BearerAuth
 
-func (sc *SocketController) handleHostEvent(conn *websocket.Conn, data map[string]any) {
-	executionId := data["executionId"].(string)
-	sc.roomsMu.Lock()
-	sc.hosts[executionId] = conn                // On stocke l'host séparément
-	sc.rooms[executionId] = []*websocket.Conn{} // On initialise la room sans participants
-	sc.roomsMu.Unlock()
-
-	quiz, err := sc.Service.QuizFromCode(executionId)
+func (sc *SocketController) handleHostEvent(ctx context.Context, conn *websocket.Conn, code string, data map[string]any, identify identifyFunc) {
+	// Les clients identifient une exécution par son code.
+	execution, quiz, err := sc.Service.ExecutionFromCode(ctx, code)
 	if err != nil {
 		return
 	}
 
-	_ = sc.Service.ResetRoomPeople(executionId)
+	if !sc.mayHost(ctx, execution, data, identify) {
+		sc.sendError(conn, "unauthorized")
+		return
+	}
+
+	executionId := execution.Id
+	sc.roomsMu.Lock()
+	if host, ok := sc.hosts[executionId]; ok && host != conn {
+		sc.roomsMu.Unlock()
+		sc.sendError(conn, "alreadyHosted")
+		return
+	}
+	// Un hôte qui se reconnecte retrouve les participants encore présents.
+	_, resumed := sc.executions[executionId]
+	sc.hosts[executionId] = conn // On stocke l'host séparément
+	if !resumed {
+		sc.rooms[executionId] = []*websocket.Conn{} // On initialise la room sans participants
+	}
+	sc.executions[executionId] = execution
+	sc.observeRooms()
+	sc.roomsMu.Unlock()
+	slog.InfoContext(ctx, "execution hosted", "quiz", execution.QuizId, "resumed", resumed)
+
+	if !resumed {
+		_ = sc.Service.ResetRoomPeople(ctx, executionId)
+	}
 
 	response := map[string]interface{}{
 		"name": "hostDetails",
@@ -118,7 +190,7 @@ func (sc *SocketController) handleHostEvent(conn *websocket.Conn, data map[strin
 	_ = conn.WriteMessage(websocket.TextMessage, res)
 
 	// Exclure l'hôte du comptage
-	nbPeoples, _ := sc.Service.GetRoomPeople(executionId)
+	nbPeoples, _ := sc.Service.GetRoomPeople(ctx, executionId)
 
 	sc.broadcastToRoom(executionId, map[string]interface{}{
 		"name": "status",
@@ -128,13 +200,13 @@ func (sc *SocketController) handleHostEvent(conn *websocket.Conn, data map[strin
 		},
 	})
 	sc.questionIdxMu.Lock()
-	sc.questionIdx[executionId] = 0 // Initialiser l'index des questions
+	sc.questionIdx[executionId] = execution.Cursor // Reprendre là où l'exécution s'était arrêtée
 	sc.questionIdxMu.Unlock()
 }
 
 // handleJoinEvent permet à un utilisateur de rejoindre un quiz via WebSocket
 // @Summary Rejoindre un quiz
-// @Description Un utilisateur rejoint un quiz et reçoit les détails du quiz
+// @Description Un utilisateur rejoint un quiz et reçoit les détails du quiz. Il peut s'identifier avec son token (compte ou invité), les tokens invalides, d'invités d'une autre exécution ou de comptes supprimés sont refusés avec un événement 'error' de raison unauthorized.
 // @Tags WebSocket
 // @Accept json
 // @Produce json
@@ -143,23 +215,60 @@ func (sc *SocketController) handleHostEvent(conn *websocket.Conn, data map[strin
 // @Router /quiz/ws [post]
 // @Security BearerAuth
 
-func (sc *SocketController) handleJoinEvent(conn *websocket.Conn, data map[string]any) {
-	executionId := data["executionId"].(string)
+func (sc *SocketController) handleJoinEvent(ctx context.Context, conn *websocket.Conn, code string, data map[string]any, identify identifyFunc) {
+	execution, quiz, err := sc.Service.ExecutionFromCode(ctx, code)
+	if err != nil {
+		return
+	}
+
+	// Un joueur peut s'identifier (compte ou invité) pour être reconnu s'il se reconnecte,
+	// sinon il reçoit une identité anonyme valable le temps de la connexion.
+	uid, registered := uuid.New().String(), false
+	if token, ok := data["token"].(string); ok && len(token) > 0 {
+		// Les comptes supprimés sont refusés, même si leur token est encore valide.
+		id, err2 := identify(ctx, token)
+		if err2 != nil || (id.Guest && NormalizeCode(id.ExecutionCode) != NormalizeCode(code)) {
+			sc.sendError(conn, "unauthorized")
+			return
+		}
+		uid, registered = id.Uid, !id.Guest
+	}
+
+	executionId := execution.Id
 	sc.roomsMu.Lock()
-	sc.rooms[executionId] = append(sc.rooms[executionId], conn) // Ajout uniquement aux participants
+	if sc.players[executionId] == nil {
+		sc.players[executionId] = make(map[string]*websocket.Conn)
+		sc.joined[executionId] = make(map[string]bool)
+	}
+	previous := sc.players[executionId][uid]
+	rejoined := sc.joined[executionId][uid]
+	sc.players[executionId][uid] = conn
+	sc.joined[executionId][uid] = true
+	sc.rooms[executionId] = append(removeConn(sc.rooms[executionId], previous), conn) // Ajout uniquement aux participants
+	sc.observeRooms()
 	sc.roomsMu.Unlock()
 
-	quiz, err := sc.Service.QuizFromCode(executionId)
-	if err != nil {
-		return
+	if !rejoined {
+		_ = sc.Service.IncrRoomPeople(ctx, executionId) // On incrémente uniquement pour les participants
+
+		nickname, _ := data["nickname"].(string)
+		participant := Participant{
+			Id:         uid,
+			Nickname:   NormalizeNickname(nickname, uid),
+			Registered: registered,
+			JoinedAt:   time.Now().UTC(),
+		}
+		if err3 := sc.Service.JoinExecution(ctx, execution, participant); err3 != nil {
+			slog.ErrorContext(ctx, "failed to record participant", logging.AttrUid, uid, "error", err3)
+		}
 	}
-	_ = sc.Service.IncrRoomPeople(executionId) // On incrémente uniquement pour les participants
-	nbPeoples, _ := sc.Service.GetRoomPeople(executionId)
+	nbPeoples, _ := sc.Service.GetRoomPeople(ctx, executionId)
 
 	response := map[string]interface{}{
 		"name": "joinDetails",
 		"data": map[string]interface{}{
 			"quizTitle": quiz.Title,
+			"uid":       uid,
 		},
 	}
 	res, _ := json.Marshal(response)
@@ -174,9 +283,36 @@ func (sc *SocketController) handleJoinEvent(conn *websocket.Conn, data map[strin
 	})
 }
 
+// mayHost reports whether the token of the given event identifies a registered user allowed to
+// drive the given execution: like RequireQuizRole, the owner of its quiz or one of its editors.
+func (sc *SocketController) mayHost(ctx context.Context, execution Execution, data map[string]any, identify identifyFunc) bool {
+	token, _ := data["token"].(string)
+	if len(token) == 0 {
+		return false
+	}
+
+	id, err := identify(ctx, token)
+	if err != nil || id.Guest || !id.HasRole(auth.RoleTeacher, auth.RoleAdmin) || !id.HasScope(auth.ScopeQuizzesWrite) {
+		return false
+	}
+
+	access, err2 := sc.Service.GetAccessible(ctx, id.Uid, execution.QuizId)
+	return err2 == nil && access.OwnerId == execution.OwnerId && access.Role.Grants(QuizRoleEditor)
+}
+
+func (sc *SocketController) sendError(conn *websocket.Conn, reason string) {
+	res, _ := json.Marshal(map[string]interface{}{
+		"name": "error",
+		"data": map[string]interface{}{
+			"reason": reason,
+		},
+	})
+	_ = conn.WriteMessage(websocket.TextMessage, res)
+}
+
 // handleNextQuestionEvent passe à la question suivante du quiz
 // @Summary Passer à la question suivante
-// @Description Envoie la prochaine question et ses réponses aux participants
+// @Description Envoie la prochaine question et ses réponses aux participants. Seul l'hôte de l'exécution, identifié par son token, peut passer à la question suivante, sinon il reçoit un événement 'error' avec la raison unauthorized ou notHost.
 // @Tags WebSocket
 // @Accept json
 // @Produce json
@@ -185,18 +321,31 @@ func (sc *SocketController) handleJoinEvent(conn *websocket.Conn, data map[strin
 // @Router /quiz/ws [post]
 // @Security BearerAuth
 
-func (sc *SocketController) handleNextQuestionEvent(data map[string]any) {
-	executionId := data["executionId"].(string)
-	quiz, err := sc.Service.QuizFromCode(executionId)
+func (sc *SocketController) handleNextQuestionEvent(ctx context.Context, conn *websocket.Conn, code string, data map[string]any, identify identifyFunc) {
+	execution, quiz, err := sc.Service.ExecutionFromCode(ctx, code)
 	if err != nil {
 		return
 	}
 
+	executionId := execution.Id
+	if !sc.mayHost(ctx, execution, data, identify) {
+		sc.sendError(conn, "unauthorized")
+		return
+	}
+
+	sc.roomsMu.Lock()
+	host := sc.hosts[executionId]
+	sc.roomsMu.Unlock()
+	if host != conn {
+		sc.sendError(conn, "notHost")
+		return
+	}
+
 	if len(quiz.Questions) == 0 {
 		return
 	}
 
-	nbPeoples, _ := sc.Service.GetRoomPeople(executionId)
+	nbPeoples, _ := sc.Service.GetRoomPeople(ctx, executionId)
 
 	sc.broadcastToRoom(executionId, map[string]interface{}{
 		"name": "status",
@@ -212,24 +361,104 @@ func (sc *SocketController) handleNextQuestionEvent(data map[string]any) {
 	sc.questionIdxMu.Unlock()
 
 	if index >= len(quiz.Questions) {
-		return // Toutes les questions ont été posées
+		// Toutes les questions ont été posées, l'exécution est terminée.
+		sc.broadcastToRoom(executionId, map[string]interface{}{
+			"name": "status",
+			"data": map[string]interface{}{
+				"status":       "finished",
+				"participants": nbPeoples,
+			},
+		})
+		sc.endExecution(ctx, executionId)
+		return
 	}
 
 	question := quiz.Questions[index]
-	var answers []string
+
+	// Le moment où la question est posée sert à mesurer les temps de réponse.
+	execution.Status = ExecutionStarted
+	execution.Cursor = index + 1
+	if execution.AskedAt == nil {
+		execution.AskedAt = make(map[string]time.Time)
+	}
+	execution.AskedAt[question.Id] = time.Now().UTC()
+	if err2 := sc.Service.UpdateExecution(ctx, execution); err2 != nil {
+		slog.ErrorContext(ctx, "failed to update execution", "error", err2)
+	}
+
+	var answers, answerIds []string
 	for _, answer := range question.Answers {
 		answers = append(answers, answer.Title)
+		answerIds = append(answerIds, answer.Id)
 	}
 
 	sc.broadcastToRoom(executionId, map[string]interface{}{
 		"name": "newQuestion",
 		"data": map[string]interface{}{
-			"question": question.Title,
-			"answers":  answers,
+			"questionId": question.Id,
+			"question":   question.Title,
+			"answers":    answers,
+			"answerIds":  answerIds,
 		},
 	})
 }
 
+// handleAnswerEvent enregistre la réponse d'un participant à la question en cours
+// @Summary Répondre à la question en cours
+// @Description Enregistre la réponse (answerId) du participant à la question en cours (questionId), un answerId vide passe la question. Seule la première réponse est retenue. Le participant reçoit un événement 'answerRecorded', ou 'error' avec la raison notJoined, invalidAnswer ou alreadyAnswered.
+// @Tags WebSocket
+// @Accept json
+// @Produce json
+// @Param event body object true "Événement WebSocket 'answer'"
+// @Success 200 {object} map[string]interface{} "Réponse enregistrée"
+// @Router /quiz/ws [post]
+// @Security BearerAuth
+
+func (sc *SocketController) handleAnswerEvent(ctx context.Context, conn *websocket.Conn, code string, data map[string]any) {
+	execution, quiz, err := sc.Service.ExecutionFromCode(ctx, code)
+	if err != nil {
+		return
+	}
+
+	// Seuls les participants ayant rejoint l'exécution sur cette connexion peuvent répondre.
+	uid := ""
+	sc.roomsMu.Lock()
+	for id, c := range sc.players[execution.Id] {
+		if c == conn {
+			uid = id
+		}
+	}
+	sc.roomsMu.Unlock()
+	if uid == "" {
+		sc.sendError(conn, "notJoined")
+		return
+	}
+
+	questionId, _ := data["questionId"].(string)
+	answerId, _ := data["answerId"].(string)
+	answer, err2 := sc.Service.AnswerQuestion(ctx, execution, quiz, uid, questionId, answerId)
+	if errors.Is(err2, ErrInvalidAnswer) {
+		sc.sendError(conn, "invalidAnswer")
+		return
+	} else if errors.Is(err2, ErrAlreadyAnswered) {
+		sc.sendError(conn, "alreadyAnswered")
+		return
+	} else if err2 != nil {
+		slog.ErrorContext(ctx, "failed to record answer", logging.AttrUid, uid, "error", err2)
+		sc.sendError(conn, "internal")
+		return
+	}
+
+	res, _ := json.Marshal(map[string]interface{}{
+		"name": "answerRecorded",
+		"data": map[string]interface{}{
+			"questionId": answer.QuestionId,
+			"answerId":   answer.AnswerId,
+		},
+	})
+	_ = conn.WriteMessage(websocket.TextMessage, res)
+}
+
 func (sc *SocketController) broadcastToRoom(executionId string, message map[string]interface{}) {
 	sc.roomsMu.Lock()
 	defer sc.roomsMu.Unlock()
@@ -244,3 +473,98 @@ func (sc *SocketController) broadcastToRoom(executionId string, message map[stri
 		_ = host.WriteMessage(websocket.TextMessage, res)
 	}
 }
+
+// releaseConn removes the given connection from every room it belongs to.
+// Executions it was hosting keep running, their host may reconnect until their code expires.
+// Executions left without host nor player are forgotten, they are resumed once hosted again.
+func (sc *SocketController) releaseConn(ctx context.Context, conn *websocket.Conn) {
+	sc.roomsMu.Lock()
+	for executionId, host := range sc.hosts {
+		if host == conn {
+			delete(sc.hosts, executionId)
+			slog.InfoContext(ctx, "execution host disconnected", logging.AttrExecution, sc.executions[executionId].Code)
+		}
+	}
+	for executionId, conns := range sc.rooms {
+		sc.rooms[executionId] = removeConn(conns, conn)
+	}
+	for _, players := range sc.players {
+		for uid, c := range players {
+			if c == conn {
+				delete(players, uid)
+			}
+		}
+	}
+
+	for executionId := range sc.executions {
+		if _, hosted := sc.hosts[executionId]; !hosted && len(sc.rooms[executionId]) == 0 {
+			sc.forgetExecution(executionId)
+		}
+	}
+	sc.observeRooms()
+	sc.roomsMu.Unlock()
+}
+
+// endExecution drops the in-memory state of the given execution and releases its code.
+func (sc *SocketController) endExecution(ctx context.Context, executionId string) {
+	sc.roomsMu.Lock()
+	execution, hosted := sc.forgetExecution(executionId)
+	sc.observeRooms()
+	sc.roomsMu.Unlock()
+
+	if !hosted {
+		return
+	}
+
+	ctx = logging.With(ctx, slog.String(logging.AttrExecution, execution.Code))
+
+	// Reloading the execution, its cursor may have moved since it was hosted.
+	if current, err := sc.Service.GetExecution(ctx, execution.OwnerId, execution.QuizId, execution.Id); err == nil {
+		execution = current
+	}
+
+	if err := sc.Service.EndExecution(ctx, execution); err != nil {
+		slog.ErrorContext(ctx, "failed to end execution", "error", err)
+	} else {
+		slog.InfoContext(ctx, "execution ended")
+	}
+}
+
+// forgetExecution drops the in-memory state of the given execution, and returns it if it was hosted.
+// Must be called with roomsMu held.
+func (sc *SocketController) forgetExecution(executionId string) (Execution, bool) {
+	execution, hosted := sc.executions[executionId]
+	delete(sc.hosts, executionId)
+	delete(sc.rooms, executionId)
+	delete(sc.executions, executionId)
+	delete(sc.players, executionId)
+	delete(sc.joined, executionId)
+
+	sc.questionIdxMu.Lock()
+	delete(sc.questionIdx, executionId)
+	sc.questionIdxMu.Unlock()
+
+	return execution, hosted
+}
+
+// observeRooms publishes how many executions are hosted, and how many players are connected
+// to them. Must be called with roomsMu held.
+func (sc *SocketController) observeRooms() {
+	participants := 0
+	for _, conns := range sc.rooms {
+		participants += len(conns)
+	}
+
+	metrics.WsRooms.Set(float64(len(sc.hosts)))
+	metrics.WsParticipants.Set(float64(participants))
+}
+
+func removeConn(conns []*websocket.Conn, conn *websocket.Conn) []*websocket.Conn {
+	for i, c := range conns {
+		if c == conn {
+			return append(conns[:i], conns[i+1:]...)
+		}
+	}
+
+	return conns
+}
@@ -0,0 +1,170 @@
+package quizzes
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"quizzy.app/backend/quizzy/auth"
+	"strings"
+	"testing"
+	"time"
+)
+
+// _serveSocket serves the given controller behind the given middlewares, and returns the URL
+// clients connect to.
+func _serveSocket(t *testing.T, sc *SocketController, id auth.Identity, handlers ...gin.HandlerFunc) string {
+	eng := gin.New()
+	handlers = append([]gin.HandlerFunc{auth.ProvideAuthenticator(&auth.DummyAuthenticator{PlaceHolder: id})}, handlers...)
+	sc.Configure(eng.Group("", handlers...))
+	srv := httptest.NewServer(eng)
+	t.Cleanup(srv.Close)
+
+	return "ws" + strings.TrimPrefix(srv.URL, "http") + "/"
+}
+
+func _dial(t *testing.T, url string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.Nil(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+// _exchange sends the given event, and returns the next event received.
+func _exchange(t *testing.T, conn *websocket.Conn, event map[string]any) map[string]any {
+	assert.Nil(t, conn.WriteJSON(event))
+
+	var res map[string]any
+	assert.Nil(t, conn.ReadJSON(&res))
+	return res
+}
+
+// _await reads events until one with the given name is received.
+func _await(t *testing.T, conn *websocket.Conn, name string) map[string]any {
+	for {
+		var res map[string]any
+		if !assert.Nil(t, conn.ReadJSON(&res)) || res["name"] == name {
+			return res
+		}
+	}
+}
+
+// _hostedExecution starts an execution of a ready quiz owned by the given identity.
+func _hostedExecution(t *testing.T, id auth.Identity) (*SocketController, Execution) {
+	svc := _createDummyQuizService()
+	quiz := _readyQuiz()
+	ctx := context.Background()
+	assert.Nil(t, svc.Create(ctx, id.Uid, quiz))
+	execution, err := svc.StartQuiz(ctx, id.Uid, quiz)
+	assert.Nil(t, err)
+
+	return NewSocketController(svc), execution
+}
+
+func TestSocketRefusesEventsWithoutExecution(t *testing.T) {
+	conn := _dial(t, _serveSocket(t, NewSocketController(_createDummyQuizService()), _fakeId()))
+	invalid := map[string]any{"name": "error", "data": map[string]any{"reason": "invalidEvent"}}
+
+	assert.Equal(t, invalid, _exchange(t, conn, map[string]any{"name": "host"}))
+	// The connection is still served after a malformed event.
+	assert.Equal(t, invalid, _exchange(t, conn, map[string]any{"name": "nextQuestion", "data": map[string]any{"executionId": 42}}))
+}
+
+func TestReleaseConnForgetsPlayer(t *testing.T) {
+	sc := NewSocketController(_createDummyQuizService())
+	alice, bob := &websocket.Conn{}, &websocket.Conn{}
+	sc.rooms["e"] = []*websocket.Conn{alice, bob}
+	sc.players["e"] = map[string]*websocket.Conn{"alice": alice, "bob": bob}
+	sc.joined["e"] = map[string]bool{"alice": true, "bob": true}
+
+	sc.releaseConn(context.Background(), alice)
+
+	assert.Equal(t, []*websocket.Conn{bob}, sc.rooms["e"])
+	assert.Equal(t, map[string]*websocket.Conn{"bob": bob}, sc.players["e"])
+	// Reconnecting won't count alice twice.
+	assert.True(t, sc.joined["e"]["alice"])
+}
+
+func TestHostReconnectResumesExecution(t *testing.T) {
+	id := _fakeId()
+	sc, execution := _hostedExecution(t, id)
+	url := _serveSocket(t, sc, id)
+	host, player := _dial(t, url), _dial(t, url)
+
+	assert.Equal(t, "hostDetails", _exchange(t, host, map[string]any{"name": "host", "data": map[string]any{"executionId": execution.Code, "token": "x"}})["name"])
+	assert.Equal(t, "joinDetails", _exchange(t, player, map[string]any{"name": "join", "data": map[string]any{"executionId": execution.Code, "nickname": "Alice"}})["name"])
+
+	// Losing the host doesn't end the execution.
+	_ = host.Close()
+	assert.Eventually(t, func() bool {
+		sc.roomsMu.Lock()
+		defer sc.roomsMu.Unlock()
+		return sc.hosts[execution.Id] == nil
+	}, time.Second, 10*time.Millisecond)
+
+	current, _, err := sc.Service.ExecutionFromCode(context.Background(), execution.Code)
+	assert.Nil(t, err)
+	assert.Equal(t, ExecutionWaiting, current.Status)
+
+	// Hosting it again keeps its players.
+	host = _dial(t, url)
+	assert.Equal(t, "hostDetails", _exchange(t, host, map[string]any{"name": "host", "data": map[string]any{"executionId": execution.Code, "token": "x"}})["name"])
+	sc.roomsMu.Lock()
+	assert.Len(t, sc.rooms[execution.Id], 1)
+	assert.Len(t, sc.players[execution.Id], 1)
+	sc.roomsMu.Unlock()
+}
+
+func TestOnlyEditorsDriveExecutions(t *testing.T) {
+	owner, other := _fakeId(), _fakeId()
+	guest := auth.Identity{Uid: "guest:1", Guest: true, Roles: []auth.Role{auth.RoleTeacher}}
+	sc, execution := _hostedExecution(t, owner)
+	unauthorized := map[string]any{"name": "error", "data": map[string]any{"reason": "unauthorized"}}
+	host := func(token string) map[string]any {
+		return map[string]any{"name": "host", "data": map[string]any{"executionId": execution.Code, "token": token}}
+	}
+	next := map[string]any{"name": "nextQuestion", "data": map[string]any{"executionId": execution.Code, "token": "x"}}
+
+	ownerUrl := _serveSocket(t, sc, owner)
+	assert.Equal(t, unauthorized, _exchange(t, _dial(t, ownerUrl), host("")))
+	assert.Equal(t, unauthorized, _exchange(t, _dial(t, _serveSocket(t, sc, other)), host("x")))
+	assert.Equal(t, unauthorized, _exchange(t, _dial(t, _serveSocket(t, sc, guest)), host("x")))
+	assert.Equal(t, unauthorized, _exchange(t, _dial(t, _serveSocket(t, sc, other)), next))
+
+	first, second := _dial(t, ownerUrl), _dial(t, ownerUrl)
+	assert.Equal(t, "hostDetails", _exchange(t, first, host("x"))["name"])
+	assert.Equal(t, map[string]any{"name": "error", "data": map[string]any{"reason": "alreadyHosted"}}, _exchange(t, second, host("x")))
+	assert.Equal(t, map[string]any{"name": "error", "data": map[string]any{"reason": "notHost"}}, _exchange(t, second, next))
+
+	// Only the live host moves the execution forward.
+	assert.Nil(t, first.WriteJSON(next))
+	_await(t, first, "newQuestion")
+
+	current, _, err := sc.Service.ExecutionFromCode(context.Background(), execution.Code)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, current.Cursor)
+}
+
+// deletedProvisioner refuses every identity, as if their account was deleted.
+type deletedProvisioner struct{}
+
+func (deletedProvisioner) Provision(ctx context.Context, id auth.Identity) error {
+	return auth.ErrIdentityDeleted
+}
+
+func TestDeletedAccountCannotJoin(t *testing.T) {
+	owner := _fakeId()
+	sc, execution := _hostedExecution(t, owner)
+	conn := _dial(t, _serveSocket(t, sc, _fakeId(), auth.ProvideProvisioner(deletedProvisioner{})))
+
+	join := map[string]any{"name": "join", "data": map[string]any{"executionId": execution.Code, "token": "x"}}
+	assert.Equal(t, map[string]any{"name": "error", "data": map[string]any{"reason": "unauthorized"}}, _exchange(t, conn, join))
+
+	_, quiz, err := sc.Service.ExecutionFromCode(context.Background(), execution.Code)
+	assert.Nil(t, err)
+	results, err := sc.Service.GetExecutionResults(context.Background(), quiz, execution)
+	assert.Nil(t, err)
+	assert.Empty(t, results)
+}
@@ -8,6 +8,7 @@ import (
 	fireauth "firebase.google.com/go/auth"
 	"google.golang.org/api/option"
 	"quizzy.app/backend/quizzy/cfg"
+	"quizzy.app/backend/quizzy/metrics"
 )
 
 var (
@@ -24,8 +25,9 @@ func ConfigureFirebase(cfg cfg.AppConfig) (FirebaseServices, error) {
 		return FirebaseServices{}, ErrFirebaseConfNotFound
 	}
 
-	opt := option.WithCredentialsFile(cfg.FirebaseConfFile)
-	if app, err := firebase.NewApp(context.Background(), nil, opt); app != nil && err == nil {
+	// Firestore calls are timed, other services ignore gRPC options.
+	opts := append([]option.ClientOption{option.WithCredentialsFile(cfg.FirebaseConfFile)}, metrics.FirestoreOptions()...)
+	if app, err := firebase.NewApp(context.Background(), nil, opts...); app != nil && err == nil {
 		store, _ := app.Firestore(context.Background())
 		auth, _ := app.Auth(context.Background())
 		return FirebaseServices{
@@ -3,12 +3,15 @@ package services
 import (
 	"github.com/redis/go-redis/v9"
 	"quizzy.app/backend/quizzy/cfg"
+	"quizzy.app/backend/quizzy/metrics"
 )
 
 func ConfigureRedis(cfg cfg.AppConfig) (*redis.Client, error) {
 	if opt, err := redis.ParseURL(cfg.RedisUri); err != nil {
 		return nil, err
 	} else {
-		return redis.NewClient(opt), nil
+		client := redis.NewClient(opt)
+		client.AddHook(metrics.RedisHook{})
+		return client, nil
 	}
 }
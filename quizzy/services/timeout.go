@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"time"
+)
+
+// WithTimeout derives a new context from the given one, which will be cancelled once
+// the given timeout is elapsed. A zero (or negative) timeout only makes the context cancellable.
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// IsTimeout reports whether the given error was caused by an exceeded deadline,
+// whether it comes from the context itself or from a gRPC backend (Firestore).
+func IsTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	return status.Code(err) == codes.DeadlineExceeded
+}
+
+// HttpStatusOf returns the HTTP status matching an unexpected error:
+// 504 when the underlying operation timed out, 500 otherwise.
+func HttpStatusOf(err error) int {
+	if IsTimeout(err) {
+		return http.StatusGatewayTimeout
+	}
+
+	return http.StatusInternalServerError
+}
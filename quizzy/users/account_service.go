@@ -0,0 +1,23 @@
+package users
+
+import (
+	"context"
+	"io"
+)
+
+// IdentityProvider removes identities from the external identity provider (Firebase)
+// when their account is deleted.
+type IdentityProvider interface {
+	DeleteIdentity(ctx context.Context, uid string) error
+}
+
+type AccountService interface {
+	// Delete removes the matching user along with all its data (quizzes, executions, tokens),
+	// then its identity from the identity provider.
+	Delete(ctx context.Context, id string) error
+
+	// Export writes personal data of the matching user to the given writer, as a zip archive of
+	// JSON documents streamed as they are read. ErrNotFound is returned, before anything is written,
+	// if the user isn't registered.
+	Export(ctx context.Context, id string, w io.Writer) error
+}
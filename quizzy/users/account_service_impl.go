@@ -0,0 +1,163 @@
+package users
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"quizzy.app/backend/quizzy/quizzes"
+)
+
+type AccountServiceImpl struct {
+	Users   Store
+	Tokens  TokenStore
+	Quizzes quizzes.QuizService
+	// Identities may be nil when identities aren't managed by an external provider.
+	Identities IdentityProvider
+}
+
+func (as *AccountServiceImpl) Delete(ctx context.Context, id string) error {
+	if err := as.Quizzes.DeleteAll(ctx, id); err != nil {
+		return err
+	}
+
+	if err := as.Tokens.DeleteAll(ctx, id); err != nil {
+		return err
+	}
+
+	// Tokens of the identity stay valid for a while, they must not register the user again.
+	if err := as.Users.AddTombstone(ctx, id); err != nil {
+		return err
+	}
+
+	// An identity may exist without ever being registered, it must be removed anyway.
+	if err := as.Users.Delete(ctx, id); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	if as.Identities != nil {
+		return as.Identities.DeleteIdentity(ctx, id)
+	}
+
+	return nil
+}
+
+// Export writes sections of the archive as soon as they are read, so the whole export is never held
+// in memory: profile.json, tokens.json, reactions.json, history.json, and quizzes/{quiz-id}/quiz.json
+// and executions.json for each quiz.
+func (as *AccountServiceImpl) Export(ctx context.Context, id string, w io.Writer) error {
+	// Unregistered users are reported before anything is written.
+	user, err := as.Users.GetUnique(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	archive := zip.NewWriter(w)
+	if err2 := writeJsonEntry(archive, "profile.json", user); err2 != nil {
+		return err2
+	}
+
+	tokens, err := as.Tokens.GetAll(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err2 := writeJsonEntry(archive, "tokens.json", tokens); err2 != nil {
+		return err2
+	}
+
+	reactions, err := as.Quizzes.GetReactions(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err2 := writeJsonEntry(archive, "reactions.json", reactions); err2 != nil {
+		return err2
+	}
+
+	if err2 := as.writeHistory(ctx, archive, id); err2 != nil {
+		return err2
+	}
+
+	quizList, err := as.Quizzes.GetAll(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, quiz := range quizList {
+		if err2 := writeJsonEntry(archive, "quizzes/"+quiz.Id+"/quiz.json", quiz); err2 != nil {
+			return err2
+		}
+
+		executions, err2 := as.Quizzes.GetExecutions(ctx, id, quiz.Id)
+		if err2 != nil {
+			return err2
+		}
+
+		if err3 := writeJsonEntry(archive, "quizzes/"+quiz.Id+"/executions.json", executions); err3 != nil {
+			return err3
+		}
+	}
+
+	return archive.Close()
+}
+
+// writeHistory writes every history entry of the given user to history.json, as a JSON array
+// written a page at a time.
+func (as *AccountServiceImpl) writeHistory(ctx context.Context, archive *zip.Writer, id string) error {
+	entry, err := archive.Create("history.json")
+	if err != nil {
+		return err
+	}
+
+	if _, err2 := io.WriteString(entry, "["); err2 != nil {
+		return err2
+	}
+
+	count := 0
+	for page := 1; ; page++ {
+		p, err2 := as.Quizzes.GetHistory(ctx, id, page, quizzes.MaxPageSize)
+		if err2 != nil {
+			return err2
+		}
+
+		for _, h := range p.Data {
+			b, err3 := json.MarshalIndent(h, "  ", "  ")
+			if err3 != nil {
+				return err3
+			}
+
+			sep := ",\n  "
+			if count == 0 {
+				sep = "\n  "
+			}
+			if _, err3 = io.WriteString(entry, sep+string(b)); err3 != nil {
+				return err3
+			}
+			count++
+		}
+
+		if len(p.Data) < p.PageSize || count >= p.Total {
+			break
+		}
+	}
+
+	end := "\n]\n"
+	if count == 0 {
+		end = "]\n"
+	}
+	_, err = io.WriteString(entry, end)
+	return err
+}
+
+func writeJsonEntry(archive *zip.Writer, name string, v any) error {
+	entry, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(entry)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
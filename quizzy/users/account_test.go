@@ -0,0 +1,215 @@
+package users
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"quizzy.app/backend/quizzy/auth"
+	"quizzy.app/backend/quizzy/quizzes"
+	"testing"
+	"time"
+)
+
+// fakeQuizService only implements methods used by accounts, other methods panic.
+type fakeQuizService struct {
+	quizzes.QuizService
+	quizzes map[string][]quizzes.Quiz
+}
+
+func (f *fakeQuizService) GetAll(ctx context.Context, ownerId string) ([]quizzes.Quiz, error) {
+	return f.quizzes[ownerId], nil
+}
+
+func (f *fakeQuizService) Get(ctx context.Context, ownerId, id string) (quizzes.Quiz, error) {
+	for _, quiz := range f.quizzes[ownerId] {
+		if quiz.Id == id {
+			return quiz, nil
+		}
+	}
+
+	return quizzes.Quiz{}, quizzes.ErrNotFound
+}
+
+func (f *fakeQuizService) GetExecutions(ctx context.Context, ownerId, quizId string) ([]quizzes.Execution, error) {
+	return []quizzes.Execution{{Id: "e-" + quizId, QuizId: quizId, Code: "ABC234", Status: quizzes.ExecutionFinished}}, nil
+}
+
+func (f *fakeQuizService) GetReactions(ctx context.Context, uid string) ([]quizzes.Reaction, error) {
+	return make([]quizzes.Reaction, 0), nil
+}
+
+func (f *fakeQuizService) GetFavorites(ctx context.Context, uid string) ([]quizzes.LibraryEntry, error) {
+	arr := make([]quizzes.LibraryEntry, 0)
+	for ownerId, list := range f.quizzes {
+		for _, quiz := range list {
+			if quiz.IsShared() && ownerId != uid {
+				arr = append(arr, quizzes.NewLibraryEntry(ownerId, quiz))
+			}
+		}
+	}
+
+	return arr, nil
+}
+
+// GetHistory pages over the same three entries for every user.
+func (f *fakeQuizService) GetHistory(ctx context.Context, uid string, page, pageSize int) (quizzes.HistoryPage, error) {
+	all := []quizzes.HistoryEntry{
+		{ExecutionId: "e3", QuizTitle: "Rome", Score: 3, Rank: 1},
+		{ExecutionId: "e2", QuizTitle: "Athènes", Score: 1, Rank: 4},
+		{ExecutionId: "e1", QuizTitle: "Carthage", Score: 2, Rank: 2},
+	}
+
+	data := make([]quizzes.HistoryEntry, 0)
+	for i := (page - 1) * pageSize; i < len(all) && len(data) < pageSize; i++ {
+		data = append(data, all[i])
+	}
+
+	return quizzes.HistoryPage{Data: data, Total: len(all), Page: page, PageSize: pageSize}, nil
+}
+
+func (f *fakeQuizService) DeleteAll(ctx context.Context, ownerId string) error {
+	delete(f.quizzes, ownerId)
+	return nil
+}
+
+type fakeIdentities struct {
+	deleted []string
+}
+
+func (f *fakeIdentities) DeleteIdentity(ctx context.Context, uid string) error {
+	f.deleted = append(f.deleted, uid)
+	return nil
+}
+
+func _configureAccountHandler(id auth.Identity, users []User) (http.Handler, *fakeQuizService, *fakeIdentities, *dummyTokenStoreImpl) {
+	store := _newDummyStore(users)
+	tokens := _newDummyTokenStore()
+	quizService := &fakeQuizService{quizzes: map[string][]quizzes.Quiz{
+		id.Uid: {{Id: "q1", Title: "quiz", Questions: []quizzes.Question{{Id: "qq1", Title: "question", Answers: []quizzes.Answer{{Id: "a1", Title: "yes", IsCorrect: true}}}}}},
+	}}
+	identities := &fakeIdentities{}
+
+	eng := gin.Default()
+	rt := eng.Group("", auth.ProvideAuthenticator(&auth.DummyAuthenticator{PlaceHolder: id}))
+	con := Controller{
+		Service:  &UserServiceImpl{Store: store},
+		Tokens:   &TokenServiceImpl{Store: tokens},
+		Accounts: &AccountServiceImpl{Users: store, Tokens: tokens, Quizzes: quizService, Identities: identities},
+	}
+	con.ConfigureRouting(rt)
+
+	return eng, quizService, identities, tokens
+}
+
+func TestExportAccount(t *testing.T) {
+	id := _fakeId()
+	handler, _, _, _ := _configureAccountHandler(id, []User{{Id: id.Uid, Username: "me", Email: id.Email}})
+
+	body := httpexpect.Default(t, "/").
+		GET("/users/me/export").
+		WithHeader("Authorization", "Bearer x").
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusOK).
+		HasContentType("application/zip").
+		Body().Raw()
+
+	archive, err := zip.NewReader(bytes.NewReader([]byte(body)), int64(len(body)))
+	assert.Nil(t, err)
+
+	names := make([]string, 0)
+	for _, f := range archive.File {
+		names = append(names, f.Name)
+	}
+	assert.ElementsMatch(t, []string{"profile.json", "tokens.json", "reactions.json", "history.json", "quizzes/q1/quiz.json", "quizzes/q1/executions.json"}, names)
+
+	// History is written a page at a time, it must still be a single JSON array.
+	f, err := archive.Open("history.json")
+	assert.Nil(t, err)
+	var history []quizzes.HistoryEntry
+	assert.Nil(t, json.NewDecoder(f).Decode(&history))
+	assert.Equal(t, []string{"e3", "e2", "e1"}, []string{history[0].ExecutionId, history[1].ExecutionId, history[2].ExecutionId})
+}
+
+func TestExportUnregisteredAccount(t *testing.T) {
+	handler, _, _, _ := _configureAccountHandler(_fakeId(), nil)
+
+	httpexpect.Default(t, "/").
+		GET("/users/me/export").
+		WithHeader("Authorization", "Bearer x").
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+func TestDeleteAccount(t *testing.T) {
+	id := _fakeId()
+	handler, quizService, identities, tokens := _configureAccountHandler(id, []User{{Id: id.Uid, Username: "me", Email: id.Email}})
+	_ = tokens.Create(context.Background(), AccessToken{Id: "t1", OwnerId: id.Uid, Hash: "h1"})
+	ex := httpexpect.Default(t, "/")
+
+	ex.DELETE("/users/me").
+		WithHeader("Authorization", "Bearer x").
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusNoContent)
+
+	assert.Empty(t, quizService.quizzes[id.Uid])
+	assert.Empty(t, tokens.Tokens)
+	assert.Equal(t, []string{id.Uid}, identities.deleted)
+
+	ex.GET("/users/me").
+		WithHeader("Authorization", "Bearer x").
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+type fakeSessions struct {
+	invalidated []string
+}
+
+func (f *fakeSessions) Invalidate(ctx context.Context, uid string) error {
+	f.invalidated = append(f.invalidated, uid)
+	return nil
+}
+
+func TestDeletedAccountIsNotProvisionedAgain(t *testing.T) {
+	id := _fakeId()
+	store := _newDummyStore([]User{{Id: id.Uid, Username: "me", Email: id.Email}})
+	tokens := _newDummyTokenStore()
+	service := &UserServiceImpl{Store: store}
+	sessions := &fakeSessions{}
+	con := Controller{
+		Service:     service,
+		Tokens:      &TokenServiceImpl{Store: tokens},
+		Provisioner: NewProvisioner(service, time.Minute),
+		Sessions:    sessions,
+		Accounts: &AccountServiceImpl{
+			Users:   store,
+			Tokens:  tokens,
+			Quizzes: &fakeQuizService{quizzes: map[string][]quizzes.Quiz{id.Uid: {{Id: "q1"}}}},
+		},
+	}
+
+	eng := gin.Default()
+	// The same token keeps being accepted by the identity provider.
+	con.ConfigureRouting(eng.Group("", auth.ProvideAuthenticator(&auth.DummyAuthenticator{PlaceHolder: id}), auth.ProvideProvisioner(con.Provisioner)))
+	ex := httpexpect.Default(t, "/")
+
+	ex.DELETE("/users/me").WithHeader("Authorization", "Bearer x").WithHandler(eng).
+		Expect().
+		Status(http.StatusNoContent)
+	assert.Equal(t, []string{id.Uid}, sessions.invalidated)
+
+	ex.GET("/users/me").WithHeader("Authorization", "Bearer x").WithHandler(eng).
+		Expect().
+		Status(http.StatusUnauthorized)
+	_, err := store.GetUnique(context.Background(), id.Uid)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
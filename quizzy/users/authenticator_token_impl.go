@@ -0,0 +1,41 @@
+package users
+
+import (
+	"context"
+	"quizzy.app/backend/quizzy/auth"
+	"strings"
+)
+
+// TokenAuthenticator accepts personal access tokens, and delegates any other token
+// to the Next authenticator.
+type TokenAuthenticator struct {
+	Tokens TokenService
+	Users  UserService
+	Next   auth.Authenticator
+}
+
+func (ta *TokenAuthenticator) Authorize(ctx context.Context, token string) (auth.Identity, error) {
+	if !strings.HasPrefix(token, AccessTokenPrefix) {
+		return ta.Next.Authorize(ctx, token)
+	}
+
+	pat, err := ta.Tokens.Resolve(ctx, token)
+	if err != nil {
+		return auth.Identity{}, err
+	}
+
+	// Tokens of removed users must not be accepted anymore.
+	user, err2 := ta.Users.Get(ctx, pat.OwnerId)
+	if err2 != nil {
+		return auth.Identity{}, err2
+	}
+
+	return auth.Identity{
+		Token:         token,
+		Uid:           user.Id,
+		Email:         user.Email,
+		AccessTokenId: pat.Id,
+		Scopes:        pat.Scopes,
+		ExpiresAt:     pat.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,151 @@
+package users
+
+import (
+	"context"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"quizzy.app/backend/quizzy/auth"
+	"quizzy.app/backend/quizzy/quizzes"
+	"testing"
+)
+
+func _configureProfileHandler(id auth.Identity) http.Handler {
+	answers := []quizzes.Answer{{Id: "a1", Title: "yes", IsCorrect: true}, {Id: "a2", Title: "no"}}
+	questions := []quizzes.Question{{Id: "q", Title: "question", Answers: answers}}
+	quizService := &fakeQuizService{quizzes: map[string][]quizzes.Quiz{
+		"author": {
+			{Id: "public", Title: "public", Visibility: quizzes.VisibilityPublic, Questions: questions},
+			{Id: "unlisted", Title: "unlisted", Visibility: quizzes.VisibilityUnlisted, Questions: questions},
+			{Id: "private", Title: "private", Visibility: quizzes.VisibilityPrivate, Questions: questions},
+		},
+	}}
+
+	eng := gin.Default()
+	rt := eng.Group("", auth.ProvideAuthenticator(&auth.DummyAuthenticator{PlaceHolder: id}))
+	con := Controller{
+		Service: &UserServiceImpl{Store: _newDummyStore([]User{{Id: "author", Username: "Author", Email: "author@mail.net"}})},
+		Quizzes: quizService,
+	}
+	con.ConfigureRouting(rt)
+
+	return eng
+}
+
+func TestGetPublicProfile(t *testing.T) {
+	handler := _configureProfileHandler(_fakeId())
+	ex := httpexpect.Default(t, "/")
+
+	obj := ex.GET("/users/author").
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	obj.Value("username").IsEqual("Author")
+	obj.NotContainsKey("email")
+
+	ex.GET("/users/nobody").
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+func TestGetPublicQuizzesStripsAnswers(t *testing.T) {
+	handler := _configureProfileHandler(_fakeId())
+	ex := httpexpect.Default(t, "/")
+
+	arr := ex.GET("/users/author/quizzes").
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	arr.Length().IsEqual(1)
+	arr.Value(0).Object().Value("id").IsEqual("public")
+	arr.Value(0).Path("$.questions[0].answers[0]").Object().NotContainsKey("isCorrect")
+
+	ex.GET("/users/author/quizzes/unlisted").
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusOK)
+
+	ex.GET("/users/author/quizzes/private").
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+func TestGetPublicQuizzesRevealsAnswersToOwner(t *testing.T) {
+	id := _fakeId()
+	id.Uid = "author"
+	handler := _configureProfileHandler(id)
+
+	httpexpect.Default(t, "/").
+		GET("/users/author/quizzes").
+		WithHeader("Authorization", "Bearer x").
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Path("$[0].questions[0].answers[0].isCorrect").IsEqual(true)
+}
+
+func TestFindUserByEmailOrUsername(t *testing.T) {
+	svc := &UserServiceImpl{Store: _newDummyStore([]User{
+		{Id: "author", Username: "Author", Email: "author@mail.net"},
+		{Id: "pupil", Username: "Pupil", Roles: []auth.Role{auth.RolePlayer}},
+	})}
+
+	byEmail, ok, err := svc.FindUser(context.Background(), "author@mail.net")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "author", byEmail.Uid)
+	assert.True(t, byEmail.ManagesQuizzes())
+
+	pupil, _, _ := svc.FindUser(context.Background(), "pupil")
+	assert.False(t, pupil.ManagesQuizzes())
+
+	byName, ok2, _ := svc.FindUser(context.Background(), "author")
+	assert.True(t, ok2)
+	assert.Equal(t, "Author", byName.Username)
+
+	_, ok3, err3 := svc.FindUser(context.Background(), "nobody@mail.net")
+	assert.Nil(t, err3)
+	assert.False(t, ok3)
+}
+
+func TestGetFavorites(t *testing.T) {
+	handler := _configureProfileHandler(_fakeId())
+
+	// The fake service stars every shared quiz of other users.
+	httpexpect.Default(t, "/").
+		GET("/users/me/favorites").
+		WithHeader("Authorization", "Bearer x").
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Length().IsEqual(2)
+}
+
+func TestGetHistory(t *testing.T) {
+	handler := _configureProfileHandler(_fakeId())
+	ex := httpexpect.Default(t, "/")
+
+	obj := ex.GET("/users/me/history").
+		WithHeader("Authorization", "Bearer x").
+		WithQuery("page", 2).
+		WithQuery("pageSize", 2).
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	obj.Value("total").IsEqual(3)
+	obj.Value("data").Array().Length().IsEqual(1)
+	obj.Value("data").Array().Value(0).Object().Value("quizTitle").IsEqual("Carthage")
+
+	ex.GET("/users/me/history").
+		WithHeader("Authorization", "Bearer x").
+		WithQuery("page", "last").
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusBadRequest)
+}
@@ -0,0 +1,157 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"quizzy.app/backend/quizzy/auth"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxUsernameAttempts bounds generated usernames tried when provisioning a user.
+	MaxUsernameAttempts = 5
+	// provisionCacheSize bounds remembered users, expired entries are swept once reached.
+	provisionCacheSize = 10_000
+)
+
+var usernameForbiddenChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// Provisioner lazily creates the user document of identities seen for the first time,
+// so every authenticated user is registered even if the client never called POST /users.
+// Users known to exist are remembered for TTL, along with their roles, to avoid a Firestore
+// read on every request: it's also the auth.RoleSource of the authentication chain, roles
+// granted by an administrator apply within TTL on other instances.
+// Deleted users are never registered again.
+type Provisioner struct {
+	Service UserService
+	TTL     time.Duration
+
+	mu    sync.Mutex
+	known map[string]knownUser
+}
+
+type knownUser struct {
+	roles     []auth.Role
+	expiresAt time.Time
+}
+
+func NewProvisioner(service UserService, ttl time.Duration) *Provisioner {
+	return &Provisioner{Service: service, TTL: ttl, known: make(map[string]knownUser)}
+}
+
+// GetRoles returns roles of the given user, an unregistered user has no role.
+func (p *Provisioner) GetRoles(ctx context.Context, uid string) ([]auth.Role, error) {
+	if known, ok := p.lookup(uid); ok {
+		return known.roles, nil
+	}
+
+	user, err := p.Service.Get(ctx, uid)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	p.remember(uid, user.Roles)
+	return user.Roles, nil
+}
+
+func (p *Provisioner) Provision(ctx context.Context, id auth.Identity) error {
+	if _, ok := p.lookup(id.Uid); ok {
+		return nil
+	}
+
+	if user, err := p.Service.Get(ctx, id.Uid); err == nil {
+		p.remember(id.Uid, user.Roles)
+		return nil
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	// Tokens of deleted users may still be valid, they must not register them again.
+	if deleted, err := p.Service.IsDeleted(ctx, id.Uid); err != nil {
+		return err
+	} else if deleted {
+		return auth.ErrIdentityDeleted
+	}
+
+	base := usernameBase(id.Email)
+	for i := 0; i < MaxUsernameAttempts; i++ {
+		user := User{Id: id.Uid, Email: id.Email, Username: base + "-" + usernameSuffix()}
+		if err := p.Service.Create(ctx, user); err == nil {
+			p.remember(id.Uid, user.Roles)
+			return nil
+		} else if !errors.Is(err, ErrUsernameTaken) {
+			return err
+		}
+	}
+
+	return ErrUsernameTaken
+}
+
+// Forget drops the given user from remembered ones, e.g. once deleted or granted other roles.
+func (p *Provisioner) Forget(uid string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.known, uid)
+}
+
+func (p *Provisioner) lookup(uid string) (knownUser, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	known, ok := p.known[uid]
+	return known, ok && time.Now().Before(known.expiresAt)
+}
+
+func (p *Provisioner) remember(uid string, roles []auth.Role) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if len(p.known) >= provisionCacheSize {
+		for k, known := range p.known {
+			if !now.Before(known.expiresAt) {
+				delete(p.known, k)
+			}
+		}
+
+		// Still full of valid entries, starting over is cheaper than tracking usage.
+		if len(p.known) >= provisionCacheSize {
+			p.known = make(map[string]knownUser)
+		}
+	}
+
+	p.known[uid] = knownUser{roles: roles, expiresAt: now.Add(p.TTL)}
+}
+
+// usernameBase derives a valid username prefix from the local part of the given email.
+func usernameBase(email string) string {
+	local, _, _ := strings.Cut(email, "@")
+	base := usernameForbiddenChars.ReplaceAllString(local, "")
+	if len(base) > 24 {
+		base = base[:24]
+	}
+
+	if len(base) < 3 {
+		return "user"
+	}
+
+	return base
+}
+
+// usernameSuffix returns 6 random digits, distinguishing users sharing the same base.
+func usernameSuffix() string {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		n = big.NewInt(time.Now().UnixNano() % 1_000_000)
+	}
+
+	return strings.Repeat("0", 6-len(n.String())) + n.String()
+}
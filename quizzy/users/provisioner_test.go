@@ -0,0 +1,85 @@
+package users
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"quizzy.app/backend/quizzy/auth"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countingUserStore counts reads made against the wrapped store.
+type countingUserStore struct {
+	Store
+	reads int
+}
+
+func (st *countingUserStore) GetUnique(ctx context.Context, id string) (User, error) {
+	st.reads++
+	return st.Store.GetUnique(ctx, id)
+}
+
+func TestProvisionerCreatesUser(t *testing.T) {
+	store := &countingUserStore{Store: _newDummyStore(nil)}
+	p := NewProvisioner(&UserServiceImpl{Store: store}, time.Minute)
+	id := auth.Identity{Uid: "uid", Email: "jane.doe+quiz@mail.net"}
+
+	assert.Nil(t, p.Provision(context.Background(), id))
+
+	user, err := store.Store.GetUnique(context.Background(), "uid")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(user.Username, "jane.doequiz-"))
+	assert.Nil(t, validateUsername(user.Username))
+	assert.Equal(t, id.Email, user.Email)
+
+	reads := store.reads
+	assert.Nil(t, p.Provision(context.Background(), id))
+	assert.Equal(t, reads, store.reads, "provisioned users must be remembered")
+
+	p.Forget("uid")
+	assert.Nil(t, p.Provision(context.Background(), id))
+	assert.Equal(t, reads+1, store.reads)
+}
+
+func TestProvisionerKeepsExistingUser(t *testing.T) {
+	store := _newDummyStore([]User{{Id: "uid", Username: "chosen", Email: "me@mail.net"}})
+	p := NewProvisioner(&UserServiceImpl{Store: store}, time.Minute)
+
+	assert.Nil(t, p.Provision(context.Background(), auth.Identity{Uid: "uid", Email: "me@mail.net"}))
+
+	user, _ := store.GetUnique(context.Background(), "uid")
+	assert.Equal(t, "chosen", user.Username)
+}
+
+func TestUsernameBase(t *testing.T) {
+	assert.Equal(t, "user", usernameBase(""))
+	assert.Equal(t, "user", usernameBase("a@mail.net"))
+	assert.Equal(t, "john_smith", usernameBase("john_smith@mail.net"))
+	assert.Len(t, usernameBase(strings.Repeat("x", 40)+"@mail.net"), 24)
+}
+
+func TestProvisionerRemembersRoles(t *testing.T) {
+	store := &countingUserStore{Store: _newDummyStore([]User{{Id: "uid", Username: "teacher", Roles: []auth.Role{auth.RoleTeacher}}})}
+	p := NewProvisioner(&UserServiceImpl{Store: store}, time.Minute)
+	ctx := context.Background()
+
+	// Like the authentication chain does on every request.
+	for i := 0; i < 3; i++ {
+		roles, err := p.GetRoles(ctx, "uid")
+		assert.Nil(t, err)
+		assert.Equal(t, []auth.Role{auth.RoleTeacher}, roles)
+		assert.Nil(t, p.Provision(ctx, auth.Identity{Uid: "uid"}))
+	}
+	assert.Equal(t, 1, store.reads)
+
+	_, _ = (&UserServiceImpl{Store: store}).SetRoles(ctx, "uid", []auth.Role{auth.RoleAdmin})
+	p.Forget("uid")
+	roles, _ := p.GetRoles(ctx, "uid")
+	assert.Equal(t, []auth.Role{auth.RoleAdmin}, roles)
+
+	// Unregistered users have no role, until provisioned.
+	roles, err := p.GetRoles(ctx, "unknown")
+	assert.Nil(t, err)
+	assert.Empty(t, roles)
+}
@@ -1,25 +1,83 @@
 package users
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"log/slog"
 	"net/http"
 	"quizzy.app/backend/quizzy/auth"
 	"quizzy.app/backend/quizzy/cfg"
+	"quizzy.app/backend/quizzy/quizzes"
 	"quizzy.app/backend/quizzy/services"
 )
 
 type Controller struct {
-	Service UserService
+	Service     UserService
+	Quizzes     quizzes.QuizService
+	Tokens      TokenService
+	Accounts    AccountService
+	Provisioner *Provisioner
+	// Sessions may be nil when verified tokens aren't cached.
+	Sessions SessionInvalidator
 }
 
-func Configure(fbs *services.FirebaseServices, conf cfg.AppConfig) *Controller {
-	return &Controller{Service: &UserServiceImpl{Store: &userFirestore{client: fbs.Store}}}
+// SessionInvalidator forgets verified tokens of a user, so they are verified again on their next use.
+type SessionInvalidator interface {
+	Invalidate(ctx context.Context, uid string) error
+}
+
+// Configure builds the users controller, user quizzes are managed through the given service
+// when accounts are exported or deleted. Identities may be nil if they can't be deleted
+// from the identity provider.
+func Configure(fbs *services.FirebaseServices, conf cfg.AppConfig, quizService quizzes.QuizService, identities IdentityProvider) *Controller {
+	store := NewFirestore(fbs.Store, conf.Timeouts.Firestore)
+	tokens := NewTokenFirestore(fbs.Store, conf.Timeouts.Firestore)
+
+	service := &UserServiceImpl{Store: store}
+
+	return &Controller{
+		Service:     service,
+		Quizzes:     quizService,
+		Provisioner: NewProvisioner(service, conf.ProvisionCacheTTL),
+		Tokens:      &TokenServiceImpl{Store: tokens},
+		Accounts: &AccountServiceImpl{
+			Users:      store,
+			Tokens:     tokens,
+			Quizzes:    quizService,
+			Identities: identities,
+		},
+	}
+}
+
+// Authenticator returns an authenticator accepting personal access tokens, other tokens
+// are delegated to the given one.
+func (uc *Controller) Authenticator(next auth.Authenticator) auth.Authenticator {
+	return &TokenAuthenticator{Tokens: uc.Tokens, Users: uc.Service, Next: next}
 }
 
 func (uc *Controller) ConfigureRouting(rt *gin.RouterGroup) {
-	secured := rt.Group("/users", auth.RequireAuthenticated)
+	secured := rt.Group("/users", auth.RequireAuthenticated, auth.RequireScope(auth.ScopeUsersRead, auth.ScopeUsersWrite))
 	secured.POST("", uc.handlePostUser)
 	secured.GET("/me", uc.handleGetSelf)
+	secured.PATCH("/me", uc.handlePatchSelf)
+	secured.DELETE("/me", auth.RejectAccessTokens, uc.handleDeleteSelf)
+	secured.GET("/me/export", uc.handleGetExport)
+	secured.GET("/me/favorites", uc.handleGetFavorites)
+	secured.GET("/me/history", uc.handleGetHistory)
+
+	// Public profiles, anyone may see them.
+	public := rt.Group("/users/:username", auth.OptionalAuthenticated, uc.ProvideProfile)
+	public.GET("", uc.handleGetProfile)
+	public.GET("/quizzes", uc.handleGetProfileQuizzes)
+	public.GET("/quizzes/:quiz-id", uc.handleGetProfileQuiz)
+
+	// Access tokens can't be used to mint or revoke other tokens.
+	tokens := secured.Group("/me/tokens", auth.RejectAccessTokens)
+	tokens.POST("", uc.handlePostToken)
+	tokens.GET("", uc.handleGetTokens)
+	tokens.DELETE("/:token-id", uc.handleDeleteToken)
 }
 
 type createUserRequest struct {
@@ -36,7 +94,9 @@ type createUserRequest struct {
 // @Param body body createUserRequest true "Informations de l'utilisateur à créer"
 // @Success 201 {string} string "Utilisateur créé avec succès"
 // @Failure 400 {string} string "Requête invalide"
+// @Failure 409 {string} string "Nom d'utilisateur déjà utilisé"
 // @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
 // @Router /users [post]
 // @Security BearerAuth
 func (uc *Controller) handlePostUser(ctx *gin.Context) {
@@ -48,8 +108,14 @@ func (uc *Controller) handlePostUser(ctx *gin.Context) {
 		return
 	}
 
-	if err := uc.Service.Create(User{Username: req.Username, Email: id.Email, Id: id.Uid}); err != nil {
-		ctx.AbortWithStatus(http.StatusInternalServerError)
+	if err := uc.Service.Create(ctx.Request.Context(), User{Username: req.Username, Email: id.Email, Id: id.Uid}); errors.Is(err, ErrInvalidProfile) {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	} else if errors.Is(err, ErrUsernameTaken) {
+		ctx.AbortWithStatus(http.StatusConflict)
+		return
+	} else if err != nil {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
 		return
 	}
 
@@ -64,16 +130,478 @@ func (uc *Controller) handlePostUser(ctx *gin.Context) {
 // @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
 // @Success 200 {object} User "Informations de l'utilisateur"
 // @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Utilisateur non enregistré"
 // @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
 // @Router /users/me [get]
 // @Security BearerAuth
 func (uc *Controller) handleGetSelf(ctx *gin.Context) {
 	id := auth.UseIdentity(ctx)
 
-	if user, err := uc.Service.Get(id.Uid); err == nil {
+	if user, err := uc.Service.Get(ctx.Request.Context(), id.Uid); err == nil {
 		ctx.JSON(http.StatusOK, user)
+	} else if errors.Is(err, ErrNotFound) {
+		ctx.AbortWithStatus(http.StatusNotFound)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+// handlePatchSelf modifie le profil de l'utilisateur authentifié
+// @Summary Modifier le profil de l'utilisateur connecté
+// @Description Modifie les champs fournis du profil (username, displayName, avatarUrl, locale), les autres sont conservés. Le nom d'utilisateur est unique, sans tenir compte de la casse.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param body body PatchUserRequest true "Champs du profil à modifier"
+// @Success 200 {object} User "Profil modifié"
+// @Failure 400 {string} string "Requête invalide"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Utilisateur non enregistré"
+// @Failure 409 {string} string "Nom d'utilisateur déjà utilisé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /users/me [patch]
+// @Security BearerAuth
+func (uc *Controller) handlePatchSelf(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	var req PatchUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatus(http.StatusBadRequest)
 		return
 	}
 
-	ctx.AbortWithStatus(http.StatusInternalServerError)
+	if user, err := uc.Service.Patch(ctx.Request.Context(), id.Uid, req); err == nil {
+		ctx.JSON(http.StatusOK, user)
+	} else if errors.Is(err, ErrInvalidProfile) {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+	} else if errors.Is(err, ErrNotFound) {
+		ctx.AbortWithStatus(http.StatusNotFound)
+	} else if errors.Is(err, ErrUsernameTaken) {
+		ctx.AbortWithStatus(http.StatusConflict)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+// handleDeleteSelf supprime le compte de l'utilisateur authentifié
+// @Summary Supprimer son compte
+// @Description Supprime définitivement le compte connecté et toutes ses données (quiz, questions, réponses, exécutions, jetons d'accès), puis révoque et supprime l'utilisateur Firebase.
+// @Tags Users
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Success 204 {string} string "Compte supprimé"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 403 {string} string "Impossible avec un jeton d'accès personnel"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /users/me [delete]
+// @Security BearerAuth
+func (uc *Controller) handleDeleteSelf(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	if err := uc.Accounts.Delete(ctx.Request.Context(), id.Uid); err != nil {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+		return
+	}
+
+	if uc.Provisioner != nil {
+		uc.Provisioner.Forget(id.Uid)
+	}
+
+	// The account is gone, failing to evict cached tokens only delays their refusal.
+	if uc.Sessions != nil {
+		if err := uc.Sessions.Invalidate(ctx.Request.Context(), id.Uid); err != nil {
+			slog.WarnContext(ctx.Request.Context(), "failed to invalidate cached tokens", "error", err)
+		}
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// handleGetExport exporte les données personnelles de l'utilisateur authentifié
+// @Summary Exporter ses données personnelles
+// @Description Retourne une archive zip contenant le profil (profile.json), les jetons d'accès (tokens.json), les favoris et notes (reactions.json), l'historique des parties (history.json), et pour chaque quiz ses questions, réponses (quizzes/{quiz-id}/quiz.json) et exécutions (quizzes/{quiz-id}/executions.json). L'archive est transmise au fur et à mesure de sa lecture.
+// @Tags Users
+// @Produce application/zip
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Success 200 {file} file "Archive des données personnelles"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 404 {string} string "Utilisateur non enregistré"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /users/me/export [get]
+// @Security BearerAuth
+func (uc *Controller) handleGetExport(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	// The archive is streamed as it is read, failures can only be reported until something was sent.
+	ctx.Header("Content-Type", "application/zip")
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="quizzy-export-%s.zip"`, id.Uid))
+
+	err := uc.Accounts.Export(ctx.Request.Context(), id.Uid, ctx.Writer)
+	if err == nil {
+		return
+	} else if ctx.Writer.Written() {
+		slog.ErrorContext(ctx.Request.Context(), "failed to write account export", "error", err)
+		return
+	}
+
+	ctx.Writer.Header().Del("Content-Type")
+	ctx.Writer.Header().Del("Content-Disposition")
+	if errors.Is(err, ErrNotFound) {
+		ctx.AbortWithStatus(http.StatusNotFound)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+// handleGetFavorites retourne les quiz favoris de l'utilisateur authentifié
+// @Summary Récupérer mes favoris
+// @Description Retourne les quiz mis en favori par l'utilisateur authentifié, des plus récents aux plus anciens. Les quiz supprimés ou redevenus privés sont ignorés.
+// @Tags Users
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Success 200 {array} quizzes.LibraryEntry "Liste des quiz favoris"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /users/me/favorites [get]
+// @Security BearerAuth
+func (uc *Controller) handleGetFavorites(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	if favorites, err := uc.Quizzes.GetFavorites(ctx.Request.Context(), id.Uid); err == nil {
+		ctx.JSON(http.StatusOK, favorites)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+type HistoryRequest struct {
+	Page     int `form:"page"`
+	PageSize int `form:"pageSize"`
+}
+
+// handleGetHistory retourne l'historique des parties de l'utilisateur authentifié
+// @Summary Récupérer mon historique
+// @Description Retourne les exécutions terminées auxquelles l'utilisateur authentifié a participé avec son compte, des plus récentes aux plus anciennes, avec le titre du quiz, la date, le score et le rang obtenus.
+// @Tags Users
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param page query int false "Numéro de page, à partir de 1"
+// @Param pageSize query int false "Nombre d'entrées par page (20 par défaut, 100 au plus)"
+// @Success 200 {object} quizzes.HistoryPage "Page de l'historique"
+// @Failure 400 {string} string "Pagination invalide"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /users/me/history [get]
+// @Security BearerAuth
+func (uc *Controller) handleGetHistory(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	var req HistoryRequest
+	if ctx.ShouldBindQuery(&req) != nil {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	if history, err := uc.Quizzes.GetHistory(ctx.Request.Context(), id.Uid, req.Page, req.PageSize); err == nil {
+		ctx.JSON(http.StatusOK, history)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+// ConfigureAdminRouting registers users management routes, the given group must be
+// restricted to administrators.
+func (uc *Controller) ConfigureAdminRouting(admin *gin.RouterGroup) {
+	admin.GET("/users", uc.handleAdminGetUsers)
+	admin.GET("/users/:user-id", uc.handleAdminGetUser)
+	admin.PUT("/users/:user-id/roles", uc.handleAdminPutRoles)
+}
+
+// handleAdminGetUsers liste tous les utilisateurs
+// @Summary Lister les utilisateurs
+// @Description Retourne tous les utilisateurs enregistrés. Réservé aux administrateurs.
+// @Tags Admin
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Success 200 {array} User "Liste des utilisateurs"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 403 {string} string "Utilisateur non administrateur"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /admin/users [get]
+// @Security BearerAuth
+func (uc *Controller) handleAdminGetUsers(ctx *gin.Context) {
+	if users, err := uc.Service.GetAll(ctx.Request.Context()); err == nil {
+		ctx.JSON(http.StatusOK, users)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+// handleAdminGetUser récupère un utilisateur
+// @Summary Récupérer un utilisateur
+// @Description Retourne les informations de n'importe quel utilisateur. Réservé aux administrateurs.
+// @Tags Admin
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param user-id path string true "ID de l'utilisateur"
+// @Success 200 {object} User "Informations de l'utilisateur"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 403 {string} string "Utilisateur non administrateur"
+// @Failure 404 {string} string "Utilisateur non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /admin/users/{user-id} [get]
+// @Security BearerAuth
+func (uc *Controller) handleAdminGetUser(ctx *gin.Context) {
+	if user, err := uc.Service.Get(ctx.Request.Context(), ctx.Param("user-id")); err == nil {
+		ctx.JSON(http.StatusOK, user)
+	} else if errors.Is(err, ErrNotFound) {
+		ctx.AbortWithStatus(http.StatusNotFound)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+type updateRolesRequest struct {
+	Roles []auth.Role `json:"roles"`
+}
+
+// handleAdminPutRoles modifie les rôles d'un utilisateur
+// @Summary Modifier les rôles d'un utilisateur
+// @Description Remplace les rôles (admin, teacher, player) accordés à un utilisateur. Réservé aux administrateurs.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param user-id path string true "ID de l'utilisateur"
+// @Param body body updateRolesRequest true "Rôles de l'utilisateur"
+// @Success 200 {object} User "Utilisateur mis à jour"
+// @Failure 400 {string} string "Requête invalide"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 403 {string} string "Utilisateur non administrateur"
+// @Failure 404 {string} string "Utilisateur non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /admin/users/{user-id}/roles [put]
+// @Security BearerAuth
+func (uc *Controller) handleAdminPutRoles(ctx *gin.Context) {
+	var req updateRolesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	for _, r := range req.Roles {
+		if r != auth.RoleAdmin && r != auth.RoleTeacher && r != auth.RolePlayer {
+			ctx.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+	}
+
+	if user, err := uc.Service.SetRoles(ctx.Request.Context(), ctx.Param("user-id"), req.Roles); err == nil {
+		// Remembered roles are outdated.
+		if uc.Provisioner != nil {
+			uc.Provisioner.Forget(user.Id)
+		}
+		ctx.JSON(http.StatusOK, user)
+	} else if errors.Is(err, ErrNotFound) {
+		ctx.AbortWithStatus(http.StatusNotFound)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+type CreateTokenResponse struct {
+	AccessToken
+	// Token is the secret, it is only returned once.
+	Token string `json:"token"`
+}
+
+// handlePostToken crée un jeton d'accès personnel
+// @Summary Créer un jeton d'accès personnel
+// @Description Crée un jeton d'accès révocable, limité aux scopes demandés (quizzes:read, quizzes:write, users:read, users:write). Le secret n'est retourné qu'une seule fois. Sans date d'expiration, le jeton expire après 90 jours (366 jours maximum).
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param body body CreateTokenRequest true "Nom, scopes et expiration du jeton"
+// @Success 201 {object} CreateTokenResponse "Jeton créé"
+// @Failure 400 {string} string "Requête invalide"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 403 {string} string "Impossible avec un jeton d'accès personnel"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /users/me/tokens [post]
+// @Security BearerAuth
+func (uc *Controller) handlePostToken(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	var req CreateTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	secret, token, err := uc.Tokens.Create(ctx.Request.Context(), id.Uid, req)
+	if errors.Is(err, ErrInvalidScope) || errors.Is(err, ErrInvalidExpiry) || errors.Is(err, ErrInvalidTokenName) {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+	} else if err != nil {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	} else {
+		ctx.JSON(http.StatusCreated, CreateTokenResponse{AccessToken: token, Token: secret})
+	}
+}
+
+// handleGetTokens liste les jetons d'accès personnels
+// @Summary Lister les jetons d'accès personnels
+// @Description Retourne les jetons de l'utilisateur connecté, sans leur secret
+// @Tags Users
+// @Produce json
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Success 200 {array} AccessToken "Liste des jetons"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 403 {string} string "Impossible avec un jeton d'accès personnel"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /users/me/tokens [get]
+// @Security BearerAuth
+func (uc *Controller) handleGetTokens(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	if tokens, err := uc.Tokens.GetAll(ctx.Request.Context(), id.Uid); err == nil {
+		ctx.JSON(http.StatusOK, tokens)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+// handleDeleteToken révoque un jeton d'accès personnel
+// @Summary Révoquer un jeton d'accès personnel
+// @Description Supprime le jeton, il ne pourra plus être utilisé
+// @Tags Users
+// @Param Authorization header string true "Token d'authentification Bearer" default(Bearer <votre_token>)
+// @Param token-id path string true "ID du jeton"
+// @Success 204 {string} string "Jeton révoqué"
+// @Failure 401 {string} string "Utilisateur non authentifié"
+// @Failure 403 {string} string "Impossible avec un jeton d'accès personnel"
+// @Failure 404 {string} string "Jeton non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /users/me/tokens/{token-id} [delete]
+// @Security BearerAuth
+func (uc *Controller) handleDeleteToken(ctx *gin.Context) {
+	id := auth.UseIdentity(ctx)
+
+	if err := uc.Tokens.Revoke(ctx.Request.Context(), id.Uid, ctx.Param("token-id")); err == nil {
+		ctx.Status(http.StatusNoContent)
+	} else if errors.Is(err, ErrTokenNotFound) {
+		ctx.AbortWithStatus(http.StatusNotFound)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+// ProvideProfile resolves the user matching the "username" parameter.
+func (uc *Controller) ProvideProfile(ctx *gin.Context) {
+	if user, err := uc.Service.GetByUsername(ctx.Request.Context(), ctx.Param("username")); err == nil {
+		ctx.Set("current-profile", user)
+	} else if errors.Is(err, ErrNotFound) {
+		ctx.AbortWithStatus(http.StatusNotFound)
+	} else {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	}
+}
+
+func UseProfile(ctx *gin.Context) User {
+	return ctx.MustGet("current-profile").(User)
+}
+
+// isProfileOwner reports whether the current request was made by the owner of the current profile.
+func isProfileOwner(ctx *gin.Context) bool {
+	id, ok := auth.TryUseIdentity(ctx)
+	return ok && !id.Guest && id.Uid == UseProfile(ctx).Id
+}
+
+// handleGetProfile retourne le profil public d'un utilisateur
+// @Summary Récupérer un profil public
+// @Description Retourne le profil public d'un utilisateur (sans email ni rôles). L'authentification est facultative.
+// @Tags Users
+// @Produce json
+// @Param username path string true "Nom d'utilisateur (insensible à la casse)"
+// @Success 200 {object} PublicUser "Profil public"
+// @Failure 404 {string} string "Utilisateur non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /users/{username} [get]
+func (uc *Controller) handleGetProfile(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, UseProfile(ctx).Public())
+}
+
+// handleGetProfileQuizzes liste les quiz publics d'un utilisateur
+// @Summary Lister les quiz publics d'un utilisateur
+// @Description Retourne les quiz publics d'un utilisateur. Les bonnes réponses (isCorrect) ne sont incluses que si l'appelant est le propriétaire. L'authentification est facultative.
+// @Tags Users
+// @Produce json
+// @Param Authorization header string false "Token d'authentification Bearer"
+// @Param username path string true "Nom d'utilisateur (insensible à la casse)"
+// @Success 200 {array} quizzes.PublicQuiz "Quiz publics"
+// @Failure 401 {string} string "Token d'authentification invalide"
+// @Failure 404 {string} string "Utilisateur non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /users/{username}/quizzes [get]
+func (uc *Controller) handleGetProfileQuizzes(ctx *gin.Context) {
+	owner := UseProfile(ctx)
+	reveal := isProfileOwner(ctx)
+
+	all, err := uc.Quizzes.GetAll(ctx.Request.Context(), owner.Id)
+	if err != nil {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+		return
+	}
+
+	// Must always be initialized to avoid a null JSON value.
+	listed := make([]quizzes.PublicQuiz, 0)
+	for _, quiz := range all {
+		if quiz.IsListed() {
+			listed = append(listed, quiz.Public(reveal))
+		}
+	}
+
+	ctx.JSON(http.StatusOK, listed)
+}
+
+// handleGetProfileQuiz retourne un quiz public ou non répertorié d'un utilisateur
+// @Summary Récupérer un quiz partagé
+// @Description Retourne un quiz public ou non répertorié (unlisted). Les bonnes réponses (isCorrect) ne sont incluses que si l'appelant est le propriétaire. L'authentification est facultative.
+// @Tags Users
+// @Produce json
+// @Param Authorization header string false "Token d'authentification Bearer"
+// @Param username path string true "Nom d'utilisateur (insensible à la casse)"
+// @Param quiz-id path string true "ID du quiz"
+// @Success 200 {object} quizzes.PublicQuiz "Quiz partagé"
+// @Failure 401 {string} string "Token d'authentification invalide"
+// @Failure 404 {string} string "Utilisateur ou quiz non trouvé"
+// @Failure 500 {string} string "Erreur interne du serveur"
+// @Failure 504 {string} string "Délai d'attente dépassé"
+// @Router /users/{username}/quizzes/{quiz-id} [get]
+func (uc *Controller) handleGetProfileQuiz(ctx *gin.Context) {
+	owner := UseProfile(ctx)
+
+	quiz, err := uc.Quizzes.Get(ctx.Request.Context(), owner.Id, ctx.Param("quiz-id"))
+	if errors.Is(err, quizzes.ErrNotFound) || (err == nil && !quiz.IsShared()) {
+		// Private quizzes must not be distinguishable from missing ones.
+		ctx.AbortWithStatus(http.StatusNotFound)
+	} else if err != nil {
+		ctx.AbortWithStatus(services.HttpStatusOf(err))
+	} else {
+		ctx.JSON(http.StatusOK, quiz.Public(isProfileOwner(ctx)))
+	}
 }
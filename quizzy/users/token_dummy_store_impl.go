@@ -0,0 +1,74 @@
+package users
+
+import (
+	"context"
+	"time"
+)
+
+type dummyTokenStoreImpl struct {
+	Tokens []AccessToken
+}
+
+func _newDummyTokenStore() *dummyTokenStoreImpl {
+	return &dummyTokenStoreImpl{Tokens: make([]AccessToken, 0)}
+}
+
+func (st *dummyTokenStoreImpl) Create(ctx context.Context, token AccessToken) error {
+	st.Tokens = append(st.Tokens, token)
+	return nil
+}
+
+func (st *dummyTokenStoreImpl) GetByHash(ctx context.Context, hash string) (AccessToken, error) {
+	for _, token := range st.Tokens {
+		if token.Hash == hash {
+			return token, nil
+		}
+	}
+
+	return AccessToken{}, ErrTokenNotFound
+}
+
+func (st *dummyTokenStoreImpl) GetAll(ctx context.Context, ownerId string) ([]AccessToken, error) {
+	arr := make([]AccessToken, 0)
+	for _, token := range st.Tokens {
+		if token.OwnerId == ownerId {
+			arr = append(arr, token)
+		}
+	}
+
+	return arr, nil
+}
+
+func (st *dummyTokenStoreImpl) Delete(ctx context.Context, ownerId string, id string) error {
+	for i, token := range st.Tokens {
+		if token.OwnerId == ownerId && token.Id == id {
+			st.Tokens = append(st.Tokens[:i], st.Tokens[i+1:]...)
+			return nil
+		}
+	}
+
+	return ErrTokenNotFound
+}
+
+func (st *dummyTokenStoreImpl) Touch(ctx context.Context, hash string, at time.Time) error {
+	for i := range st.Tokens {
+		if st.Tokens[i].Hash == hash {
+			st.Tokens[i].LastUsedAt = &at
+			return nil
+		}
+	}
+
+	return ErrTokenNotFound
+}
+
+func (st *dummyTokenStoreImpl) DeleteAll(ctx context.Context, ownerId string) error {
+	kept := make([]AccessToken, 0)
+	for _, token := range st.Tokens {
+		if token.OwnerId != ownerId {
+			kept = append(kept, token)
+		}
+	}
+
+	st.Tokens = kept
+	return nil
+}
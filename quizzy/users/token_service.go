@@ -0,0 +1,48 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"quizzy.app/backend/quizzy/auth"
+	"time"
+)
+
+const (
+	// AccessTokenPrefix identifies personal access tokens among bearer tokens.
+	AccessTokenPrefix = "qpat_"
+	// DefaultTokenLifetime is applied to tokens created without an expiry date.
+	DefaultTokenLifetime = 90 * 24 * time.Hour
+	// MaxTokenLifetime bounds the expiry date a token may be created with.
+	MaxTokenLifetime = 366 * 24 * time.Hour
+	// TouchInterval throttles updates of the last-used timestamp of a token.
+	TouchInterval = time.Minute
+)
+
+var (
+	ErrInvalidScope       = errors.New("invalid access token scope")
+	ErrInvalidExpiry      = errors.New("invalid access token expiry")
+	ErrInvalidTokenName   = errors.New("invalid access token name")
+	ErrAccessTokenExpired = errors.New("access token expired")
+)
+
+type CreateTokenRequest struct {
+	Name   string       `json:"name" binding:"required"`
+	Scopes []auth.Scope `json:"scopes" binding:"required"`
+	// ExpiresAt defaults to DefaultTokenLifetime from now.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+type TokenService interface {
+	// Create mints a new token for the given user, the returned secret is never stored
+	// and can't be retrieved afterward.
+	Create(ctx context.Context, ownerId string, req CreateTokenRequest) (string, AccessToken, error)
+
+	// GetAll returns every token owned by the given user.
+	GetAll(ctx context.Context, ownerId string) ([]AccessToken, error)
+
+	// Revoke deletes the matching token owned by the given user.
+	Revoke(ctx context.Context, ownerId string, id string) error
+
+	// Resolve returns the valid token matching the given secret.
+	Resolve(ctx context.Context, secret string) (AccessToken, error)
+}
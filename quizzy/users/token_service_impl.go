@@ -0,0 +1,101 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+type TokenServiceImpl struct {
+	Store TokenStore
+}
+
+// hashToken returns the stored representation of the given token secret.
+func hashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func (ts *TokenServiceImpl) Create(ctx context.Context, ownerId string, req CreateTokenRequest) (string, AccessToken, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "", AccessToken{}, ErrInvalidTokenName
+	}
+
+	if len(req.Scopes) == 0 {
+		return "", AccessToken{}, ErrInvalidScope
+	}
+	for _, s := range req.Scopes {
+		if !s.IsValid() {
+			return "", AccessToken{}, ErrInvalidScope
+		}
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(DefaultTokenLifetime)
+	if req.ExpiresAt != nil {
+		expiresAt = req.ExpiresAt.UTC()
+	}
+	if !expiresAt.After(now) || expiresAt.Sub(now) > MaxTokenLifetime {
+		return "", AccessToken{}, ErrInvalidExpiry
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", AccessToken{}, err
+	}
+	secret := AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+
+	token := AccessToken{
+		Id:        uuid.New().String(),
+		OwnerId:   ownerId,
+		Hash:      hashToken(secret),
+		Name:      name,
+		Scopes:    req.Scopes,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+
+	if err := ts.Store.Create(ctx, token); err != nil {
+		return "", AccessToken{}, err
+	}
+
+	return secret, token, nil
+}
+
+func (ts *TokenServiceImpl) GetAll(ctx context.Context, ownerId string) ([]AccessToken, error) {
+	return ts.Store.GetAll(ctx, ownerId)
+}
+
+func (ts *TokenServiceImpl) Revoke(ctx context.Context, ownerId string, id string) error {
+	return ts.Store.Delete(ctx, ownerId, id)
+}
+
+func (ts *TokenServiceImpl) Resolve(ctx context.Context, secret string) (AccessToken, error) {
+	hash := hashToken(secret)
+	token, err := ts.Store.GetByHash(ctx, hash)
+	if err != nil {
+		return token, err
+	}
+
+	now := time.Now()
+	if !now.Before(token.ExpiresAt) {
+		return AccessToken{}, ErrAccessTokenExpired
+	}
+
+	// The timestamp is informative, failing to update it must not fail the request.
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= TouchInterval {
+		if err2 := ts.Store.Touch(ctx, hash, now.UTC()); err2 != nil && !errors.Is(err2, ErrTokenNotFound) {
+			slog.WarnContext(ctx, "failed to update access token last use", "token", token.Id, "error", err2)
+		}
+	}
+
+	return token, nil
+}
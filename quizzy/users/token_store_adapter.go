@@ -0,0 +1,44 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"quizzy.app/backend/quizzy/auth"
+	"time"
+)
+
+var (
+	ErrTokenNotFound = errors.New("access token not found")
+)
+
+// AccessToken is a personal access token, only its hash is ever stored.
+type AccessToken struct {
+	Id         string       `firestore:"id" json:"id"`
+	OwnerId    string       `firestore:"ownerId" json:"-"`
+	Hash       string       `firestore:"-" json:"-"`
+	Name       string       `firestore:"name" json:"name"`
+	Scopes     []auth.Scope `firestore:"scopes" json:"scopes"`
+	CreatedAt  time.Time    `firestore:"createdAt" json:"createdAt"`
+	ExpiresAt  time.Time    `firestore:"expiresAt" json:"expiresAt"`
+	LastUsedAt *time.Time   `firestore:"lastUsedAt" json:"lastUsedAt,omitempty"`
+}
+
+type TokenStore interface {
+	// Create stores the given token, identified by its hash.
+	Create(ctx context.Context, token AccessToken) error
+
+	// GetByHash returns the token matching the given hash, otherwise ErrTokenNotFound is returned.
+	GetByHash(ctx context.Context, hash string) (AccessToken, error)
+
+	// GetAll returns every token owned by the given user.
+	GetAll(ctx context.Context, ownerId string) ([]AccessToken, error)
+
+	// Delete removes the matching token of the given user, otherwise ErrTokenNotFound is returned.
+	Delete(ctx context.Context, ownerId string, id string) error
+
+	// DeleteAll removes every token owned by the given user.
+	DeleteAll(ctx context.Context, ownerId string) error
+
+	// Touch updates the last time the token matching the given hash has been used.
+	Touch(ctx context.Context, hash string, at time.Time) error
+}
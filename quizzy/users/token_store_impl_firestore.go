@@ -0,0 +1,136 @@
+package users
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"quizzy.app/backend/quizzy/services"
+	"time"
+)
+
+// tokenFirestore stores tokens in a top-level "tokens" collection keyed by their hash,
+// so authenticating a request is a single document read.
+type tokenFirestore struct {
+	client  *firestore.Client
+	timeout time.Duration
+}
+
+func NewTokenFirestore(client *firestore.Client, timeout time.Duration) TokenStore {
+	return &tokenFirestore{client: client, timeout: timeout}
+}
+
+func (fs *tokenFirestore) Create(ctx context.Context, token AccessToken) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	_, err := fs.client.Collection("tokens").Doc(token.Hash).Create(ctx, token)
+	return err
+}
+
+func (fs *tokenFirestore) GetByHash(ctx context.Context, hash string) (AccessToken, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	doc, err := fs.client.Collection("tokens").Doc(hash).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return AccessToken{}, ErrTokenNotFound
+	} else if err != nil {
+		return AccessToken{}, err
+	}
+
+	return tokenFromDoc(doc)
+}
+
+func (fs *tokenFirestore) GetAll(ctx context.Context, ownerId string) ([]AccessToken, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	docs, err := fs.client.Collection("tokens").
+		Where("ownerId", "==", ownerId).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// Must always be initialized to avoid nil pointer.
+	arr := make([]AccessToken, 0)
+	for _, doc := range docs {
+		token, err2 := tokenFromDoc(doc)
+		if err2 != nil {
+			return nil, err2
+		}
+
+		arr = append(arr, token)
+	}
+
+	return arr, nil
+}
+
+func (fs *tokenFirestore) Delete(ctx context.Context, ownerId string, id string) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	docs, err := fs.client.Collection("tokens").
+		Where("ownerId", "==", ownerId).
+		Where("id", "==", id).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return err
+	}
+
+	if len(docs) == 0 {
+		return ErrTokenNotFound
+	}
+
+	for _, doc := range docs {
+		if _, err2 := doc.Ref.Delete(ctx); err2 != nil {
+			return err2
+		}
+	}
+
+	return nil
+}
+
+func (fs *tokenFirestore) DeleteAll(ctx context.Context, ownerId string) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	docs, err := fs.client.Collection("tokens").
+		Where("ownerId", "==", ownerId).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		if _, err2 := doc.Ref.Delete(ctx); err2 != nil {
+			return err2
+		}
+	}
+
+	return nil
+}
+
+func (fs *tokenFirestore) Touch(ctx context.Context, hash string, at time.Time) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	_, err := fs.client.Collection("tokens").Doc(hash).Update(ctx, []firestore.Update{
+		{Path: "lastUsedAt", Value: at},
+	})
+	return err
+}
+
+func tokenFromDoc(doc *firestore.DocumentSnapshot) (AccessToken, error) {
+	var token AccessToken
+	if err := doc.DataTo(&token); err != nil {
+		return token, err
+	}
+
+	token.Hash = doc.Ref.ID
+	return token, nil
+}
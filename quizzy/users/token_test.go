@@ -0,0 +1,134 @@
+package users
+
+import (
+	"context"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"quizzy.app/backend/quizzy/auth"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenServiceCreateAndResolve(t *testing.T) {
+	store := _newDummyTokenStore()
+	s := &TokenServiceImpl{Store: store}
+
+	secret, token, err := s.Create(context.Background(), "owner", CreateTokenRequest{
+		Name:   "ci",
+		Scopes: []auth.Scope{auth.ScopeQuizzesWrite},
+	})
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(secret, AccessTokenPrefix))
+	assert.NotContains(t, store.Tokens[0].Hash, secret)
+	assert.WithinDuration(t, time.Now().Add(DefaultTokenLifetime), token.ExpiresAt, time.Minute)
+
+	resolved, err2 := s.Resolve(context.Background(), secret)
+	assert.Nil(t, err2)
+	assert.Equal(t, token.Id, resolved.Id)
+	assert.NotNil(t, store.Tokens[0].LastUsedAt)
+
+	_, err3 := s.Resolve(context.Background(), AccessTokenPrefix+"unknown")
+	assert.ErrorIs(t, err3, ErrTokenNotFound)
+
+	assert.Nil(t, s.Revoke(context.Background(), "owner", token.Id))
+	_, err4 := s.Resolve(context.Background(), secret)
+	assert.ErrorIs(t, err4, ErrTokenNotFound)
+}
+
+func TestTokenServiceRejectsInvalidRequests(t *testing.T) {
+	s := &TokenServiceImpl{Store: _newDummyTokenStore()}
+	past := time.Now().Add(-time.Hour)
+	tooFar := time.Now().Add(2 * MaxTokenLifetime)
+
+	_, _, err := s.Create(context.Background(), "owner", CreateTokenRequest{Name: "ci", Scopes: []auth.Scope{"everything"}})
+	assert.ErrorIs(t, err, ErrInvalidScope)
+
+	_, _, err = s.Create(context.Background(), "owner", CreateTokenRequest{Name: "ci", Scopes: []auth.Scope{auth.ScopeUsersRead}, ExpiresAt: &past})
+	assert.ErrorIs(t, err, ErrInvalidExpiry)
+
+	_, _, err = s.Create(context.Background(), "owner", CreateTokenRequest{Name: "ci", Scopes: []auth.Scope{auth.ScopeUsersRead}, ExpiresAt: &tooFar})
+	assert.ErrorIs(t, err, ErrInvalidExpiry)
+
+	_, _, err = s.Create(context.Background(), "owner", CreateTokenRequest{Name: " ", Scopes: []auth.Scope{auth.ScopeUsersRead}})
+	assert.ErrorIs(t, err, ErrInvalidTokenName)
+}
+
+func TestTokenServiceRejectsExpiredTokens(t *testing.T) {
+	store := _newDummyTokenStore()
+	s := &TokenServiceImpl{Store: store}
+
+	secret, _, err := s.Create(context.Background(), "owner", CreateTokenRequest{Name: "ci", Scopes: []auth.Scope{auth.ScopeUsersRead}})
+	assert.Nil(t, err)
+	store.Tokens[0].ExpiresAt = time.Now().Add(-time.Second)
+
+	_, err2 := s.Resolve(context.Background(), secret)
+	assert.ErrorIs(t, err2, ErrAccessTokenExpired)
+}
+
+func TestAccessTokenHttpFlow(t *testing.T) {
+	id := _fakeId()
+	user := User{Id: id.Uid, Username: "ci-user", Email: id.Email}
+
+	eng := gin.Default()
+	con := Controller{
+		Service: &UserServiceImpl{Store: _newDummyStore([]User{user})},
+		Tokens:  &TokenServiceImpl{Store: _newDummyTokenStore()},
+	}
+	rt := eng.Group("", auth.ProvideAuthenticator(con.Authenticator(&auth.DummyAuthenticator{PlaceHolder: id})))
+	con.ConfigureRouting(rt)
+	ex := httpexpect.Default(t, "/")
+
+	created := ex.POST("/users/me/tokens").
+		WithHeader("Authorization", "Bearer x").
+		WithJSON(CreateTokenRequest{Name: "ci", Scopes: []auth.Scope{auth.ScopeUsersRead}}).
+		WithHandler(eng).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object()
+	secret := created.Value("token").String().Raw()
+	tokenId := created.Value("id").String().Raw()
+
+	ex.GET("/users/me").
+		WithHeader("Authorization", "Bearer "+secret).
+		WithHandler(eng).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("uid").IsEqual(id.Uid)
+
+	// Write scope wasn't granted.
+	ex.POST("/users").
+		WithHeader("Authorization", "Bearer "+secret).
+		WithJSON(createUserRequest{Username: "renamed"}).
+		WithHandler(eng).
+		Expect().
+		Status(http.StatusForbidden)
+
+	// Tokens can't manage tokens.
+	ex.GET("/users/me/tokens").
+		WithHeader("Authorization", "Bearer "+secret).
+		WithHandler(eng).
+		Expect().
+		Status(http.StatusForbidden)
+
+	ex.GET("/users/me/tokens").
+		WithHeader("Authorization", "Bearer x").
+		WithHandler(eng).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Length().IsEqual(1)
+
+	ex.DELETE("/users/me/tokens/"+tokenId).
+		WithHeader("Authorization", "Bearer x").
+		WithHandler(eng).
+		Expect().
+		Status(http.StatusNoContent)
+
+	ex.GET("/users/me").
+		WithHeader("Authorization", "Bearer "+secret).
+		WithHandler(eng).
+		Expect().
+		Status(http.StatusUnauthorized)
+}
@@ -1,7 +1,15 @@
 package users
 
+import (
+	"context"
+	"slices"
+)
+
 type dummyUserStoreImpl struct {
-	Users []User
+	Users      []User
+	Tombstones []string
+	// Usernames reserved by ReserveUsername, by key. Upsert checks stored users instead.
+	Reserved map[string]string
 }
 
 func _newDummyStore(placeholder []User) Store {
@@ -16,11 +24,16 @@ func _newDummyStore(placeholder []User) Store {
 	}
 }
 
-func (st *dummyUserStoreImpl) Upsert(user User) error {
+func (st *dummyUserStoreImpl) Upsert(ctx context.Context, user User) error {
+	for _, u := range st.Users {
+		if u.Id != user.Id && user.Username != "" && UsernameKey(u.Username) == UsernameKey(user.Username) {
+			return ErrUsernameTaken
+		}
+	}
+
 	for i, u := range st.Users {
 		if u.Id == user.Id {
-			st.Users[i].Username = user.Username
-			st.Users[i].Email = user.Email
+			st.Users[i] = user
 			return nil
 		}
 	}
@@ -29,7 +42,7 @@ func (st *dummyUserStoreImpl) Upsert(user User) error {
 	return nil
 }
 
-func (st *dummyUserStoreImpl) GetUnique(id string) (User, error) {
+func (st *dummyUserStoreImpl) GetUnique(ctx context.Context, id string) (User, error) {
 	for _, user := range st.Users {
 		if user.Id == id {
 			return user, nil
@@ -38,3 +51,62 @@ func (st *dummyUserStoreImpl) GetUnique(id string) (User, error) {
 
 	return User{}, ErrNotFound
 }
+
+func (st *dummyUserStoreImpl) GetByUsername(ctx context.Context, username string) (User, error) {
+	for _, user := range st.Users {
+		if UsernameKey(user.Username) == UsernameKey(username) {
+			return user, nil
+		}
+	}
+
+	return User{}, ErrNotFound
+}
+
+func (st *dummyUserStoreImpl) GetByEmail(ctx context.Context, email string) (User, error) {
+	for _, user := range st.Users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+
+	return User{}, ErrNotFound
+}
+
+func (st *dummyUserStoreImpl) GetAll(ctx context.Context) ([]User, error) {
+	return st.Users, nil
+}
+
+func (st *dummyUserStoreImpl) Delete(ctx context.Context, id string) error {
+	for i, user := range st.Users {
+		if user.Id == id {
+			st.Users = append(st.Users[:i], st.Users[i+1:]...)
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+func (st *dummyUserStoreImpl) AddTombstone(ctx context.Context, id string) error {
+	if !slices.Contains(st.Tombstones, id) {
+		st.Tombstones = append(st.Tombstones, id)
+	}
+	return nil
+}
+
+func (st *dummyUserStoreImpl) HasTombstone(ctx context.Context, id string) (bool, error) {
+	return slices.Contains(st.Tombstones, id), nil
+}
+
+func (st *dummyUserStoreImpl) ReserveUsername(ctx context.Context, user User) error {
+	key := UsernameKey(user.Username)
+	if owner, ok := st.Reserved[key]; ok && owner != user.Id {
+		return ErrUsernameTaken
+	}
+
+	if st.Reserved == nil {
+		st.Reserved = make(map[string]string)
+	}
+	st.Reserved[key] = user.Id
+	return nil
+}
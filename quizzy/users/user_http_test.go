@@ -1,6 +1,7 @@
 package users
 
 import (
+	"context"
 	"github.com/gavv/httpexpect/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -8,6 +9,7 @@ import (
 	"net/http"
 	"quizzy.app/backend/quizzy/auth"
 	"testing"
+	"time"
 )
 
 func _configureTestHandler(id auth.Identity, users []User) http.HandlerFunc {
@@ -15,8 +17,10 @@ func _configureTestHandler(id auth.Identity, users []User) http.HandlerFunc {
 	rt := eng.Group("", auth.ProvideAuthenticator(&auth.DummyAuthenticator{PlaceHolder: id}))
 	con := Controller{
 		Service: &UserServiceImpl{Store: _newDummyStore(users)},
+		Tokens:  &TokenServiceImpl{Store: _newDummyTokenStore()},
 	}
 	con.ConfigureRouting(rt)
+	con.ConfigureAdminRouting(rt.Group("/admin", auth.RequireAuthenticated, auth.RequireRole(auth.RoleAdmin)))
 
 	return eng.ServeHTTP
 }
@@ -100,3 +104,162 @@ func TestGetUserSelf(t *testing.T) {
 	assert.Equal(t, user.Email, id.Email)
 	assert.Equal(t, user.Username, "dummy-user")
 }
+
+// timeoutUserStore simulates a backend which never answers in time.
+type timeoutUserStore struct{}
+
+func (st *timeoutUserStore) Upsert(ctx context.Context, user User) error {
+	return context.DeadlineExceeded
+}
+
+func (st *timeoutUserStore) GetUnique(ctx context.Context, id string) (User, error) {
+	return User{}, context.DeadlineExceeded
+}
+
+func (st *timeoutUserStore) GetByUsername(ctx context.Context, username string) (User, error) {
+	return User{}, context.DeadlineExceeded
+}
+
+func (st *timeoutUserStore) GetByEmail(ctx context.Context, email string) (User, error) {
+	return User{}, context.DeadlineExceeded
+}
+
+func (st *timeoutUserStore) GetAll(ctx context.Context) ([]User, error) {
+	return nil, context.DeadlineExceeded
+}
+
+func (st *timeoutUserStore) Delete(ctx context.Context, id string) error {
+	return context.DeadlineExceeded
+}
+
+func (st *timeoutUserStore) ReserveUsername(ctx context.Context, user User) error {
+	return context.DeadlineExceeded
+}
+
+func (st *timeoutUserStore) AddTombstone(ctx context.Context, id string) error {
+	return context.DeadlineExceeded
+}
+
+func (st *timeoutUserStore) HasTombstone(ctx context.Context, id string) (bool, error) {
+	return false, context.DeadlineExceeded
+}
+
+func TestGetUserSelfTimeout(t *testing.T) {
+	eng := gin.Default()
+	rt := eng.Group("", auth.ProvideAuthenticator(&auth.DummyAuthenticator{PlaceHolder: _fakeId()}))
+	con := Controller{Service: &UserServiceImpl{Store: &timeoutUserStore{}}}
+	con.ConfigureRouting(rt)
+
+	httpexpect.Default(t, "/").
+		GET("/users/me").
+		WithHeader("Authorization", "Bearer x").
+		WithHandler(eng).
+		Expect().
+		Status(http.StatusGatewayTimeout)
+}
+
+func TestAdminRoutesRequireAdmin(t *testing.T) {
+	id := _fakeId()
+	handler := _configureTestHandler(id, nil)
+
+	httpexpect.Default(t, "/").
+		GET("/admin/users").
+		WithHeader("Authorization", "Bearer x").
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusForbidden)
+}
+
+func TestAdminPutRoles(t *testing.T) {
+	id := _fakeId()
+	id.Roles = []auth.Role{auth.RoleAdmin}
+	target := User{Id: "target", Username: "target", Email: "target@mail.net"}
+	handler := _configureTestHandler(id, []User{target})
+	ex := httpexpect.Default(t, "/")
+
+	ex.PUT("/admin/users/target/roles").
+		WithHeader("Authorization", "Bearer x").
+		WithJSON(map[string]any{"roles": []string{"teacher", "unknown"}}).
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusBadRequest)
+
+	ex.PUT("/admin/users/missing/roles").
+		WithHeader("Authorization", "Bearer x").
+		WithJSON(map[string]any{"roles": []string{"teacher"}}).
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusNotFound)
+
+	ex.PUT("/admin/users/target/roles").
+		WithHeader("Authorization", "Bearer x").
+		WithJSON(map[string]any{"roles": []string{"admin"}}).
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("roles").Array().ContainsOnly("admin")
+
+	ex.GET("/admin/users").
+		WithHeader("Authorization", "Bearer x").
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Length().IsEqual(1)
+}
+
+func TestPatchUserSelf(t *testing.T) {
+	id := _fakeId()
+	created := time.Now().Add(-time.Hour).UTC()
+	handler := _configureTestHandler(id, []User{
+		{Id: id.Uid, Username: "me", Email: id.Email, CreatedAt: created},
+		{Id: "other", Username: "Taken-Name", Email: "other@mail.net"},
+	})
+	ex := httpexpect.Default(t, "/")
+
+	obj := ex.PATCH("/users/me").
+		WithHeader("Authorization", "Bearer x").
+		WithJSON(map[string]any{"displayName": "Me Myself", "locale": "fr-FR", "avatarUrl": "https://cdn.net/me.png"}).
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	obj.Value("username").IsEqual("me")
+	obj.Value("displayName").IsEqual("Me Myself")
+	obj.Value("locale").IsEqual("fr-FR")
+	obj.Value("createdAt").IsEqual(created.Format(time.RFC3339Nano))
+
+	ex.PATCH("/users/me").
+		WithHeader("Authorization", "Bearer x").
+		WithJSON(map[string]any{"username": "taken-name"}).
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusConflict)
+
+	ex.PATCH("/users/me").
+		WithHeader("Authorization", "Bearer x").
+		WithJSON(map[string]any{"avatarUrl": "javascript:alert(1)"}).
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusBadRequest)
+
+	ex.PATCH("/users/me").
+		WithHeader("Authorization", "Bearer x").
+		WithJSON(map[string]any{"username": "New-Me"}).
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("username").IsEqual("New-Me")
+}
+
+func TestPostUserUsernameTaken(t *testing.T) {
+	id := _fakeId()
+	handler := _configureTestHandler(id, []User{{Id: "other", Username: "dummy-user", Email: "other@mail.net"}})
+
+	httpexpect.Default(t, "/").
+		POST("/users").
+		WithHeader("Authorization", "Bearer x").
+		WithJSON(createUserRequest{Username: "DUMMY-USER"}).
+		WithHandler(handler).
+		Expect().
+		Status(http.StatusConflict)
+}
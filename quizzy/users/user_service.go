@@ -1,12 +1,53 @@
 package users
 
+import (
+	"context"
+	"errors"
+	"quizzy.app/backend/quizzy/auth"
+	"quizzy.app/backend/quizzy/quizzes"
+)
+
+var (
+	ErrInvalidProfile = errors.New("invalid user profile")
+)
+
+// PatchUserRequest lists profile fields a user may change, omitted fields are kept as is.
+type PatchUserRequest struct {
+	Username    *string `json:"username,omitempty"`
+	DisplayName *string `json:"displayName,omitempty"`
+	AvatarUrl   *string `json:"avatarUrl,omitempty"`
+	Locale      *string `json:"locale,omitempty"`
+}
+
 type UserService interface {
+	// RoleSource provides roles stored in user documents to the authentication chain.
+	auth.RoleSource
+
+	// UserResolver finds users invited as quiz collaborators.
+	quizzes.UserResolver
+
 	// Get returns the matching User with the given unique id.
-	Get(id string) (User, error)
+	Get(ctx context.Context, id string) (User, error)
 
 	// Update will update the given User.
-	Update(user User) error
+	Update(ctx context.Context, user User) error
 
 	// Create the given User in our application.
-	Create(user User) error
+	// ErrInvalidProfile or ErrUsernameTaken are returned if the username can't be used.
+	Create(ctx context.Context, user User) error
+
+	// Patch applies the given profile changes to the matching User, and returns it.
+	Patch(ctx context.Context, id string, req PatchUserRequest) (User, error)
+
+	// GetByUsername returns the User holding the given username (case-insensitive).
+	GetByUsername(ctx context.Context, username string) (User, error)
+
+	// GetAll returns every registered User.
+	GetAll(ctx context.Context) ([]User, error)
+
+	// IsDeleted reports whether the matching User deleted its account.
+	IsDeleted(ctx context.Context, id string) (bool, error)
+
+	// SetRoles replaces roles granted to the matching User.
+	SetRoles(ctx context.Context, id string, roles []auth.Role) (User, error)
 }
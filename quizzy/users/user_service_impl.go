@@ -1,17 +1,176 @@
 package users
 
+import (
+	"context"
+	"errors"
+	"net/url"
+	"quizzy.app/backend/quizzy/auth"
+	"quizzy.app/backend/quizzy/quizzes"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{3,32}$`)
+	localePattern   = regexp.MustCompile(`^[a-zA-Z]{2,3}([-_][a-zA-Z0-9]{2,8})*$`)
+)
+
+const maxDisplayNameLength = 64
+
+// validateUsername returns ErrInvalidProfile if the given username can't be chosen.
+func validateUsername(username string) error {
+	if !usernamePattern.MatchString(username) {
+		return ErrInvalidProfile
+	}
+
+	return nil
+}
+
+// validateProfile checks other user editable fields, ErrInvalidProfile is returned on the first invalid one.
+func validateProfile(user User) error {
+	if utf8.RuneCountInString(user.DisplayName) > maxDisplayNameLength {
+		return ErrInvalidProfile
+	}
+
+	if user.AvatarUrl != "" {
+		u, err := url.Parse(user.AvatarUrl)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return ErrInvalidProfile
+		}
+	}
+
+	if user.Locale != "" && !localePattern.MatchString(user.Locale) {
+		return ErrInvalidProfile
+	}
+
+	return nil
+}
+
 type UserServiceImpl struct {
 	Store Store
 }
 
-func (us *UserServiceImpl) Create(user User) error {
-	return us.Store.Upsert(user)
+func (us *UserServiceImpl) Create(ctx context.Context, user User) error {
+	if err := validateUsername(user.Username); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	// Roles are only granted by administrators, registering again must keep them.
+	if existing, err := us.Store.GetUnique(ctx, user.Id); err == nil {
+		user.Roles = existing.Roles
+		user.DisplayName = existing.DisplayName
+		user.AvatarUrl = existing.AvatarUrl
+		user.Locale = existing.Locale
+		if !existing.CreatedAt.IsZero() {
+			user.CreatedAt = existing.CreatedAt
+		}
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	return us.Store.Upsert(ctx, user)
+}
+
+func (us *UserServiceImpl) Update(ctx context.Context, user User) error {
+	user.UpdatedAt = time.Now().UTC()
+	return us.Store.Upsert(ctx, user)
+}
+
+func (us *UserServiceImpl) Patch(ctx context.Context, id string, req PatchUserRequest) (User, error) {
+	user, err := us.Store.GetUnique(ctx, id)
+	if err != nil {
+		return user, err
+	}
+
+	// Usernames chosen before validation existed are kept as long as they aren't changed.
+	if req.Username != nil {
+		user.Username = strings.TrimSpace(*req.Username)
+		if err2 := validateUsername(user.Username); err2 != nil {
+			return User{}, err2
+		}
+	}
+	if req.DisplayName != nil {
+		user.DisplayName = strings.TrimSpace(*req.DisplayName)
+	}
+	if req.AvatarUrl != nil {
+		user.AvatarUrl = strings.TrimSpace(*req.AvatarUrl)
+	}
+	if req.Locale != nil {
+		user.Locale = strings.TrimSpace(*req.Locale)
+	}
+
+	if err3 := validateProfile(user); err3 != nil {
+		return User{}, err3
+	}
+
+	user.UpdatedAt = time.Now().UTC()
+	return user, us.Store.Upsert(ctx, user)
+}
+
+func (us *UserServiceImpl) Get(ctx context.Context, id string) (User, error) {
+	return us.Store.GetUnique(ctx, id)
+}
+
+func (us *UserServiceImpl) GetByUsername(ctx context.Context, username string) (User, error) {
+	return us.Store.GetByUsername(ctx, username)
+}
+
+// FindUser looks the user up by email if the given string looks like one, by username otherwise.
+func (us *UserServiceImpl) FindUser(ctx context.Context, emailOrUsername string) (quizzes.UserRef, bool, error) {
+	var user User
+	var err error
+	if strings.Contains(emailOrUsername, "@") {
+		user, err = us.Store.GetByEmail(ctx, strings.TrimSpace(emailOrUsername))
+	} else {
+		user, err = us.Store.GetByUsername(ctx, strings.TrimSpace(emailOrUsername))
+	}
+
+	if errors.Is(err, ErrNotFound) {
+		return quizzes.UserRef{}, false, nil
+	} else if err != nil {
+		return quizzes.UserRef{}, false, err
+	}
+
+	roles := user.Roles
+	if len(roles) == 0 {
+		roles = []auth.Role{auth.DefaultRole}
+	}
+
+	return quizzes.UserRef{Uid: user.Id, Username: user.Username, Roles: roles}, true, nil
+}
+
+func (us *UserServiceImpl) GetAll(ctx context.Context) ([]User, error) {
+	return us.Store.GetAll(ctx)
+}
+
+func (us *UserServiceImpl) SetRoles(ctx context.Context, id string, roles []auth.Role) (User, error) {
+	user, err := us.Store.GetUnique(ctx, id)
+	if err != nil {
+		return user, err
+	}
+
+	user.Roles = roles
+	return user, us.Store.Upsert(ctx, user)
 }
 
-func (us *UserServiceImpl) Update(user User) error {
-	return us.Store.Upsert(user)
+// GetRoles returns roles stored in the user document, an unregistered user has no role.
+// It makes UserServiceImpl usable as an auth.RoleSource.
+func (us *UserServiceImpl) GetRoles(ctx context.Context, uid string) ([]auth.Role, error) {
+	if user, err := us.Store.GetUnique(ctx, uid); errors.Is(err, ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	} else {
+		return user.Roles, nil
+	}
 }
 
-func (us *UserServiceImpl) Get(id string) (User, error) {
-	return us.Store.GetUnique(id)
+func (us *UserServiceImpl) IsDeleted(ctx context.Context, id string) (bool, error) {
+	return us.Store.HasTombstone(ctx, id)
 }
@@ -1,6 +1,7 @@
 package users
 
 import (
+	"context"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -19,12 +20,15 @@ func TestUserServiceCreate(t *testing.T) {
 		Username: "test-user",
 		Email:    "test.user@mail.com",
 	}
-	e := s.Create(expected)
-
+	e := s.Create(context.Background(), expected)
 	assert.Nil(t, e)
-	assert.Equal(t, data[0].Id, expected.Id)
-	assert.Equal(t, data[0].Username, expected.Username)
-	assert.Equal(t, data[0].Email, expected.Email)
+
+	// Appending to an empty slice does not alias it, reading back from the service instead.
+	created, e2 := s.Get(context.Background(), expected.Id)
+	assert.Nil(t, e2)
+	assert.Equal(t, created.Id, expected.Id)
+	assert.Equal(t, created.Username, expected.Username)
+	assert.Equal(t, created.Email, expected.Email)
 }
 
 func TestUserServiceUpdateUsername(t *testing.T) {
@@ -44,9 +48,30 @@ func TestUserServiceUpdateUsername(t *testing.T) {
 		Email:    "test.user@mail.com",
 	}
 
-	e := s.Update(usr)
+	e := s.Update(context.Background(), usr)
 	assert.Nil(t, e)
 	assert.Equal(t, usr.Id, data[0].Id)
 	assert.Equal(t, usr.Username, data[0].Username)
 	assert.Equal(t, usr.Email, data[0].Email)
 }
+
+func TestBackfillUsernames(t *testing.T) {
+	store := _newDummyStore([]User{
+		{Id: "u1", Username: "Bob"},
+		{Id: "u2", Username: "alice"},
+		// Registered before usernames were compared case-insensitively.
+		{Id: "u3", Username: "bob"},
+		{Id: "u4"},
+	})
+
+	n, conflicts, err := BackfillUsernames(context.Background(), store)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []User{{Id: "u3", Username: "bob"}}, conflicts)
+
+	// Running it again is harmless.
+	n, conflicts, err = BackfillUsernames(context.Background(), store)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.Len(t, conflicts, 1)
+}
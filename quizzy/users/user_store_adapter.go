@@ -1,23 +1,112 @@
 package users
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"quizzy.app/backend/quizzy/auth"
+	"strings"
+	"time"
+)
 
 var (
-	ErrNotFound = errors.New("user not found")
+	ErrNotFound      = errors.New("user not found")
+	ErrUsernameTaken = errors.New("username already taken")
 )
 
 type User struct {
-	Id       string `firestore:"-" json:"uid"`
-	Username string `firestore:"username" json:"username"`
-	Email    string `firestore:"email" json:"email"`
+	Id          string      `firestore:"-" json:"uid"`
+	Username    string      `firestore:"username" json:"username"`
+	Email       string      `firestore:"email" json:"email"`
+	DisplayName string      `firestore:"displayName" json:"displayName,omitempty"`
+	AvatarUrl   string      `firestore:"avatarUrl" json:"avatarUrl,omitempty"`
+	Locale      string      `firestore:"locale" json:"locale,omitempty"`
+	Roles       []auth.Role `firestore:"roles" json:"roles,omitempty"`
+	CreatedAt   time.Time   `firestore:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time   `firestore:"updatedAt" json:"updatedAt"`
+}
+
+// PublicUser is a User as seen by anyone, without private data.
+type PublicUser struct {
+	Username    string    `json:"username"`
+	DisplayName string    `json:"displayName,omitempty"`
+	AvatarUrl   string    `json:"avatarUrl,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// Public returns the public profile of the user.
+func (u User) Public() PublicUser {
+	return PublicUser{Username: u.Username, DisplayName: u.DisplayName, AvatarUrl: u.AvatarUrl, CreatedAt: u.CreatedAt}
+}
+
+// UsernameKey returns the key reserving the given username, usernames are unique
+// regardless of their case.
+func UsernameKey(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
 }
 
 type Store interface {
 	// Upsert Store or update the given user, if no user with the given id exists,
 	// it will be created, otherwise it will be updated.
-	Upsert(user User) error
+	// The username is reserved for this user, ErrUsernameTaken is returned if another
+	// user already reserved it (case-insensitive).
+	Upsert(ctx context.Context, user User) error
 
 	// GetUnique returns the user matching to the given uid,
 	// otherwise ErrNotFound is returned.
-	GetUnique(id string) (User, error)
+	GetUnique(ctx context.Context, id string) (User, error)
+
+	// GetByUsername returns the user holding the given username (case-insensitive),
+	// otherwise ErrNotFound is returned.
+	GetByUsername(ctx context.Context, username string) (User, error)
+
+	// GetByEmail returns the user registered with the given email,
+	// otherwise ErrNotFound is returned.
+	GetByEmail(ctx context.Context, email string) (User, error)
+
+	// GetAll returns every registered user.
+	GetAll(ctx context.Context) ([]User, error)
+
+	// Delete removes the matching user and releases its username,
+	// otherwise ErrNotFound is returned.
+	Delete(ctx context.Context, id string) error
+
+	// ReserveUsername reserves the username of the given user, if not reserved yet. Users
+	// stored before usernames were reserved must be backfilled (see BackfillUsernames).
+	// ErrUsernameTaken is returned if another user already reserved it (case-insensitive).
+	ReserveUsername(ctx context.Context, user User) error
+
+	// AddTombstone records the given user as deleted, its tokens still being valid
+	// must never register it again.
+	AddTombstone(ctx context.Context, id string) error
+
+	// HasTombstone reports whether the given user was deleted.
+	HasTombstone(ctx context.Context, id string) (bool, error)
+}
+
+// BackfillUsernames reserves usernames of users stored before usernames were reserved, so
+// they can't be claimed again with a different case. Users whose username is held by another
+// user are returned, they must pick another one.
+func BackfillUsernames(ctx context.Context, store Store) (int, []User, error) {
+	all, err := store.GetAll(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	reserved := 0
+	conflicts := make([]User, 0)
+	for _, user := range all {
+		if UsernameKey(user.Username) == "" {
+			continue
+		}
+
+		if err2 := store.ReserveUsername(ctx, user); errors.Is(err2, ErrUsernameTaken) {
+			conflicts = append(conflicts, user)
+		} else if err2 != nil {
+			return reserved, conflicts, err2
+		} else {
+			reserved++
+		}
+	}
+
+	return reserved, conflicts, nil
 }
@@ -3,42 +3,263 @@ package users
 import (
 	"cloud.google.com/go/firestore"
 	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"quizzy.app/backend/quizzy/services"
 	"strings"
+	"time"
 )
 
 type userFirestore struct {
-	client *firestore.Client
+	client  *firestore.Client
+	timeout time.Duration
 }
 
-func NewFirestore(client *firestore.Client) Store {
-	return &userFirestore{client}
+func NewFirestore(client *firestore.Client, timeout time.Duration) Store {
+	return &userFirestore{client: client, timeout: timeout}
 }
 
-func (fs *userFirestore) Upsert(user User) error {
-	_, err := fs.client.
-		Doc(strings.Join([]string{"users", user.Id}, "/")).
-		Set(context.Background(), user)
-	return err
+// Upsert stores the user and its username reservation ("usernames/{key}") in a single
+// transaction, releasing the previous reservation when the username changed.
+func (fs *userFirestore) Upsert(ctx context.Context, user User) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	userRef := fs.client.Doc(strings.Join([]string{"users", user.Id}, "/"))
+	key := UsernameKey(user.Username)
+
+	return fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		previous := ""
+		if doc, err := tx.Get(userRef); err == nil {
+			if v, err2 := doc.DataAt("username"); err2 == nil {
+				previous, _ = v.(string)
+			}
+		} else if status.Code(err) != codes.NotFound {
+			return err
+		}
+
+		if key != "" {
+			reservation, err := tx.Get(fs.client.Collection("usernames").Doc(key))
+			if err == nil {
+				if owner, _ := reservation.DataAt("uid"); owner != user.Id {
+					return ErrUsernameTaken
+				}
+			} else if status.Code(err) != codes.NotFound {
+				return err
+			}
+		}
+
+		// All reads must be done before any write in a transaction.
+		if prevKey := UsernameKey(previous); prevKey != "" && prevKey != key {
+			if err := tx.Delete(fs.client.Collection("usernames").Doc(prevKey)); err != nil {
+				return err
+			}
+		}
+
+		if key != "" {
+			if err := tx.Set(fs.client.Collection("usernames").Doc(key), map[string]any{"uid": user.Id}); err != nil {
+				return err
+			}
+		}
+
+		return tx.Set(userRef, user)
+	})
 }
 
-func (fs *userFirestore) GetUnique(id string) (User, error) {
+func (fs *userFirestore) GetUnique(ctx context.Context, id string) (User, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
 	doc, err := fs.client.
 		Doc(strings.Join([]string{"users", id}, "/")).
-		Get(context.Background())
+		Get(ctx)
+
+	if status.Code(err) == codes.NotFound {
+		return User{}, ErrNotFound
+	} else if err != nil {
+		return User{}, err
+	}
+
+	var user User
+	if err2 := doc.DataTo(&user); err2 != nil {
+		return user, err2
+	}
 
+	user.Id = doc.Ref.ID
+	return user, nil
+}
+
+// GetByUsername resolves the username through its reservation ("usernames/{key}"), falling
+// back to the username field of users not backfilled yet.
+func (fs *userFirestore) GetByUsername(ctx context.Context, username string) (User, error) {
+	key := UsernameKey(username)
+	if key == "" || strings.Contains(key, "/") {
+		return User{}, ErrNotFound
+	}
+
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	doc, err := fs.client.Collection("usernames").Doc(key).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return fs.getByLegacyUsername(ctx, strings.TrimSpace(username))
+	} else if err != nil {
+		return User{}, err
+	}
+
+	uid, _ := doc.DataAt("uid")
+	if s, ok := uid.(string); ok && s != "" {
+		return fs.GetUnique(ctx, s)
+	}
+
+	return User{}, ErrNotFound
+}
+
+// getByLegacyUsername finds users stored before usernames were reserved, which aren't
+// backfilled yet. Only the exact username matches.
+func (fs *userFirestore) getByLegacyUsername(ctx context.Context, username string) (User, error) {
+	docs, err := fs.client.Collection("users").
+		Where("username", "==", username).
+		Limit(1).
+		Documents(ctx).
+		GetAll()
 	if err != nil {
 		return User{}, err
+	} else if len(docs) == 0 {
+		return User{}, ErrNotFound
 	}
 
-	if !doc.Exists() {
+	var user User
+	if err2 := docs[0].DataTo(&user); err2 != nil {
+		return user, err2
+	}
+
+	user.Id = docs[0].Ref.ID
+	return user, nil
+}
+
+func (fs *userFirestore) ReserveUsername(ctx context.Context, user User) error {
+	key := UsernameKey(user.Username)
+	if key == "" || strings.Contains(key, "/") {
+		return ErrInvalidProfile
+	}
+
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	ref := fs.client.Collection("usernames").Doc(key)
+	return fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		reservation, err := tx.Get(ref)
+		if err == nil {
+			if owner, _ := reservation.DataAt("uid"); owner != user.Id {
+				return ErrUsernameTaken
+			}
+			return nil
+		} else if status.Code(err) != codes.NotFound {
+			return err
+		}
+
+		return tx.Create(ref, map[string]any{"uid": user.Id})
+	})
+}
+
+func (fs *userFirestore) GetByEmail(ctx context.Context, email string) (User, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	docs, err := fs.client.Collection("users").
+		Where("email", "==", email).
+		Limit(1).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return User{}, err
+	} else if len(docs) == 0 {
 		return User{}, ErrNotFound
 	}
 
 	var user User
-	if err2 := doc.DataTo(&user); err2 != nil {
+	if err2 := docs[0].DataTo(&user); err2 != nil {
 		return user, err2
 	}
 
-	user.Id = doc.Ref.ID
+	user.Id = docs[0].Ref.ID
 	return user, nil
 }
+
+func (fs *userFirestore) GetAll(ctx context.Context) ([]User, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	docs, err := fs.client.Collection("users").Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// Must always be initialized to avoid nil pointer.
+	arr := make([]User, 0)
+	for _, doc := range docs {
+		var user User
+		if err2 := doc.DataTo(&user); err2 != nil {
+			return nil, err2
+		}
+
+		user.Id = doc.Ref.ID
+		arr = append(arr, user)
+	}
+
+	return arr, nil
+}
+
+func (fs *userFirestore) Delete(ctx context.Context, id string) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	userRef := fs.client.Doc(strings.Join([]string{"users", id}, "/"))
+
+	return fs.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(userRef)
+		if status.Code(err) == codes.NotFound {
+			return ErrNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if v, err2 := doc.DataAt("username"); err2 == nil {
+			if username, _ := v.(string); UsernameKey(username) != "" {
+				if err3 := tx.Delete(fs.client.Collection("usernames").Doc(UsernameKey(username))); err3 != nil {
+					return err3
+				}
+			}
+		}
+
+		return tx.Delete(userRef)
+	})
+}
+
+// tombstone is stored under "deletedUsers/{uid}" once a user deleted its account.
+type tombstone struct {
+	DeletedAt time.Time `firestore:"deletedAt"`
+}
+
+func (fs *userFirestore) AddTombstone(ctx context.Context, id string) error {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	_, err := fs.client.Collection("deletedUsers").Doc(id).Set(ctx, tombstone{DeletedAt: time.Now().UTC()})
+	return err
+}
+
+func (fs *userFirestore) HasTombstone(ctx context.Context, id string) (bool, error) {
+	ctx, cancel := services.WithTimeout(ctx, fs.timeout)
+	defer cancel()
+
+	_, err := fs.client.Collection("deletedUsers").Doc(id).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}